@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/game/race"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+func main() {
+	theme := flag.String("theme", "clash", "race theme to simulate")
+	numRaces := flag.Int("races", 10000, "number of races to simulate")
+	numRacers := flag.Int("racers", 0, "number of racers in each race (defaults to the maximum for the theme)")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for the simulation, so it may be repeated")
+	flag.Parse()
+
+	godotenv.Load(".env")
+	discord.ConfigDir = os.Getenv("DISCORD_CONFIG_DIR")
+
+	config, err := race.ReadConfigFile(*theme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to read the race config for theme %s: %v\n", *theme, err)
+		os.Exit(1)
+	}
+	config.Theme = *theme
+	if *numRacers == 0 {
+		*numRacers = config.MaxNumRacers
+	}
+
+	results, err := race.SimulateRaces(config, *numRaces, *numRacers, *seed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to simulate races: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	p.Printf("Theme %s, %d races of %d racers, track length %d, seed %d\n\n", *theme, results.NumRaces, results.NumRacers, results.TrackLength, *seed)
+
+	fmt.Println("Speed, Racers, Win, Place, Show, Win Return, Place Return, Show Return")
+	for _, stats := range results.Speeds {
+		printStats(p, stats.Name, stats, config, results.NumRacers)
+	}
+
+	fmt.Println("\nAvatar, Racers, Win, Place, Show, Win Return, Place Return, Show Return")
+	for _, stats := range results.Avatars {
+		printStats(p, avatarName(stats.Name), stats, config, results.NumRacers)
+	}
+
+	p.Printf("\nExpected prize per race: %.0f (%d to %d per racer)\n", results.AveragePrize(), config.MinPrizeAmount, config.MaxPrizeAmount)
+	if config.PariMutuel {
+		p.Printf("Bets are pari-mutuel with a %.2f%% house cut\n", config.HouseCut)
+	} else {
+		p.Printf("Win bets of %d pay %d\n", config.BetAmount, config.BetAmount*results.NumRacers)
+	}
+
+	fmt.Println("\nBet, Payout Multiplier, Expected Payout, House Edge, Best Speed, Best Return")
+	for _, betType := range []race.BetType{race.BetWin, race.BetPlace, race.BetShow, race.BetExacta, race.BetTrifecta} {
+		edge := results.HouseEdge(betType, config)
+		payout := float64(config.BetAmount) * (1 - edge/100)
+		if betType == race.BetExacta || betType == race.BetTrifecta {
+			p.Printf("%s, %.2f, %.0f, %.2f%%, -, -\n", betType, config.PayoutMultiplier(betType), payout, edge)
+			continue
+		}
+		bestSpeed, bestReturn := results.BestReturn(betType, config)
+		p.Printf("%s, %.2f, %.0f, %.2f%%, %s, %.3f\n", betType, config.PayoutMultiplier(betType), payout, edge, bestSpeed, bestReturn)
+	}
+}
+
+// printStats prints the finishing probabilities and returns for an avatar or movement speed.
+func printStats(p *message.Printer, name string, stats *race.SimulationStats, config *race.Config, numRacers int) {
+	p.Printf("%s, %d, %.2f%%, %.2f%%, %.2f%%, %.3f, %.3f, %.3f\n",
+		name,
+		stats.Entries,
+		stats.Probability(race.BetWin),
+		stats.Probability(race.BetPlace),
+		stats.Probability(race.BetShow),
+		stats.Return(race.BetWin, config, numRacers),
+		stats.Return(race.BetPlace, config, numRacers),
+		stats.Return(race.BetShow, config, numRacers),
+	)
+}
+
+// avatarName returns the name of a custom Discord emoji, such as "Minion" for "<:Minion:1346564146463768636>".
+func avatarName(emoji string) string {
+	parts := strings.Split(strings.Trim(emoji, "<>"), ":")
+	if len(parts) == 3 {
+		return parts[1]
+	}
+	return emoji
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/joho/godotenv"
 	"github.com/rbrabson/goblin/database/mongo"
@@ -51,4 +53,23 @@ func main() {
 	p.Printf("Average wins: %.0f (%.2f%%)\n", averages.AverageTotalWins, averages.AverageWinPercentage)
 	p.Printf("Average losses: %.0f (%.2f%%)\n", averages.AverageTotalLosses, averages.AverageLossPercentage)
 	p.Printf("Average return: %.2f%% (%d won from %d bet)\n", averages.AverageReturns, averages.TotalWon, averages.TotalBet)
+
+	machineAverages, err := slots.GetMachinePayoutAverages(guildID)
+	if err != nil {
+		slog.Error("error getting machine payout averages",
+			slog.String("guildID", guildID),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	machines := slices.Sorted(maps.Keys(machineAverages))
+	for _, machine := range machines {
+		averages := machineAverages[machine]
+		totalGames := averages.TotalWins + averages.TotalLosses
+		p.Printf("\n%s\n", machine)
+		p.Printf("Total wins %d (%.2f%%)\n", averages.TotalWins, float64(averages.TotalWins)/float64(totalGames)*100)
+		p.Printf("Total losses %d (%.2f%%)\n", averages.TotalLosses, float64(averages.TotalLosses)/float64(totalGames)*100)
+		p.Printf("Average return: %.2f%% (%d won from %d bet)\n", averages.AverageReturns, averages.TotalWon, averages.TotalBet)
+	}
 }
@@ -24,40 +24,42 @@ func main() {
 	godotenv.Load(".env")
 	discord.ConfigDir = os.Getenv("DISCORD_CONFIG_DIR")
 
-	sm := rslots.NewSlotMachine(
-		rslots.WithLookupTable(slots.GetLookupTable()),
-		rslots.WithPayoutTable(slots.GetPayoutTable()),
-	)
-
-	nymPossibilities := 1
-	for _, reel := range sm.LookupTable {
-		nymPossibilities *= len(reel)
+	payoutTable := slots.PayoutFileName
+	if len(os.Args) > 1 {
+		payoutTable = os.Args[1]
+	}
+	lookupTable := slots.LookupFileName
+	if len(os.Args) > 2 {
+		lookupTable = os.Args[2]
 	}
 
-	// numWins := 0
-	// bets := 0
-	// payouts := 0
-	// for _, symbol1 := range sm.LookupTable[0] {
-	// 	for _, symbol2 := range sm.LookupTable[1] {
-	// 		for _, symbol3 := range sm.LookupTable[2] {
-	// 			bets++
-	// 			payline := []string{symbol1, symbol2, symbol3}
-	// 			payoutAmount, _ := sm.PayoutTable.GetPayoutAmount(1, payline)
-	// 			if payoutAmount > 0 {
-	// 				numWins++
-	// 				payouts += payoutAmount
-	// 			}
-	// 		}
-	// 	}
-	// }
-	// p := message.NewPrinter(language.AmericanEnglish)
-	// p.Printf("Total possibilities: %d, Total wins: %d, Win probability: %.2f%%\n", nymPossibilities, numWins, (float64(numWins)/float64(nymPossibilities))*100)
-	// p.Printf("Total bets: %d, Total payouts: %d, Return to player: %.2f%%, ROI: %.2f%%\n", bets, payouts, (float64(payouts)/float64(bets))*100, (float64(payouts)-float64(bets))/float64(bets)*100)
+	lookup := slots.GetLookupTable(lookupTable)
+	payouts := slots.GetPayoutTable(payoutTable)
+
+	// Each spin is checked using the same function the game uses to spin the reels. A winning spin
+	// is counted against the first line in the payout table that it matches.
+	numPossibilities := 0
+	numMatches := make([]int, len(payouts))
+	for spin := range slots.AllSpins(lookup, payouts, 1) {
+		numPossibilities++
+		if spin.Payout <= 0 {
+			continue
+		}
+		for idx := range payouts {
+			if payouts[idx].GetPayoutAmount(1, spin.Payline) > 0 {
+				numMatches[idx]++
+				break
+			}
+		}
+	}
+	if numPossibilities == 0 {
+		fmt.Println("The lookup table has no spins")
+		return
+	}
 
-	probabilities := make([]*PayoutProbability, 0, len(sm.PayoutTable))
-	for _, payout := range sm.PayoutTable {
-		payoutProbability := getProbabilityOfWin(&payout, sm)
-		probabilities = append(probabilities, payoutProbability)
+	probabilities := make([]*PayoutProbability, 0, len(payouts))
+	for idx, payout := range payouts {
+		probabilities = append(probabilities, getProbabilityOfWin(&payout, numMatches[idx], numPossibilities))
 	}
 
 	totalWinProb := 0.0
@@ -78,28 +80,12 @@ func main() {
 	fmt.Printf("\nWin,,, %.2f%%, %.2f%%\n", totalWinProb, totalReturn)
 }
 
-func getProbabilityOfWin(payout *rslots.PayoutAmount, sm *rslots.SlotMachine) *PayoutProbability {
-	nymPossibilities := 1
-	for _, reel := range sm.LookupTable {
-		nymPossibilities *= len(reel)
-	}
-
-	numMatches := 0
-
-	for _, symbol1 := range sm.LookupTable[0] {
-		for _, symbol2 := range sm.LookupTable[1] {
-			for _, symbol3 := range sm.LookupTable[2] {
-				payoutAmount := payout.GetPayoutAmount(1, []string{symbol1, symbol2, symbol3})
-				if payoutAmount > 0 {
-					numMatches++
-				}
-			}
-		}
-	}
-
+// getProbabilityOfWin returns the probability of a spin paying the line in the payout table, and
+// the return from it, given the number of spins that pay the line.
+func getProbabilityOfWin(payout *rslots.PayoutAmount, numMatches int, numPossibilities int) *PayoutProbability {
 	bet := float64(payout.Bet)
 	winnings := float64(payout.Payout) - bet
-	probability := (float64(numMatches) / float64((nymPossibilities))) * 100
+	probability := (float64(numMatches) / float64(numPossibilities)) * 100
 
 	return &PayoutProbability{
 		Bet:         payout.Bet,
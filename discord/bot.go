@@ -144,6 +144,26 @@ func NewBot(botName string, version string, revision string) *Bot {
 					}
 				}
 			}
+		case discordgo.InteractionModalSubmit:
+			if h, ok := componentHandlers[i.ModalSubmitData().CustomID]; ok {
+				h(s, i)
+			} else {
+				if h, ok := customComponentHandlers[i.ModalSubmitData().CustomID]; ok {
+					h(s, i)
+				} else {
+					slog.Warn("unhandled modal",
+						slog.String("modal", i.ModalSubmitData().CustomID),
+					)
+					resp := disgomsg.NewResponse(
+						disgomsg.WithContent("Unknown form. Please try again."),
+					)
+					if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+						slog.Error("failed to send ephemeral message",
+							slog.Any("error", err),
+						)
+					}
+				}
+			}
 		}
 	})
 	slog.Debug("bot handlers added")
@@ -2,6 +2,8 @@ package blackjack
 
 import (
 	"log/slog"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -9,8 +11,13 @@ import (
 	"github.com/bwmarrin/discordgo"
 	bj "github.com/rbrabson/blackjack"
 	"github.com/rbrabson/cards"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/bank"
 	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/stats"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
 )
 
 var (
@@ -26,6 +33,7 @@ const (
 	WaitingForPlayers
 	StartingRound
 	DealingHands
+	OfferingInsurance
 )
 
 type Action int
@@ -41,23 +49,53 @@ const (
 
 // Game represents a blackjack game for a specific guild.
 type Game struct {
-	guildID          string
-	game             *bj.Game
-	config           *Config
-	state            GameState
-	gameStartTime    time.Time
-	turnChan         chan Action
-	interaction      *discordgo.InteractionCreate
-	message          *discordgo.Message
-	symbols          Symbols
-	joinButton       discordgo.Button
-	hitButton        discordgo.Button
-	standButton      discordgo.Button
-	doubleDownButton discordgo.Button
-	splitButton      discordgo.Button
-	surrenderButton  discordgo.Button
-	uid              string
-	lock             sync.Mutex
+	guildID            string
+	channelID          string
+	game               *bj.Game
+	roundID            bson.ObjectID
+	config             *Config
+	state              GameState
+	gameStartTime      time.Time
+	turnChan           chan Action
+	interaction        *discordgo.InteractionCreate
+	message            *discordgo.Message
+	symbols            Symbols
+	joinButton         discordgo.Button
+	hitButton          discordgo.Button
+	standButton        discordgo.Button
+	doubleDownButton   discordgo.Button
+	splitButton        discordgo.Button
+	surrenderButton    discordgo.Button
+	insuranceButton    discordgo.Button
+	evenMoneyButton    discordgo.Button
+	noInsuranceButton  discordgo.Button
+	uid                string
+	stakes             map[string]int
+	insurance          map[string]int
+	evenMoney          map[string]bool
+	sideBets           map[string]SideBetStakes
+	sideBetResults     map[string][]*SideBetResult
+	coached            map[string]bool
+	coachHints         map[string]string
+	decisions          map[string]int
+	deviations         map[string]int
+	deviatedHands      map[*bj.Hand]bool
+	playerInteractions map[string]*discordgo.Interaction
+	wagers             map[string]int
+	tournament         *Tournament
+	tableState         string
+	lock               sync.Mutex
+}
+
+// SavedGame is the saved state of a blackjack game that has players. It is used to refund the bets
+// of a game that is interrupted by a restart of the bot.
+type SavedGame struct {
+	ID        bson.ObjectID  `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID   string         `json:"guild_id" bson:"guild_id"`
+	UID       string         `json:"uid" bson:"uid"`
+	ChannelID string         `json:"channel_id" bson:"channel_id"`
+	State     GameState      `json:"state" bson:"state"`
+	Wagers    map[string]int `json:"wagers" bson:"wagers"`
 }
 
 // GetGame retrieves the blackjack game for the specified guild.
@@ -72,12 +110,17 @@ func GetGame(guildID string, uid string) *Game {
 		slog.Warn("blackjack game not found", slog.String("guildID", guildID), slog.String("uid", uid))
 		return nil
 	}
-	game.config = config
+	// A tournament's table uses the tournament's bet limits rather than the guild's
+	if game.tournament == nil {
+		game.config = config
+	}
 	return game
 }
 
-// StartGame starts a new blackjack game for the specified guild and member.
-func StartGame(guildID string, memberID string) (*Game, error) {
+// StartGame starts a new blackjack game for the specified guild and member in the given channel. The
+// member bets the given amount on each round, or the table's default bet if the amount is zero, along
+// with any side bets.
+func StartGame(guildID string, channelID string, memberID string, bet int, sideBets SideBetStakes) (*Game, error) {
 	gamesLock.Lock()
 	defer gamesLock.Unlock()
 
@@ -101,9 +144,10 @@ func StartGame(guildID string, memberID string) (*Game, error) {
 	}
 
 	game.config = config
+	game.channelID = channelID
 
 	game.SetState(WaitingForPlayers)
-	if err := game.addPlayer(memberID); err != nil {
+	if err := game.addPlayer(memberID, bet, sideBets); err != nil {
 		game.SetState(NotStarted)
 		return nil, err
 	}
@@ -111,33 +155,59 @@ func StartGame(guildID string, memberID string) (*Game, error) {
 	return game, nil
 }
 
-// newGame creates a new blackjack game for the specified guild.
+// newGame creates a new blackjack game for the specified guild. Unlike the other games, blackjack
+// rounds aren't seeded yet. The shoe is shuffled by the blackjack library from the global random
+// source, and the library has no way to supply another, so seeding the shoe is a follow-up that
+// needs a change to the library.
 func newGame(guildID string, uid string, numDecks int) *Game {
 	game := &Game{
-		guildID:  guildID,
-		uid:      uid,
-		game:     bj.New(numDecks),
-		state:    NotStarted,
-		turnChan: make(chan Action, 5),
-		symbols:  GetSymbols(),
-		lock:     sync.Mutex{},
+		guildID:            guildID,
+		uid:                uid,
+		game:               bj.New(numDecks),
+		state:              NotStarted,
+		turnChan:           make(chan Action, 5),
+		symbols:            GetSymbols(),
+		stakes:             make(map[string]int),
+		insurance:          make(map[string]int),
+		evenMoney:          make(map[string]bool),
+		sideBets:           make(map[string]SideBetStakes),
+		sideBetResults:     make(map[string][]*SideBetResult),
+		coached:            make(map[string]bool),
+		coachHints:         make(map[string]string),
+		decisions:          make(map[string]int),
+		deviations:         make(map[string]int),
+		deviatedHands:      make(map[*bj.Hand]bool),
+		playerInteractions: make(map[string]*discordgo.Interaction),
+		wagers:             make(map[string]int),
+		lock:               sync.Mutex{},
 	}
 	createButtons(game)
 
 	return game
 }
 
-// joinGame allows a player to join the blackjack game if it has not started yet.
-func (g *Game) joinGame(memberID string) error {
+// joinGame allows a player to join the blackjack game if it has not started yet. The player bets the
+// given amount on each round, or the table's default bet if the amount is zero, along with any side
+// bets.
+func (g *Game) joinGame(memberID string, bet int, sideBets SideBetStakes) error {
 	g.Lock()
 	defer g.Unlock()
 
-	return g.addPlayer(memberID)
+	return g.addPlayer(memberID, bet, sideBets)
+}
+
+// setPlayerInteraction saves the interaction used by the player to start or join the game, so they
+// can be sent messages only they can see.
+func (g *Game) setPlayerInteraction(memberID string, interaction *discordgo.Interaction) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.playerInteractions[memberID] = interaction
 }
 
 // addPlayer adds a player to the blackjack game with a chip manager that uses their bank account.
 // If the player already exists, no action is taken.
-func (g *Game) addPlayer(memberID string) error {
+func (g *Game) addPlayer(memberID string, bet int, sideBets SideBetStakes) error {
 	if g.GetPlayer(memberID) != nil {
 		return ErrPlayerAlreadyInGame
 	}
@@ -150,14 +220,31 @@ func (g *Game) addPlayer(memberID string) error {
 	if len(g.game.Players()) >= g.config.MaxPlayers {
 		return ErrGameFull
 	}
+	if bet == 0 {
+		bet = g.config.defaultBet()
+	}
+	if err := g.config.checkBet(bet); err != nil {
+		return err
+	}
+	if err := g.config.SideBets.check(sideBets); err != nil {
+		return err
+	}
 
-	cm := NewChipManager(g, memberID)
+	cm := g.chipManager(memberID)
+	if !cm.HasEnoughChips(bet + sideBets.total()) {
+		return bank.ErrInsufficientFunds
+	}
 	g.game.AddPlayer(memberID, bj.WithChipManager(cm))
 	player := g.GetPlayer(memberID)
-	if err := player.CurrentHand().PlaceBet(g.config.BetAmount); err != nil {
+	if err := player.CurrentHand().PlaceBet(bet); err != nil {
 		g.game.RemovePlayer(memberID)
 		return err
 	}
+	g.stakes[memberID] = bet
+	g.coached[memberID] = GetMember(g.guildID, memberID).Coach
+	if err := g.placeSideBets(cm, memberID, sideBets); err != nil {
+		slog.Error("failed to place blackjack side bets", slog.String("guildID", g.guildID), slog.String("memberID", memberID), slog.Any("error", err))
+	}
 
 	// If this is the first player, set the game start time to wait for additional players.
 	if len(g.game.Players()) == 1 {
@@ -206,9 +293,19 @@ func (g *Game) StartNewRound() error {
 	g.Lock()
 	defer g.Unlock()
 
+	// Reshuffle once the house's cut card is reached. The blackjack library shuffles the shoe with the
+	// global random number generator and doesn't accept a seed, so rounds can't be replayed. Instead,
+	// every card dealt is saved in the round's history.
+	shoe := g.game.Shoe()
+	if shoe.Penetration() >= float64(g.config.HouseRules.Penetration) {
+		slog.Debug("reshuffling blackjack shoe", slog.String("guildID", g.guildID), slog.Float64("penetration", shoe.Penetration()))
+		shoe.Reshuffle()
+	}
+
 	if err := g.game.StartNewRound(); err != nil {
 		return err
 	}
+	g.roundID = bson.NewObjectID()
 	g.SetState(StartingRound)
 
 	return nil
@@ -222,18 +319,26 @@ func (g *Game) EndRound() {
 	g.Lock()
 	defer g.Unlock()
 
-	// Update the member stats
-	for _, player := range g.Players() {
-		slog.Debug("updating member stats for player", slog.String("guildID", g.guildID), slog.String("playerName", player.Name()))
-		member := GetMember(g.guildID, player.Name())
-		member.RoundPlayed(g, player)
+	// Save the round for the players' history
+	if len(g.Players()) > 0 && len(g.Dealer().Hand().Cards()) > 0 {
+		writeRoundRecord(newRoundRecord(g))
 	}
 
-	memberIDs := make([]string, 0, len(g.Players()))
-	for _, player := range g.Players() {
-		memberIDs = append(memberIDs, player.Name())
+	// Update the member stats. Tournament rounds are played with chips rather than credits, so they
+	// aren't included.
+	if g.tournament == nil {
+		for _, player := range g.Players() {
+			slog.Debug("updating member stats for player", slog.String("guildID", g.guildID), slog.String("playerName", player.Name()))
+			member := GetMember(g.guildID, player.Name())
+			member.RoundPlayed(g, player)
+		}
+
+		memberIDs := make([]string, 0, len(g.Players()))
+		for _, player := range g.Players() {
+			memberIDs = append(memberIDs, player.Name())
+		}
+		stats.UpdateGameStats(g.guildID, "blackjack", memberIDs)
 	}
-	stats.UpdateGameStats(g.guildID, "blackjack", memberIDs)
 
 	for _, player := range g.game.Players() {
 		slog.Debug("removing player from blackjack game", slog.String("guildID", g.guildID), slog.String("playerName", player.Name()))
@@ -245,8 +350,23 @@ func (g *Game) EndRound() {
 	}
 	slog.Debug("cleared pending blackjack player actions for new round", slog.String("guildID", g.guildID))
 
+	deleteSavedGame(g.uid)
+	clear(g.stakes)
+	clear(g.insurance)
+	clear(g.evenMoney)
+	clear(g.sideBets)
+	clear(g.sideBetResults)
+	clear(g.coached)
+	clear(g.coachHints)
+	clear(g.decisions)
+	clear(g.deviations)
+	clear(g.deviatedHands)
+	clear(g.playerInteractions)
+	clear(g.wagers)
+
 	g.interaction = nil
 	g.message = nil
+	g.tableState = ""
 	g.SetState(NotStarted)
 
 	if g.config.SinglePlayerMode {
@@ -313,12 +433,31 @@ func (g *Game) DealInitialCards() error {
 	}
 	for _, player := range g.game.Players() {
 		for _, hand := range player.Hands() {
-			hand.SetBet(g.config.BetAmount)
+			hand.SetBet(g.stakes[player.Name()])
 		}
 	}
+	g.settleSideBets()
 	return nil
 }
 
+// Stake returns the amount the player bet on each hand at the start of the round.
+func (g *Game) Stake(memberID string) int {
+	return g.stakes[memberID]
+}
+
+// currency returns the name of what is bet at the table for the given amount, which is chips in a
+// tournament and credits otherwise.
+func (g *Game) currency(amount int) string {
+	name := "credit"
+	if g.tournament != nil {
+		name = "chip"
+	}
+	if amount != 1 {
+		name += "s"
+	}
+	return name
+}
+
 // Dealer returns the dealer of the blackjack game.
 func (g *Game) Dealer() *bj.Dealer {
 	return g.game.Dealer()
@@ -366,7 +505,7 @@ func (g *Game) PlayerDoubleDown(player *bj.Player) error {
 	g.Lock()
 	defer g.Unlock()
 
-	if !player.CurrentHand().CanDoubleDown() {
+	if !g.config.HouseRules.canDoubleDown(player.CurrentHand()) {
 		slog.Error("cannot double down", slog.String("guildID", g.guildID), slog.String("playerName", player.Name()))
 		return ErrCannotDoubleDown
 	}
@@ -395,7 +534,7 @@ func (g *Game) PlayerSplit(player *bj.Player) error {
 	g.Lock()
 	defer g.Unlock()
 
-	if !player.CurrentHand().CanSplit() {
+	if !g.config.HouseRules.canSplit(player, player.CurrentHand()) {
 		slog.Error("cannot split", slog.String("guildID", g.guildID), slog.String("playerName", player.Name()))
 		return ErrCannotSplit
 	}
@@ -413,7 +552,7 @@ func (g *Game) PlayerSurrender(player *bj.Player) error {
 	g.Lock()
 	defer g.Unlock()
 
-	if !player.CurrentHand().CanSurrender() {
+	if !g.config.HouseRules.canSurrender(player.CurrentHand()) {
 		slog.Error("cannot surrender", slog.String("guildID", g.guildID), slog.String("playerName", player.Name()))
 		return ErrCannotSurrender
 	}
@@ -424,6 +563,9 @@ func (g *Game) PlayerSurrender(player *bj.Player) error {
 
 	hand := player.CurrentHand()
 	hand.SetActive(false)
+	// The half of the bet that wasn't returned is lost, so none of the hand's bet is refunded if the
+	// game is interrupted
+	g.settleWager(player.Name(), hand.Bet())
 
 	return nil
 }
@@ -447,7 +589,7 @@ func (g *Game) PlayerActionRequest(memberID string, action Action) error {
 	return nil
 }
 
-// DealerPlay processes the dealer's play according to blackjack rules.
+// DealerPlay processes the dealer's play according to the house rules.
 func (g *Game) DealerPlay() error {
 	g.Lock()
 	defer g.Unlock()
@@ -455,9 +597,15 @@ func (g *Game) DealerPlay() error {
 	if !g.hasNonbustedPlayers() {
 		return ErrAllPlayersBusted
 	}
-	if err := g.game.DealerPlay(); err != nil {
-		return err
+	dealer := g.Dealer()
+	for g.config.HouseRules.dealerShouldHit(dealer.Hand()) {
+		card, err := g.game.Shoe().Draw()
+		if err != nil {
+			return err
+		}
+		dealer.Hit(card)
 	}
+	dealer.Stand()
 
 	return nil
 }
@@ -479,7 +627,118 @@ func (g *Game) PayoutResults() {
 	g.Lock()
 	defer g.Unlock()
 
-	g.game.PayoutResults()
+	// Each bet is saved as settled once it is paid, so only the bets that haven't been paid are
+	// refunded if the bot is restarted part way through
+	g.settleInsurance()
+	for _, player := range g.Players() {
+		for _, hand := range player.Hands() {
+			// Skip hands with no bet or that are already settled, such as surrendered hands
+			if hand.Bet() == 0 || hand.Winnings() != 0 {
+				continue
+			}
+			if g.evenMoney[player.Name()] && hand.IsBlackjack() {
+				hand.WinBet(1.0)
+				g.settleWager(player.Name(), hand.Bet())
+				continue
+			}
+			switch g.EvaluateHand(hand) {
+			case bj.PlayerWin:
+				hand.WinBet(1.0)
+			case bj.PlayerBlackjack:
+				hand.WinBet(g.config.HouseRules.blackjackMultiplier())
+			case bj.Push:
+				hand.PushBet()
+			case bj.DealerWin, bj.DealerBlackjack:
+				hand.LoseBet()
+			}
+			g.settleWager(player.Name(), hand.Bet())
+		}
+	}
+}
+
+// addWager adds a stake to the amount the member has at stake in the current round and saves the
+// game, so the stake may be refunded if the bot is restarted before it is settled. The caller must
+// hold the game's lock.
+func (g *Game) addWager(memberID string, stake int) {
+	g.wagers[memberID] += stake
+	g.save()
+}
+
+// settleWager removes a stake that has been settled, such as a hand that was paid or surrendered, from
+// the amount the member has at stake and saves the game, so the stake isn't refunded if the bot is
+// restarted. The whole stake is removed whatever was paid for it. Tournament chips aren't refunded, so
+// nothing is tracked for them. The caller must hold the game's lock.
+func (g *Game) settleWager(memberID string, stake int) {
+	if g.wagers[memberID] == 0 {
+		return
+	}
+	g.wagers[memberID] = max(g.wagers[memberID]-stake, 0)
+	g.save()
+}
+
+// save saves the state of the game. The caller must hold the game's lock.
+func (g *Game) save() {
+	saved := &SavedGame{
+		GuildID:   g.guildID,
+		UID:       g.uid,
+		ChannelID: g.channelID,
+		State:     g.state,
+		Wagers:    maps.Clone(g.wagers),
+	}
+	writeSavedGame(saved)
+}
+
+// restoreGames refunds the bets of any blackjack game that was being played when the bot was stopped,
+// and sends a message to the channel in which the game was played. This is called when the bot starts.
+func restoreGames() {
+	for _, saved := range readSavedGames() {
+		refundSavedGame(saved)
+		deleteSavedGame(saved.UID)
+	}
+}
+
+// refundSavedGame returns the amount each player had at stake in an interrupted game.
+func refundSavedGame(saved *SavedGame) {
+	memberIDs := slices.Sorted(maps.Keys(saved.Wagers))
+	p := message.NewPrinter(language.AmericanEnglish)
+	var sb strings.Builder
+	for _, memberID := range memberIDs {
+		amount := saved.Wagers[memberID]
+		if amount <= 0 {
+			continue
+		}
+		account := bank.GetAccount(saved.GuildID, memberID)
+		if err := account.Deposit(amount); err != nil {
+			slog.Error("failed to refund blackjack bet",
+				slog.String("guildID", saved.GuildID),
+				slog.String("memberID", memberID),
+				slog.Int("amount", amount),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		sb.WriteString(p.Sprintf("- <@%s>: %d credits\n", memberID, amount))
+	}
+	slog.Info("refunded interrupted blackjack game",
+		slog.String("guildID", saved.GuildID),
+		slog.String("uid", saved.UID),
+		slog.Any("state", saved.State),
+		slog.Int("players", len(saved.Wagers)),
+	)
+
+	if sb.Len() == 0 || saved.ChannelID == "" || bot == nil {
+		return
+	}
+	msg := disgomsg.NewMessage(
+		disgomsg.WithContent("The blackjack game was interrupted and has been cancelled. The following bets have been returned:\n" + sb.String()),
+	)
+	if _, err := msg.Send(bot.Session, saved.ChannelID); err != nil {
+		slog.Error("failed to send blackjack cancellation message",
+			slog.String("guildID", saved.GuildID),
+			slog.String("channelID", saved.ChannelID),
+			slog.Any("error", err),
+		)
+	}
 }
 
 // EvaluateHand evaluates the result of a specific hand for a player.
@@ -540,6 +799,7 @@ func createButtons(game *Game) {
 		CustomID: "blackjack_join" + ":" + game.uid,
 	}
 	bot.AddComponentHandler(game.joinButton.CustomID, blackjackJoin)
+	bot.AddComponentHandler(betModalPrefix+game.uid, blackjackBet)
 
 	game.hitButton = discordgo.Button{
 		Label:    "Hit",
@@ -575,16 +835,43 @@ func createButtons(game *Game) {
 		CustomID: "blackjack_surrender" + ":" + game.uid,
 	}
 	bot.AddComponentHandler(game.surrenderButton.CustomID, blackjackSurrender)
+
+	game.insuranceButton = discordgo.Button{
+		Label:    "Insurance",
+		Style:    discordgo.PrimaryButton,
+		CustomID: "blackjack_insurance" + ":" + game.uid,
+	}
+	bot.AddComponentHandler(game.insuranceButton.CustomID, blackjackInsurance)
+	bot.AddComponentHandler(insuranceModalPrefix+game.uid, blackjackInsuranceBet)
+
+	game.evenMoneyButton = discordgo.Button{
+		Label:    "Even Money",
+		Style:    discordgo.SuccessButton,
+		CustomID: "blackjack_even_money" + ":" + game.uid,
+	}
+	bot.AddComponentHandler(game.evenMoneyButton.CustomID, blackjackEvenMoney)
+
+	game.noInsuranceButton = discordgo.Button{
+		Label:    "No Insurance",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "blackjack_no_insurance" + ":" + game.uid,
+	}
+	bot.AddComponentHandler(game.noInsuranceButton.CustomID, blackjackNoInsurance)
 }
 
 // destroyButtons deregisters the action buttons for the blackjack game.
 func destroyButtons(game *Game) {
 	bot.RemoveComponentHandler(game.joinButton.CustomID)
+	bot.RemoveComponentHandler(betModalPrefix + game.uid)
 	bot.RemoveComponentHandler(game.hitButton.CustomID)
 	bot.RemoveComponentHandler(game.standButton.CustomID)
 	bot.RemoveComponentHandler(game.doubleDownButton.CustomID)
 	bot.RemoveComponentHandler(game.splitButton.CustomID)
 	bot.RemoveComponentHandler(game.surrenderButton.CustomID)
+	bot.RemoveComponentHandler(game.insuranceButton.CustomID)
+	bot.RemoveComponentHandler(insuranceModalPrefix + game.uid)
+	bot.RemoveComponentHandler(game.evenMoneyButton.CustomID)
+	bot.RemoveComponentHandler(game.noInsuranceButton.CustomID)
 }
 
 // getUID generates the unique identifier for the blackjack game based on the guild and member IDs.
@@ -620,6 +907,8 @@ func (s GameState) String() string {
 		return "Starting Round"
 	case DealingHands:
 		return "Dealing Hands"
+	case OfferingInsurance:
+		return "Offering Insurance"
 	default:
 		return "Unknown State"
 	}
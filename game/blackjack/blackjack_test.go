@@ -0,0 +1,137 @@
+package blackjack
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/joho/godotenv"
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/database/mongo"
+	"github.com/rbrabson/goblin/guild"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	testGuildID = "refund-123"
+	testUID     = "refund-123-table"
+)
+
+func init() {
+	err := godotenv.Load("../../.env_test")
+	if err != nil {
+		slog.Error("Error loading .env file")
+		os.Exit(1)
+	}
+	db = mongo.NewDatabase()
+	bank.SetDB(db)
+	guild.SetDB(db)
+}
+
+// newBankTestGame returns a game in which each member has 1,000 credits in the bank before they bet
+// their stake. The members' bets are deducted from their bank accounts.
+func newBankTestGame(t *testing.T, stakes map[string]int) *Game {
+	g := &Game{
+		guildID:   testGuildID,
+		uid:       testUID,
+		game:      bj.New(1),
+		config:    &Config{PayoutPercent: 100, HouseRules: defaultHouseRules()},
+		stakes:    stakes,
+		insurance: make(map[string]int),
+		evenMoney: make(map[string]bool),
+		wagers:    make(map[string]int),
+	}
+	for memberID, stake := range stakes {
+		if err := bank.GetAccount(testGuildID, memberID).SetBalance(1000); err != nil {
+			t.Fatal(err)
+		}
+		g.game.AddPlayer(memberID, bj.WithChipManager(NewChipManager(g, memberID)))
+		if err := g.GetPlayer(memberID).CurrentHand().PlaceBet(stake); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+// deleteBankTestGame removes the saved game, the bank accounts and the guild members used by the test.
+func deleteBankTestGame() {
+	deleteSavedGame(testUID)
+	if err := db.DeleteMany("bank_accounts", bson.M{"guild_id": testGuildID}); err != nil {
+		slog.Error("Error deleting bank accounts", slog.String("guildID", testGuildID), slog.Any("error", err))
+	}
+	if err := db.DeleteMany(guild.MemberCollection, bson.M{"guild_id": testGuildID}); err != nil {
+		slog.Error("Error deleting guild members", slog.String("guildID", testGuildID), slog.Any("error", err))
+	}
+}
+
+// refundTestGame refunds the saved game as if the bot had been restarted, and returns the amount each
+// member's balance changed by.
+func refundTestGame(memberIDs ...string) map[string]int {
+	balances := make(map[string]int, len(memberIDs))
+	for _, memberID := range memberIDs {
+		balances[memberID] = bank.GetAccount(testGuildID, memberID).GetBalance()
+	}
+	for _, saved := range readSavedGames() {
+		if saved.UID == testUID {
+			refundSavedGame(saved)
+		}
+	}
+	for _, memberID := range memberIDs {
+		balances[memberID] = bank.GetAccount(testGuildID, memberID).GetBalance() - balances[memberID]
+	}
+	return balances
+}
+
+func TestRefundSavedGame(t *testing.T) {
+	defer deleteBankTestGame()
+
+	g := newBankTestGame(t, map[string]int{"1": 100, "2": 200})
+	if err := g.chipManager("1").DeductChips(50); err != nil {
+		t.Fatal(err)
+	}
+	g.insurance["1"] = 50
+	for _, card := range []cards.Card{{Rank: cards.Ten, Suit: cards.Spades}, {Rank: cards.Nine, Suit: cards.Hearts}} {
+		g.GetPlayer("1").CurrentHand().AddCard(card)
+	}
+	for _, card := range []cards.Card{{Rank: cards.Ten, Suit: cards.Clubs}, {Rank: cards.Queen, Suit: cards.Hearts}} {
+		g.GetPlayer("2").CurrentHand().AddCard(card)
+	}
+	for _, card := range []cards.Card{{Rank: cards.King, Suit: cards.Spades}, {Rank: cards.Queen, Suit: cards.Diamonds}} {
+		g.Dealer().DealCard(card)
+	}
+
+	// Stop part way through the payouts, once the insurance and the first player's hand are settled
+	g.settleInsurance()
+	hand := g.GetPlayer("1").CurrentHand()
+	hand.LoseBet()
+	g.settleWager("1", hand.Bet())
+
+	refunds := refundTestGame("1", "2")
+	if refunds["1"] != 0 {
+		t.Errorf("expected the settled insurance and hand not to be refunded, got %d", refunds["1"])
+	}
+	if refunds["2"] != 200 {
+		t.Errorf("expected the unsettled bet of 200 to be refunded, got %d", refunds["2"])
+	}
+}
+
+func TestRefundSurrenderedHand(t *testing.T) {
+	defer deleteBankTestGame()
+
+	g := newBankTestGame(t, map[string]int{"1": 100})
+	for _, card := range []cards.Card{{Rank: cards.Ten, Suit: cards.Spades}, {Rank: cards.Six, Suit: cards.Hearts}} {
+		g.GetPlayer("1").CurrentHand().AddCard(card)
+	}
+	if err := g.PlayerSurrender(g.GetPlayer("1")); err != nil {
+		t.Fatal(err)
+	}
+	if balance := bank.GetAccount(testGuildID, "1").GetBalance(); balance != 950 {
+		t.Errorf("expected half the bet to be returned on surrender, got a balance of %d", balance)
+	}
+
+	if refunds := refundTestGame("1"); refunds["1"] != 0 {
+		t.Errorf("expected the half bet lost on surrender not to be refunded, got %d", refunds["1"])
+	}
+}
@@ -3,6 +3,7 @@ package blackjack
 import (
 	"log/slog"
 
+	bj "github.com/rbrabson/blackjack"
 	"github.com/rbrabson/goblin/bank"
 )
 
@@ -35,7 +36,7 @@ func (c *ChipManager) SetChips(amount int) {
 // AddChips adds the specified amount of chips to the player's account.
 func (c *ChipManager) AddChips(amount int) {
 	game := c.game
-	amount = amount * game.config.PayoutPercent / 100
+	amount = game.creditedChips(amount)
 	if amount == 0 {
 		slog.Warn("attempted to add zero blackjack chips to account", slog.String("guildID", c.game.guildID), slog.String("memberID", c.memberID))
 		return
@@ -63,6 +64,7 @@ func (c *ChipManager) DeductChips(amount int) error {
 			slog.Any("error", err))
 		return err
 	}
+	c.game.addWager(c.memberID, amount)
 	slog.Debug("deducted blackjack chips from account", slog.String("guildID", c.game.guildID), slog.String("memberID", c.memberID), slog.Int("amount", amount))
 	return nil
 }
@@ -71,3 +73,89 @@ func (c *ChipManager) DeductChips(amount int) error {
 func (c *ChipManager) HasEnoughChips(amount int) bool {
 	return c.GetChips() >= amount
 }
+
+// creditedChips returns the number of chips credited to a player when the amount is added to their
+// chips. Chips added to a bank account are adjusted by the payout percent, while tournament chips aren't.
+func (g *Game) creditedChips(amount int) int {
+	if g.tournament != nil {
+		return amount
+	}
+	return amount * g.config.PayoutPercent / 100
+}
+
+// handPayout returns the amount the player won or lost on the hand. This is the amount credited to the
+// player for the hand, less the bet, so it includes the payout percent when the chips are added to a
+// bank account.
+func (g *Game) handPayout(hand *bj.Hand) int {
+	return g.creditedChips(hand.Bet()+hand.Winnings()) - hand.Bet()
+}
+
+// chipManager returns the chip manager for the player, which uses their tournament chip stack when the
+// game is part of a tournament and their bank account otherwise.
+func (g *Game) chipManager(memberID string) bj.ChipManager {
+	if g.tournament != nil {
+		return NewTournamentChipManager(g.tournament, memberID)
+	}
+	return NewChipManager(g, memberID)
+}
+
+// TournamentChipManager manages the chips for a blackjack player using their tournament chip stack.
+// The chips aren't backed by a bank account, and winnings aren't adjusted by the payout percent.
+type TournamentChipManager struct {
+	tournament *Tournament
+	memberID   string
+}
+
+// NewTournamentChipManager returns a new TournamentChipManager for the member entered in the tournament.
+func NewTournamentChipManager(tournament *Tournament, memberID string) *TournamentChipManager {
+	return &TournamentChipManager{
+		tournament: tournament,
+		memberID:   memberID,
+	}
+}
+
+// GetChips returns the number of chips in the player's stack.
+func (c *TournamentChipManager) GetChips() int {
+	entrant := c.tournament.getEntrant(c.memberID)
+	if entrant == nil {
+		return 0
+	}
+	return entrant.Chips
+}
+
+// SetChips sets the number of chips in the player's stack.
+func (c *TournamentChipManager) SetChips(amount int) {
+	if entrant := c.tournament.getEntrant(c.memberID); entrant != nil {
+		entrant.Chips = amount
+	}
+}
+
+// AddChips adds the specified amount of chips to the player's stack.
+func (c *TournamentChipManager) AddChips(amount int) {
+	entrant := c.tournament.getEntrant(c.memberID)
+	if entrant == nil {
+		slog.Warn("attempted to add blackjack tournament chips to a member not in the tournament", slog.String("guildID", c.tournament.GuildID), slog.String("memberID", c.memberID))
+		return
+	}
+	entrant.Chips += amount
+	slog.Debug("added blackjack tournament chips", slog.String("guildID", c.tournament.GuildID), slog.String("memberID", c.memberID), slog.Int("amount", amount))
+}
+
+// DeductChips deducts the specified amount of chips from the player's stack.
+func (c *TournamentChipManager) DeductChips(amount int) error {
+	entrant := c.tournament.getEntrant(c.memberID)
+	if entrant == nil {
+		return ErrNotInTournament
+	}
+	if entrant.Chips < amount {
+		return ErrNotEnoughChips
+	}
+	entrant.Chips -= amount
+	slog.Debug("deducted blackjack tournament chips", slog.String("guildID", c.tournament.GuildID), slog.String("memberID", c.memberID), slog.Int("amount", amount))
+	return nil
+}
+
+// HasEnoughChips checks if the player's stack has at least the specified amount of chips.
+func (c *TournamentChipManager) HasEnoughChips(amount int) bool {
+	return c.GetChips() >= amount
+}
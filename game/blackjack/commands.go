@@ -3,6 +3,7 @@ package blackjack
 import (
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -11,14 +12,34 @@ import (
 	"github.com/rbrabson/disgomsg"
 	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/form"
 	"github.com/rbrabson/goblin/internal/format"
+	"github.com/rbrabson/goblin/internal/history"
 	"github.com/rbrabson/goblin/internal/unicode"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
 
+const (
+	betModalPrefix       = "blackjack_bet:"
+	betInputID           = "bet"
+	insuranceModalPrefix = "blackjack_insurance_bet:"
+	insuranceInputID     = "insurance"
+	maxFieldLength       = 1024
+)
+
 var (
+	minBetValue         = 1.0
+	minTournamentRounds = 1.0
+	maxTournamentRounds = 50.0
+	minTournamentFee    = 0.0
+	minTournamentSignup = 1.0
+	minSplitHandsValue  = float64(minSplitHands)
+	maxSplitHandsValue  = float64(maxSplitHands)
+	minPenetrationValue = float64(minPenetration)
+	maxPenetrationValue = float64(maxPenetration)
+
 	componentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
 		"join_blackjack":       blackjackJoin,
 		"hit_blackjack":        blackjackHit,
@@ -26,6 +47,8 @@ var (
 		"doubledown_blackjack": blackjackDoubleDown,
 		"split_blackjack":      blackjackSplit,
 		"surrender_blackjack":  blackjackSurrender,
+		tournamentBetButtonID:  blackjackTournamentBet,
+		tournamentBetModalID:   blackjackTournamentBetSubmit,
 	}
 	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
 		"blackjack":       blackjack,
@@ -41,6 +64,72 @@ var (
 					Name:        "play",
 					Description: "Play the blackjack game.",
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "bet",
+							Description: "The amount to bet on each hand.",
+							Required:    false,
+							MinValue:    &minBetValue,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "perfect-pairs",
+							Description: "The amount to bet on the Perfect Pairs side bet.",
+							Required:    false,
+							MinValue:    &minBetValue,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "twenty-one-plus-three",
+							Description: "The amount to bet on the 21+3 side bet.",
+							Required:    false,
+							MinValue:    &minBetValue,
+						},
+					},
+				},
+				{
+					Name:        "coach",
+					Description: "Shows basic strategy hints during your turn.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "enabled",
+							Description: "Whether to show the hints.",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Name:        "history",
+					Description: "Shows your most recent hands.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						history.CountOption("rounds"),
+					},
+				},
+				{
+					Name:        "tournament",
+					Description: "Enters or follows the blackjack tournament.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "join",
+							Description: "Pays the entry fee and enters you into the blackjack tournament.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "leave",
+							Description: "Removes you from the blackjack tournament before it starts and refunds your entry fee.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "standings",
+							Description: "Shows the format and chip standings for the blackjack tournament.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+					},
 				},
 				{
 					Name:        "stats",
@@ -76,7 +165,7 @@ var (
 						},
 						{
 							Name:        "bet",
-							Description: "Sets the bet amount.",
+							Description: "Sets the default bet amount.",
 							Type:        discordgo.ApplicationCommandOptionSubCommand,
 							Options: []*discordgo.ApplicationCommandOption{
 								{
@@ -87,6 +176,112 @@ var (
 								},
 							},
 						},
+						{
+							Name:        "limits",
+							Description: "Sets the minimum and maximum bets at the table.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min",
+									Description: "The minimum bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The maximum bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+							},
+						},
+						{
+							Name:        "rules",
+							Description: "Sets the house rules for the table.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "soft-17",
+									Description: "Whether the dealer hits or stands on a soft 17.",
+									Required:    false,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Hit", Value: "hit"},
+										{Name: "Stand", Value: "stand"},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "blackjack-payout",
+									Description: "The payout for a blackjack.",
+									Required:    false,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: Payout3To2, Value: Payout3To2},
+										{Name: Payout6To5, Value: Payout6To5},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+									Name:        "double-after-split",
+									Description: "Whether a player may double down after splitting.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max-split-hands",
+									Description: "The most hands a player may split into. Two disallows re-splitting.",
+									Required:    false,
+									MinValue:    &minSplitHandsValue,
+									MaxValue:    maxSplitHandsValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+									Name:        "late-surrender",
+									Description: "Whether a player may surrender after the dealer checks for blackjack.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "penetration",
+									Description: "The percentage of the shoe dealt before it is reshuffled.",
+									Required:    false,
+									MinValue:    &minPenetrationValue,
+									MaxValue:    maxPenetrationValue,
+								},
+							},
+						},
+						{
+							Name:        "side-bets",
+							Description: "Configures a side bet offered at the table.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "bet",
+									Description: "The side bet to configure.",
+									Required:    true,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: sideBetName(PerfectPairs), Value: PerfectPairs},
+										{Name: sideBetName(TwentyOnePlusThree), Value: TwentyOnePlusThree},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionBoolean,
+									Name:        "enabled",
+									Description: "Whether players may place the side bet.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The maximum side bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+							},
+						},
 						{
 							Name:        "payout",
 							Description: "The base payout percentage when winning a game.",
@@ -113,6 +308,135 @@ var (
 								},
 							},
 						},
+						{
+							Name:        "display",
+							Description: "Sets how the blackjack table is shown.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "mode",
+									Description: "Whether cards are shown as emoji or as a rendered table image.",
+									Required:    true,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{
+											Name:  "Emoji",
+											Value: DisplayEmoji,
+										},
+										{
+											Name:  "Image",
+											Value: DisplayImage,
+										},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "theme",
+									Description: "The card art theme used when rendering images.",
+									Required:    false,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "tournament",
+					Description: "Schedules or cancels a blackjack tournament.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "create",
+							Description: "Schedules a blackjack tournament played with chip stacks.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of the tournament, such as \"Friday High Roller\".",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "rounds",
+									Description: "The number of rounds played.",
+									Required:    true,
+									MinValue:    &minTournamentRounds,
+									MaxValue:    maxTournamentRounds,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "entry-fee",
+									Description: "The credits paid to enter, which are added to the prize pool.",
+									Required:    true,
+									MinValue:    &minTournamentFee,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "chips",
+									Description: "The number of chips each player starts with.",
+									Required:    true,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min-bet",
+									Description: "The minimum bet, in chips. Defaults to the table's minimum bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max-bet",
+									Description: "The maximum bet, in chips. Defaults to the table's maximum bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "payouts",
+									Description: "Percent of the prize pool won by each finishing position, such as 50,30,20.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "signup",
+									Description: "The minutes players have to enter before the first round. Defaults to 10.",
+									Required:    false,
+									MinValue:    &minTournamentSignup,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionChannel,
+									Name:        "channel",
+									Description: "The channel where the tournament is played. Defaults to this channel.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "cancel",
+							Description: "Cancels the blackjack tournament and refunds the entry fees.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+					},
+				},
+				{
+					Name:        "history",
+					Description: "Shows a round, the rounds for a member, or the most recent rounds.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "id",
+							Description: "The ID of the round, shown with the round results.",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "The member or member ID.",
+							Required:    false,
+						},
+						history.CountOption("rounds"),
 					},
 				},
 			},
@@ -132,8 +456,13 @@ func blackjackAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	options := i.ApplicationCommandData().Options
-	if options[0].Name == "config" {
+	switch options[0].Name {
+	case "config":
 		config(s, i)
+	case "history":
+		adminHistory(s, i)
+	case "tournament":
+		adminTournament(s, i)
 	}
 }
 
@@ -143,10 +472,18 @@ func config(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch options[0].Name {
 	case "bet":
 		configBetAmount(s, i)
+	case "limits":
+		configBetLimits(s, i)
+	case "rules":
+		configHouseRules(s, i)
+	case "side-bets":
+		configSideBets(s, i)
 	case "payout":
 		configPayoutPercent(s, i)
 	case "single-player":
 		configSinglePlayer(s, i)
+	case "display":
+		configDisplay(s, i)
 	case "info":
 		configInfo(s, i)
 	}
@@ -165,6 +502,124 @@ func configBetAmount(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	slog.Info("blackjack bet amount updated", slog.String("guildID", i.GuildID), slog.Int("betAmount", int(betAmount)))
 }
 
+// configBetLimits sets the minimum and maximum bets for the blackjack game on this server.
+func configBetLimits(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	minBet, maxBet := config.MinBetAmount, config.MaxBetAmount
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "min":
+			minBet = int(option.IntValue())
+		case "max":
+			maxBet = int(option.IntValue())
+		}
+	}
+	if minBet <= 0 || maxBet < minBet {
+		disgomsg.NewResponse(disgomsg.WithContent("The minimum bet must be greater than zero and no larger than the maximum bet.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	config.MinBetAmount = minBet
+	config.MaxBetAmount = maxBet
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Bets are limited to between %d and %d credits", minBet, maxBet))).Send(s, i.Interaction)
+	writeConfig(config)
+	slog.Info("blackjack bet limits updated", slog.String("guildID", i.GuildID), slog.Int("minBetAmount", minBet), slog.Int("maxBetAmount", maxBet))
+}
+
+// configHouseRules sets the house rules for the blackjack game on this server. Rules that aren't
+// provided are left unchanged.
+func configHouseRules(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	rules := config.HouseRules
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "soft-17":
+			rules.DealerHitsSoft17 = option.StringValue() == "hit"
+		case "blackjack-payout":
+			rules.BlackjackPayout = option.StringValue()
+		case "double-after-split":
+			rules.DoubleAfterSplit = option.BoolValue()
+		case "max-split-hands":
+			rules.MaxSplitHands = int(option.IntValue())
+		case "late-surrender":
+			rules.LateSurrender = option.BoolValue()
+		case "penetration":
+			rules.Penetration = int(option.IntValue())
+		}
+	}
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent("House rules updated:\n"+formatHouseRules(rules))).Send(s, i.Interaction)
+	slog.Info("blackjack house rules updated", slog.String("guildID", i.GuildID), slog.Any("houseRules", rules))
+}
+
+// formatHouseRules returns the house rules as a list.
+func formatHouseRules(rules *HouseRules) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	var sb strings.Builder
+	sb.WriteString(p.Sprintf("- Dealer %s on soft 17\n", formatSoft17(rules.DealerHitsSoft17)))
+	sb.WriteString(p.Sprintf("- Blackjack pays %s\n", rules.BlackjackPayout))
+	sb.WriteString(p.Sprintf("- Double after split: %s\n", formatOnOff(rules.DoubleAfterSplit)))
+	sb.WriteString(p.Sprintf("- Split up to %d hands\n", rules.MaxSplitHands))
+	sb.WriteString(p.Sprintf("- Late surrender: %s\n", formatOnOff(rules.LateSurrender)))
+	sb.WriteString(p.Sprintf("- Reshuffle after %d%% of the shoe is dealt", rules.Penetration))
+	return sb.String()
+}
+
+// formatOnOff returns "on" or "off" for a rule that may be enabled or disabled.
+func formatOnOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// configSideBets enables or disables a side bet for the blackjack game on this server, and sets the
+// most that may be bet on it. Settings that aren't provided are left unchanged.
+func configSideBets(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	var sideBet string
+	for _, option := range options {
+		if option.Name == "bet" {
+			sideBet = option.StringValue()
+		}
+	}
+	table := config.SideBets.table(sideBet)
+	for _, option := range options {
+		switch option.Name {
+		case "enabled":
+			table.Enabled = option.BoolValue()
+		case "max":
+			table.MaxBet = int(option.IntValue())
+		}
+	}
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent("Side bet updated:\n"+formatSideBetTable(sideBet, table))).Send(s, i.Interaction)
+	slog.Info("blackjack side bet updated", slog.String("guildID", i.GuildID), slog.String("sideBet", sideBet), slog.Bool("enabled", table.Enabled), slog.Int("maxBet", table.MaxBet))
+}
+
+// formatSideBets returns the side bets offered at the table as a list.
+func formatSideBets(sideBets *SideBets) string {
+	return "- " + formatSideBetTable(PerfectPairs, sideBets.PerfectPairs) + "\n- " + formatSideBetTable(TwentyOnePlusThree, sideBets.TwentyOnePlusThree)
+}
+
+// formatSideBetResults returns the results of the side bets placed by a player.
+func formatSideBetResults(results []*SideBetResult) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Winnings > 0 {
+			lines = append(lines, p.Sprintf("%s (%d): %s, won %d credits", sideBetName(result.SideBet), result.Stake, outcomeName(result.Outcome), result.Winnings))
+		} else {
+			lines = append(lines, p.Sprintf("%s (%d): lost", sideBetName(result.SideBet), result.Stake))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // configPayoutPercent sets the payout percent for the blackjack game on this server.
 func configPayoutPercent(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	config := GetConfig(i.GuildID)
@@ -191,6 +646,38 @@ func configSinglePlayer(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	slog.Info("blackjack single-player mode updated", slog.String("guildID", i.GuildID), slog.Bool("singlePlayerMode", singlePlayer))
 }
 
+// configDisplay sets how the blackjack table is displayed on this server. A card theme must have a
+// directory of card art in the config directory.
+func configDisplay(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	display, theme := config.Display, config.CardTheme
+	themeChanged := false
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	for _, option := range options {
+		switch option.Name {
+		case "mode":
+			display = option.StringValue()
+		case "theme":
+			theme = strings.ToLower(strings.TrimSpace(option.StringValue()))
+			if theme != "" {
+				theme = filepath.Base(theme)
+			}
+			themeChanged = true
+		}
+	}
+	if themeChanged && theme != "" && !cardThemeExists(theme) {
+		p := message.NewPrinter(language.AmericanEnglish)
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("There is no card theme named %q", theme))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	config.Display = display
+	config.CardTheme = theme
+
+	disgomsg.NewResponse(disgomsg.WithContent("Table display set to "+formatDisplay(config))).Send(s, i.Interaction)
+	writeConfig(config)
+	slog.Info("blackjack display updated", slog.String("guildID", i.GuildID), slog.String("display", config.Display), slog.String("cardTheme", config.CardTheme))
+}
+
 // configInfo returns the configuration for the blackjack game on this server.
 func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	config := GetConfig(i.GuildID)
@@ -202,6 +689,16 @@ func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				Value:  fmt.Sprintf("%d", config.BetAmount),
 				Inline: true,
 			},
+			{
+				Name:   "min bet",
+				Value:  fmt.Sprintf("%d", config.MinBetAmount),
+				Inline: true,
+			},
+			{
+				Name:   "max bet",
+				Value:  fmt.Sprintf("%d", config.MaxBetAmount),
+				Inline: true,
+			},
 			{
 				Name:   "payout percent",
 				Value:  fmt.Sprintf("%d", config.PayoutPercent),
@@ -212,6 +709,21 @@ func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				Value:  fmt.Sprintf("%t", config.SinglePlayerMode),
 				Inline: true,
 			},
+			{
+				Name:   "house rules",
+				Value:  formatHouseRules(config.HouseRules),
+				Inline: false,
+			},
+			{
+				Name:   "side bets",
+				Value:  formatSideBets(config.SideBets),
+				Inline: false,
+			},
+			{
+				Name:   "display",
+				Value:  formatDisplay(config),
+				Inline: false,
+			},
 		},
 	}
 
@@ -239,6 +751,12 @@ func blackjack(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch subCommand {
 	case "play":
 		playBlackjack(s, i)
+	case "coach":
+		setCoach(s, i)
+	case "history":
+		blackjackHistory(s, i)
+	case "tournament":
+		blackjackTournament(s, i)
 	case "stats":
 		showStats(s, i)
 	}
@@ -249,13 +767,27 @@ func playBlackjack(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
 
 	slog.Debug("starting blackjack game", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID))
-	game, err := StartGame(i.GuildID, i.Member.User.ID)
+	var bet int
+	var sideBets SideBetStakes
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		switch option.Name {
+		case "bet":
+			bet = int(option.IntValue())
+		case "perfect-pairs":
+			sideBets.PerfectPairs = int(option.IntValue())
+		case "twenty-one-plus-three":
+			sideBets.TwentyOnePlusThree = int(option.IntValue())
+		}
+	}
+
+	game, err := StartGame(i.GuildID, i.ChannelID, i.Member.User.ID, bet, sideBets)
 	if err != nil {
 		slog.Debug("error starting blackjack game", slog.String("guildID", i.GuildID), slog.Any("error", err))
 		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
 		return
 	}
 	defer game.EndRound()
+	game.setPlayerInteraction(i.Member.User.ID, i.Interaction)
 
 	showJoinGame(s, i, game)
 	waitForPlayersToJoin(s, i, game)
@@ -266,10 +798,40 @@ func playBlackjack(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 	showStartingGame(s, i, game)
 
-	playRound(s, i, game)
+	playRound(s, game)
 	slog.Debug("blackjack round completed", slog.String("guildID", i.GuildID))
 }
 
+// playTournamentRound plays a round of the tournament at the tournament's table, with each entrant who
+// can still cover the minimum bet.
+func playTournamentRound(s *discordgo.Session, game *Game, tournament *Tournament) {
+	defer game.EndRound()
+	if err := game.startTournamentRound(tournament); err != nil {
+		slog.Error("error starting blackjack tournament round", slog.String("guildID", game.guildID), slog.String("uid", game.uid), slog.Any("error", err))
+		return
+	}
+	if err := game.StartNewRound(); err != nil {
+		slog.Error("error starting blackjack tournament round", slog.String("guildID", game.guildID), slog.String("uid", game.uid), slog.Any("error", err))
+		return
+	}
+
+	playRound(s, game)
+	slog.Debug("blackjack tournament round completed", slog.String("guildID", game.guildID), slog.Int("round", tournament.RoundsPlayed+1))
+}
+
+// setCoach turns basic strategy hints on or off for the member.
+func setCoach(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	enabled := i.ApplicationCommandData().Options[0].Options[0].BoolValue()
+	GetMember(i.GuildID, i.Member.User.ID).SetCoach(enabled)
+
+	content := "Coach turned off."
+	if enabled {
+		content = "Coach turned on. You'll be shown the basic strategy play during your turn, starting with your next game."
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(content)).SendEphemeral(s, i.Interaction)
+	slog.Info("blackjack coach updated", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Bool("enabled", enabled))
+}
+
 // waitForPlayersToJoin waits for the round to start for the blackjack game.
 func waitForPlayersToJoin(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game) {
 	if game.config.SinglePlayerMode {
@@ -300,14 +862,17 @@ func waitForPlayersToJoin(s *discordgo.Session, i *discordgo.InteractionCreate,
 }
 
 // playRound handles playing a round of blackjack.
-func playRound(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game) {
+func playRound(s *discordgo.Session, game *Game) {
 	game.DealInitialCards()
-	showDeal(s, i, game, false)
+	showDeal(s, game, false)
+	if game.dealerShowsAce() {
+		offerInsurance(s, game)
+	}
 
 	// Check for dealer blackjack and only proceed to player turns if dealer doesn't have blackjack
 	if !game.Dealer().HasBlackjack() {
 		allPlayerTurns(s, game)
-		dealerTurn(s, i, game)
+		dealerTurn(s, game)
 	}
 
 	for _, player := range game.Players() {
@@ -323,6 +888,58 @@ func playRound(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game)
 	showResults(s, game)
 }
 
+// offerInsurance lets the players take insurance, or even money on a blackjack, when the dealer shows an ace.
+// Insurance closes once every player has decided or the player timeout is reached.
+func offerInsurance(s *discordgo.Session, game *Game) {
+	if game.message == nil {
+		return
+	}
+	game.OfferInsurance()
+	defer game.CloseInsurance()
+
+	buttons := []discordgo.MessageComponent{game.insuranceButton}
+	for _, player := range game.Players() {
+		if player.CurrentHand().IsBlackjack() {
+			buttons = append(buttons, game.evenMoneyButton)
+			break
+		}
+	}
+	buttons = append(buttons, game.noInsuranceButton)
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	content := p.Sprintf("The dealer shows an ace. Insurance pays 2 to 1 and closes <t:%d:R>.", time.Now().Add(game.config.PlayerTimeout).Unix())
+	components := []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+	if _, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    game.message.ChannelID,
+		ID:         game.message.ID,
+		Content:    &content,
+		Components: &components,
+	}); err != nil {
+		slog.Error("error offering blackjack insurance", slog.String("guildID", game.guildID), slog.Any("error", err))
+		return
+	}
+
+	deadline := time.Now().Add(game.config.PlayerTimeout)
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if time.Until(deadline) <= 0 || game.AllDecidedInsurance() {
+			break
+		}
+	}
+
+	content = ""
+	components = []discordgo.MessageComponent{}
+	if _, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    game.message.ChannelID,
+		ID:         game.message.ID,
+		Content:    &content,
+		Components: &components,
+	}); err != nil {
+		slog.Error("error closing blackjack insurance", slog.String("guildID", game.guildID), slog.Any("error", err))
+	}
+}
+
 // allPlayerTurns handles the turns for each player in blackjack, until all players have stood or busted.
 func allPlayerTurns(s *discordgo.Session, game *Game) {
 	slog.Debug("starting player turns for blackjack", slog.String("guildID", game.guildID))
@@ -391,6 +1008,9 @@ GetAction:
 		case pa := <-game.turnChan:
 			action = pa
 			slog.Debug("received player action", slog.String("guildID", game.guildID), slog.String("playerName", playerName), slog.Any("action", action))
+			if game.IsCoached(player.Name()) {
+				game.recordDecision(player, currentHand, action)
+			}
 			break GetAction
 		case <-timeout:
 			slog.Debug("player turn timed out, defaulting to Stand", slog.String("guildID", game.guildID), slog.String("playerName", playerName))
@@ -448,12 +1068,12 @@ GetAction:
 }
 
 // dealerTurn handles the dealer's turn in blackjack.
-func dealerTurn(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game) {
+func dealerTurn(s *discordgo.Session, game *Game) {
 	slog.Debug("starting dealer turn", slog.String("guildID", game.guildID))
 	defer slog.Debug("finished dealer turn", slog.String("guildID", game.guildID))
 
 	game.DealerPlay()
-	showDeal(s, i, game, true)
+	showDeal(s, game, true)
 	time.Sleep(game.config.ShowDealerTurn)
 }
 
@@ -482,14 +1102,19 @@ func showJoinGame(s *discordgo.Session, i *discordgo.InteractionCreate, game *Ga
 	playerNames := make([]string, 0, len(game.Players()))
 	for _, player := range game.Players() {
 		member := guild.GetMember(game.guildID, player.Name())
-		playerNames = append(playerNames, member.Name)
+		playerNames = append(playerNames, p.Sprintf("%s (%d)", member.Name, game.Stake(player.Name())))
+	}
+
+	description := p.Sprintf("A new blackjack game is starting. You can join the game for a cost of %d credits at any time prior to the game starting.", game.config.MinBetAmount)
+	if game.config.MinBetAmount != game.config.MaxBetAmount {
+		description = p.Sprintf("A new blackjack game is starting. You can join the game with a bet of %d to %d credits at any time prior to the game starting.", game.config.MinBetAmount, game.config.MaxBetAmount)
 	}
 
 	embeds := []*discordgo.MessageEmbed{
 		{
 			Type:        discordgo.EmbedTypeRich,
-			Title:       game.symbols["Cards"]["Multiple"] + "Blackjack" + game.symbols["Cards"]["Multiple"],
-			Description: p.Sprintf("A new blackjack game is starting. You can join the game for a cost of %d credits at any time prior to the game starting.", game.config.BetAmount),
+			Title:       game.title("Blackjack"),
+			Description: description,
 			Fields: []*discordgo.MessageEmbedField{
 				{
 					Name:   "Status",
@@ -546,7 +1171,7 @@ func showStartingGame(s *discordgo.Session, i *discordgo.InteractionCreate, game
 	embeds := []*discordgo.MessageEmbed{
 		{
 			Type:        discordgo.EmbedTypeRich,
-			Title:       game.symbols["Cards"]["Multiple"] + "Blackjack" + game.symbols["Cards"]["Multiple"],
+			Title:       game.title("Blackjack"),
 			Description: "The round is starting! The dealer is dealing the hands to the players.",
 			Fields: []*discordgo.MessageEmbedField{
 				{
@@ -567,7 +1192,7 @@ func showStartingGame(s *discordgo.Session, i *discordgo.InteractionCreate, game
 		}); err != nil {
 			slog.Error("error sending blackjack interaction response",
 				slog.String("guildID", game.guildID),
-				slog.String("memberID", game.interaction.Member.User.ID),
+				slog.String("uid", game.uid),
 				slog.Any("error", err),
 			)
 		}
@@ -579,7 +1204,7 @@ func showStartingGame(s *discordgo.Session, i *discordgo.InteractionCreate, game
 		}); err != nil {
 			slog.Error("error editing blackjack interaction response",
 				slog.String("guildID", game.guildID),
-				slog.String("memberID", game.interaction.Member.User.ID),
+				slog.String("uid", game.uid),
 				slog.Any("error", err),
 			)
 		}
@@ -587,21 +1212,21 @@ func showStartingGame(s *discordgo.Session, i *discordgo.InteractionCreate, game
 }
 
 // showDeal displays the deal information for the blackjack game.
-func showDeal(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game, isDealerTurn bool) {
+func showDeal(s *discordgo.Session, game *Game, isDealerTurn bool) {
 	embeds := make([]*discordgo.MessageEmbed, 0, len(game.Players())+1)
 
 	var title string
 	if game.message == nil {
-		title = game.symbols["Cards"]["Multiple"] + "Blackjack - Deal" + game.symbols["Cards"]["Multiple"]
+		title = game.title("Blackjack - Deal")
 	} else {
-		title = game.symbols["Cards"]["Multiple"] + "Blackjack - Dealer's Turn" + game.symbols["Cards"]["Multiple"]
+		title = game.title("Blackjack - Dealer's Turn")
 	}
 
 	dealerHand := game.Dealer().Hand()
 	embeds = append(embeds, &discordgo.MessageEmbed{
 		Type:        discordgo.EmbedTypeRich,
 		Title:       title,
-		Description: fmt.Sprintf("**Dealer Hand**:\n%s\nValue: %s", game.symbols.GetHandWithoutValue(dealerHand, !isDealerTurn), GetHandValue(dealerHand, !isDealerTurn)),
+		Description: fmt.Sprintf("**Dealer Hand**:\n%s\nValue: %s", game.handCards(dealerHand, !isDealerTurn), GetHandValue(dealerHand, !isDealerTurn)),
 	})
 
 	for _, player := range game.Players() {
@@ -614,21 +1239,30 @@ func showDeal(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game,
 		for idx, hand := range player.Hands() {
 			handField := &discordgo.MessageEmbedField{
 				Name:   fmt.Sprintf("Hand %d", idx+1),
-				Value:  fmt.Sprintf("%s\nValue: %s", game.symbols.GetHandWithoutValue(hand, false), GetHandValue(hand, false)),
+				Value:  fmt.Sprintf("%s\nValue: %s", game.handCards(hand, false), GetHandValue(hand, false)),
 				Inline: false,
 			}
 			playerEmbed.Fields = append(playerEmbed.Fields, handField)
 		}
+		if results := game.SideBetResults(player.Name()); len(results) > 0 {
+			playerEmbed.Fields = append(playerEmbed.Fields, &discordgo.MessageEmbedField{
+				Name:  "Side Bets",
+				Value: formatSideBetResults(results),
+			})
+		}
 		embeds = append(embeds, playerEmbed)
 	}
+	files := game.tableImage(embeds, !isDealerTurn, nil)
 
 	if game.message != nil {
-		m, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		edit := &discordgo.MessageEdit{
 			Channel:    game.message.ChannelID,
 			ID:         game.message.ID,
 			Embeds:     &embeds,
 			Components: &[]discordgo.MessageComponent{},
-		})
+		}
+		replaceTableImage(edit, files)
+		m, err := s.ChannelMessageEditComplex(edit)
 		if err != nil {
 			slog.Error("error editing blackjack deal message",
 				slog.String("guildID", game.guildID),
@@ -638,8 +1272,9 @@ func showDeal(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game,
 		}
 		game.message = m
 	} else {
-		m, err := s.ChannelMessageSendComplex(i.ChannelID, &discordgo.MessageSend{
+		m, err := s.ChannelMessageSendComplex(game.channelID, &discordgo.MessageSend{
 			Embeds: embeds,
+			Files:  files,
 		})
 		if err != nil {
 			slog.Error("error sending blackjack deal message",
@@ -652,6 +1287,16 @@ func showDeal(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game,
 	}
 }
 
+// replaceTableImage replaces the image of the table attached to the message being edited, if a new image
+// was drawn.
+func replaceTableImage(edit *discordgo.MessageEdit, files []*discordgo.File) {
+	if len(files) == 0 {
+		return
+	}
+	edit.Files = files
+	edit.Attachments = &[]*discordgo.MessageAttachment{}
+}
+
 // showCurrentTurn displays the current turn information for the active player.
 func showCurrentTurn(s *discordgo.Session, game *Game, currentPlayer *bj.Player, currentHand *bj.Hand, currentHandIndex int, waitTime time.Duration) {
 	if game == nil || game.message == nil {
@@ -663,8 +1308,8 @@ func showCurrentTurn(s *discordgo.Session, game *Game, currentPlayer *bj.Player,
 	dealerHand := game.Dealer().Hand()
 	embeds = append(embeds, &discordgo.MessageEmbed{
 		Type:        discordgo.EmbedTypeRich,
-		Title:       game.symbols["Cards"]["Multiple"] + "Blackjack - Player Turn" + game.symbols["Cards"]["Multiple"],
-		Description: fmt.Sprintf("**Dealer Hand**:\n%s\nValue: %s", game.symbols.GetHandWithoutValue(dealerHand, true), GetHandValue(dealerHand, true)),
+		Title:       game.title("Blackjack - Player Turn"),
+		Description: fmt.Sprintf("**Dealer Hand**:\n%s\nValue: %s", game.handCards(dealerHand, true), GetHandValue(dealerHand, true)),
 	})
 
 	for _, player := range game.Players() {
@@ -684,7 +1329,7 @@ func showCurrentTurn(s *discordgo.Session, game *Game, currentPlayer *bj.Player,
 			}
 			handField := &discordgo.MessageEmbedField{
 				Name:   fmt.Sprintf("Hand %d", idx+1),
-				Value:  fmt.Sprintf("%s\nValue: %s", game.symbols.GetHandWithoutValue(hand, false), GetHandValue(hand, false)),
+				Value:  fmt.Sprintf("%s\nValue: %s", game.handCards(hand, false), GetHandValue(hand, false)),
 				Inline: false,
 			}
 			playerEmbed.Fields = append(playerEmbed.Fields, handField)
@@ -697,13 +1342,13 @@ func showCurrentTurn(s *discordgo.Session, game *Game, currentPlayer *bj.Player,
 	// Player actions for current hand.
 	if currentHand.IsActive() && !currentHand.IsBusted() && !currentHand.IsBlackjack() {
 		buttons = append(buttons, game.hitButton, game.standButton)
-		if currentHand.CanDoubleDown() {
+		if game.config.HouseRules.canDoubleDown(currentHand) {
 			buttons = append(buttons, game.doubleDownButton)
 		}
-		if currentHand.CanSplit() {
+		if game.config.HouseRules.canSplit(currentPlayer, currentHand) {
 			buttons = append(buttons, game.splitButton)
 		}
-		if currentHand.CanSurrender() {
+		if game.config.HouseRules.canSurrender(currentHand) {
 			buttons = append(buttons, game.surrenderButton)
 		}
 	}
@@ -718,7 +1363,7 @@ func showCurrentTurn(s *discordgo.Session, game *Game, currentPlayer *bj.Player,
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   fmt.Sprintf("Hand %d", currentHandIndex+1),
-				Value:  game.symbols.GetHandWithoutValue(currentHand, false),
+				Value:  game.handCards(currentHand, false),
 				Inline: false,
 			},
 			{
@@ -738,36 +1383,55 @@ func showCurrentTurn(s *discordgo.Session, game *Game, currentPlayer *bj.Player,
 		}
 	}
 	embeds = append(embeds, embed)
+	files := game.tableImage(embeds, true, currentHand)
 
-	var m *discordgo.Message
-	var err error
-
-	if len(buttons) == 0 {
-		m, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-			Channel:    game.message.ChannelID,
-			ID:         game.message.ID,
-			Embeds:     &embeds,
-			Components: &[]discordgo.MessageComponent{},
-		})
-	} else {
-		m, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-			Channel: game.message.ChannelID,
-			ID:      game.message.ID,
-			Embeds:  &embeds,
-			Components: &[]discordgo.MessageComponent{
-				discordgo.ActionsRow{Components: buttons},
-			},
-		})
+	edit := &discordgo.MessageEdit{
+		Channel:    game.message.ChannelID,
+		ID:         game.message.ID,
+		Embeds:     &embeds,
+		Components: &[]discordgo.MessageComponent{},
 	}
+	if len(buttons) > 0 {
+		edit.Components = &[]discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: buttons},
+		}
+	}
+	replaceTableImage(edit, files)
+	m, err := s.ChannelMessageEditComplex(edit)
 	if err != nil {
 		slog.Error("error editing blackjack turn message",
 			slog.String("guildID", game.guildID),
-			slog.String("memberID", game.interaction.Member.User.ID),
+			slog.String("uid", game.uid),
 			slog.Any("error", err),
 		)
 		return
 	}
 	game.message = m
+
+	if len(buttons) > 0 && game.IsCoached(currentPlayer.Name()) {
+		showCoachHint(s, game, currentPlayer, currentHand, currentHandIndex)
+	}
+}
+
+// showCoachHint sends a coached player the basic strategy play for their hand, in a message only they
+// can see. The hint is sent once for each decision, even though the turn is shown again every second.
+func showCoachHint(s *discordgo.Session, game *Game, player *bj.Player, hand *bj.Hand, handIndex int) {
+	memberID := player.Name()
+	decision := fmt.Sprintf("%d:%d", handIndex, len(hand.Cards()))
+	interaction := game.playerInteractions[memberID]
+	if interaction == nil || game.coachHints[memberID] == decision {
+		return
+	}
+	game.coachHints[memberID] = decision
+
+	hint := coachHint(game.Recommend(player, hand), hand, game.Dealer().Hand().Cards()[1])
+	_, err := s.FollowupMessageCreate(interaction, false, &discordgo.WebhookParams{
+		Content: hint,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		slog.Error("error sending blackjack coach hint", slog.String("guildID", game.guildID), slog.String("memberID", memberID), slog.Any("error", err))
+	}
 }
 
 // showResults displays the results of the blackjack round for each player.
@@ -778,8 +1442,11 @@ func showResults(s *discordgo.Session, game *Game) {
 	dealerHand := game.Dealer().Hand()
 	embeds = append(embeds, &discordgo.MessageEmbed{
 		Type:        discordgo.EmbedTypeRich,
-		Title:       game.symbols["Cards"]["Multiple"] + "Blackjack - Results" + game.symbols["Cards"]["Multiple"],
-		Description: fmt.Sprintf("**Dealer Hand**:\n%s\nValue: %s", game.symbols.GetHandWithoutValue(dealerHand, false), GetHandValue(dealerHand, false)),
+		Title:       game.title("Blackjack - Results"),
+		Description: fmt.Sprintf("**Dealer Hand**:\n%s\nValue: %s", game.handCards(dealerHand, false), GetHandValue(dealerHand, false)),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Round ID: " + game.roundID.Hex(),
+		},
 	})
 	for _, player := range game.Players() {
 		embed := &discordgo.MessageEmbed{
@@ -789,53 +1456,67 @@ func showResults(s *discordgo.Session, game *Game) {
 
 		for idx, hand := range player.Hands() {
 			var result string
-			winnings := hand.Winnings()
+			payout := game.handPayout(hand)
 			switch {
-			case winnings > 0:
-				winnings = winnings * game.config.PayoutPercent / 100
-				if winnings == 1 {
-					result = "Won 1 credit"
-				} else {
-					result = p.Sprintf("Won %d credits", winnings)
-				}
-			case winnings < 0:
-				if winnings == -1 {
-					result = "Lost 1 credit"
-				} else {
-					result = p.Sprintf("Lost %d credits", -winnings)
-
-				}
+			case hand.Winnings() > 0:
+				result = p.Sprintf("Won %d %s", payout, game.currency(payout))
+			case hand.Winnings() < 0:
+				result = p.Sprintf("Lost %d %s", -payout, game.currency(-payout))
 			default:
 				result = "Push"
 			}
+			if game.TookEvenMoney(player.Name()) && hand.IsBlackjack() {
+				result += " (even money)"
+			}
 			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 				Name:  p.Sprintf("Hand %d", idx+1),
-				Value: fmt.Sprintf("%s\nValue: %s\n%s", game.symbols.GetHandWithoutValue(hand, false), GetHandValue(hand, false), result),
+				Value: p.Sprintf("%s\nValue: %s\nBet: %d\n%s", game.handCards(hand, false), GetHandValue(hand, false), hand.Bet(), result),
 
 				Inline: false,
 			})
 		}
 
+		if insurance := game.Insurance(player.Name()); insurance > 0 {
+			result := p.Sprintf("Lost %d %s", insurance, game.currency(insurance))
+			if won := game.InsuranceWinnings(player.Name()); won > 0 {
+				result = p.Sprintf("Won %d %s", won, game.currency(won))
+			}
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  "Insurance",
+				Value: p.Sprintf("Bet: %d\n%s", insurance, result),
+			})
+		}
+		if results := game.SideBetResults(player.Name()); len(results) > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  "Side Bets",
+				Value: formatSideBetResults(results),
+			})
+		}
+
 		embeds = append(embeds, embed)
 	}
+	files := game.tableImage(embeds, false, nil)
 
-	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+	edit := &discordgo.MessageEdit{
 		Channel:    game.message.ChannelID,
 		ID:         game.message.ID,
 		Embeds:     &embeds,
 		Components: &[]discordgo.MessageComponent{},
-	})
+	}
+	replaceTableImage(edit, files)
+	_, err := s.ChannelMessageEditComplex(edit)
 	if err != nil {
 		slog.Error("error sending blackjack result message",
 			slog.String("guildID", game.guildID),
-			slog.String("memberID", game.interaction.Member.User.ID),
+			slog.String("uid", game.uid),
 			slog.Any("error", err),
 		)
 		return
 	}
 }
 
-// blackjackJoin handles the /blackjack/join command.
+// blackjackJoin handles the /blackjack/join command. If the table allows more than one bet amount or
+// offers side bets, the member is sent a form used to choose their bets.
 func blackjackJoin(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	uid := getUIDFromInteraction(i)
 	game := GetGame(i.GuildID, uid)
@@ -844,11 +1525,86 @@ func blackjackJoin(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	if err := game.joinGame(i.Member.User.ID); err != nil {
+	if game.config.MinBetAmount == game.config.MaxBetAmount && !game.config.SideBets.anyEnabled() {
+		joinWithBet(s, i, game, game.config.MinBetAmount, SideBetStakes{})
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID:  betInputID,
+				Label:     p.Sprintf("Bet (%d to %d credits)", game.config.MinBetAmount, game.config.MaxBetAmount),
+				Style:     discordgo.TextInputShort,
+				Value:     p.Sprintf("%d", game.config.defaultBet()),
+				Required:  true,
+				MaxLength: 12,
+			},
+		}},
+	}
+	for _, sideBet := range []string{PerfectPairs, TwentyOnePlusThree} {
+		table := game.config.SideBets.table(sideBet)
+		if !table.Enabled {
+			continue
+		}
+		components = append(components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.TextInput{
+				CustomID:  sideBet,
+				Label:     p.Sprintf("%s side bet (up to %d credits)", sideBetName(sideBet), table.MaxBet),
+				Style:     discordgo.TextInputShort,
+				Required:  false,
+				MaxLength: 12,
+			},
+		}})
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID:   betModalPrefix + uid,
+			Title:      "Join Blackjack",
+			Components: components,
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the blackjack bet form", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+	}
+}
+
+// blackjackBet handles the bet entered by a member joining the blackjack game.
+func blackjackBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	game := GetGame(i.GuildID, strings.TrimPrefix(data.CustomID, betModalPrefix))
+	if game == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No active blackjack game found to join.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	bet, err := parseBet(form.TextInputValue(data.Components, betInputID), game.config)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	var sideBets SideBetStakes
+	if sideBets.PerfectPairs, err = parseSideBet(form.TextInputValue(data.Components, PerfectPairs)); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if sideBets.TwentyOnePlusThree, err = parseSideBet(form.TextInputValue(data.Components, TwentyOnePlusThree)); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	joinWithBet(s, i, game, bet, sideBets)
+}
+
+// joinWithBet adds the member to the blackjack game with the given bet and side bets.
+func joinWithBet(s *discordgo.Session, i *discordgo.InteractionCreate, game *Game, bet int, sideBets SideBetStakes) {
+	if err := game.joinGame(i.Member.User.ID, bet, sideBets); err != nil {
 		slog.Error("error adding player to blackjack game", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
 		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
 		return
 	}
+	game.setPlayerInteraction(i.Member.User.ID, i.Interaction)
 	disgomsg.NewResponse(disgomsg.WithContent("You have joined the game.")).SendEphemeral(s, i.Interaction)
 	showJoinGame(s, i, game)
 }
@@ -949,6 +1705,102 @@ func blackjackSurrender(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	})
 }
 
+// blackjackInsurance sends the player a form used to choose their insurance bet.
+func blackjackInsurance(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	uid := getUIDFromInteraction(i)
+	game := GetGame(i.GuildID, uid)
+	if game == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No active blackjack game found.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if !game.IsOfferingInsurance() {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrInsuranceClosed.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	maxInsurance := game.MaxInsurance(i.Member.User.ID)
+	if maxInsurance == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent("You are not able to take insurance in this game.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: insuranceModalPrefix + uid,
+			Title:    "Insurance",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:  insuranceInputID,
+						Label:     p.Sprintf("Insurance bet (1 to %d credits)", maxInsurance),
+						Style:     discordgo.TextInputShort,
+						Value:     p.Sprintf("%d", maxInsurance),
+						Required:  true,
+						MaxLength: 12,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the blackjack insurance form", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+	}
+}
+
+// blackjackInsuranceBet handles the insurance bet entered by a player.
+func blackjackInsuranceBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	game := GetGame(i.GuildID, strings.TrimPrefix(data.CustomID, insuranceModalPrefix))
+	if game == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No active blackjack game found.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	amount, err := form.ParseAmount(form.TextInputValue(data.Components, insuranceInputID), "credits")
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if err := game.PlayerInsurance(i.Member.User.ID, amount); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You placed an insurance bet of %d credits.", amount))).SendEphemeral(s, i.Interaction)
+}
+
+// blackjackEvenMoney handles a player taking even money on their blackjack.
+func blackjackEvenMoney(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	game := GetGame(i.GuildID, getUIDFromInteraction(i))
+	if game == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No active blackjack game found.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	if err := game.PlayerEvenMoney(i.Member.User.ID); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	disgomsg.NewResponse(disgomsg.WithContent("You took even money. Your blackjack will be paid 1 to 1.")).SendEphemeral(s, i.Interaction)
+}
+
+// blackjackNoInsurance handles a player declining insurance.
+func blackjackNoInsurance(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	game := GetGame(i.GuildID, getUIDFromInteraction(i))
+	if game == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No active blackjack game found.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	if err := game.PlayerDeclineInsurance(i.Member.User.ID); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	disgomsg.NewResponse(disgomsg.WithContent("You declined insurance.")).SendEphemeral(s, i.Interaction)
+}
+
 // showStats handles the /blackjack/stats command.
 func showStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	p := message.NewPrinter(language.AmericanEnglish)
@@ -1028,6 +1880,22 @@ func showStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 					p.Sprintf("%d", member.Surrenders)),
 				Inline: true,
 			},
+			{
+				Name: "🛡️ Insurance",
+				Value: fmt.Sprintf("**Insurance Bets:** %s\n**Insurance Won:** %s\n**Even Money:** %s",
+					p.Sprintf("%d", member.Insurances),
+					p.Sprintf("%d", member.InsuranceWon),
+					p.Sprintf("%d", member.EvenMoney)),
+				Inline: true,
+			},
+			{
+				Name: "🎲 Side Bets",
+				Value: fmt.Sprintf("**Side Bets:** %s\n**Side Bets Won:** %s\n**Net:** %s",
+					p.Sprintf("%d", member.SideBets),
+					p.Sprintf("%d", member.SideBetsWon),
+					formatNetCredits(member.SideBetCreditsWon-member.SideBetCreditsLost, p)),
+				Inline: true,
+			},
 			{
 				Name: "💰 Credits",
 				Value: fmt.Sprintf("**Total Bet:** %s\n**Credits Won:** %s\n**Credits Lost:** %s\n**Net:** %s",
@@ -1040,6 +1908,20 @@ func showStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		},
 	}
 
+	// Add the coach's report if the member has made decisions with the coach on
+	if member.CoachedDecisions > 0 {
+		followed := float64(member.CoachedDecisions-member.Deviations) / float64(member.CoachedDecisions) * 100
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "🧑‍🏫 Coach",
+			Value: fmt.Sprintf("**Decisions:** %s\n**Followed Basic Strategy:** %.1f%%\n**Deviations:** %s\n**Lost on Hands with Deviations:** %s",
+				p.Sprintf("%d", member.CoachedDecisions),
+				followed,
+				p.Sprintf("%d", member.Deviations),
+				p.Sprintf("%d", member.DeviatedHandCreditsLost)),
+			Inline: false,
+		})
+	}
+
 	// Add last played field if the member has played before
 	if !member.LastPlayed.IsZero() {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
@@ -1084,3 +1966,33 @@ func formatNetCredits(netCredits int, p *message.Printer) string {
 		return "**0** ➖"
 	}
 }
+
+// parseBet returns the number of credits in the bet entered by a member, ensuring it is within
+// the table limits.
+func parseBet(value string, config *Config) (int, error) {
+	bet, err := form.ParseAmount(value, "credits")
+	if err != nil {
+		return 0, err
+	}
+	if err := config.checkBet(bet); err != nil {
+		return 0, err
+	}
+	return bet, nil
+}
+
+// parseSideBet returns the number of credits in a side bet entered by a member. A side bet that is
+// left empty is zero.
+func parseSideBet(value string) (int, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	return form.ParseAmount(value, "credits")
+}
+
+// formatDisplay returns a description of how the table is displayed.
+func formatDisplay(config *Config) string {
+	if config.Display != DisplayImage {
+		return "emoji"
+	}
+	return "image (" + config.cardTheme() + " cards)"
+}
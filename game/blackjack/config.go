@@ -3,6 +3,7 @@ package blackjack
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,7 +14,8 @@ import (
 )
 
 const (
-	ConfigFileName = "config"
+	ConfigFileName          = "config"
+	defaultMaxBetMultiplier = 10
 )
 
 // Config holds the configuration settings for the blackjack game.
@@ -23,6 +25,8 @@ type Config struct {
 	MaxPlayers        int           `json:"max_players" bson:"max_players"`
 	Decks             int           `json:"decks" bson:"decks"`
 	BetAmount         int           `json:"bet_amount" bson:"bet_amount"`
+	MinBetAmount      int           `json:"min_bet_amount" bson:"min_bet_amount"`
+	MaxBetAmount      int           `json:"max_bet_amount" bson:"max_bet_amount"`
 	DelayBetweenGames time.Duration `json:"delay_between_games" bson:"delay_between_games"`
 	WaitForPlayers    time.Duration `json:"wait_for_players" bson:"wait_for_players"`
 	PlayerTimeout     time.Duration `json:"player_timeout" bson:"player_timeout"`
@@ -30,6 +34,10 @@ type Config struct {
 	ShowDealerTurn    time.Duration `json:"show_dealer_turn" bson:"show_dealer_turn"`
 	PayoutPercent     int           `json:"payout_percent" bson:"payout_percent"`
 	SinglePlayerMode  bool          `json:"single_player_mode" bson:"single_player_mode"`
+	HouseRules        *HouseRules   `json:"house_rules" bson:"house_rules"`
+	SideBets          *SideBets     `json:"side_bets" bson:"side_bets"`
+	Display           string        `json:"display,omitempty" bson:"display,omitempty"`
+	CardTheme         string        `json:"card_theme,omitempty" bson:"card_theme,omitempty"`
 }
 
 // String returns a string representation of the Config struct.
@@ -41,8 +49,14 @@ func (c *Config) String() string {
 	fmt.Fprintf(&sb, "MaxPlayers: %d, ", c.MaxPlayers)
 	fmt.Fprintf(&sb, "Decks: %d, ", c.Decks)
 	fmt.Fprintf(&sb, "BetAmount: %d, ", c.BetAmount)
+	fmt.Fprintf(&sb, "MinBetAmount: %d, ", c.MinBetAmount)
+	fmt.Fprintf(&sb, "MaxBetAmount: %d, ", c.MaxBetAmount)
 	fmt.Fprintf(&sb, "DelayBetweenGames: %v, ", c.DelayBetweenGames)
-	fmt.Fprintf(&sb, "WaitForPlayers: %v", c.WaitForPlayers)
+	fmt.Fprintf(&sb, "WaitForPlayers: %v, ", c.WaitForPlayers)
+	fmt.Fprintf(&sb, "HouseRules: %v, ", c.HouseRules)
+	fmt.Fprintf(&sb, "SideBets: %v, ", c.SideBets)
+	fmt.Fprintf(&sb, "Display: %s, ", c.Display)
+	fmt.Fprintf(&sb, "CardTheme: %s", c.CardTheme)
 	sb.WriteString("}")
 	return sb.String()
 }
@@ -58,6 +72,22 @@ func GetConfig(guildID string) *Config {
 		config.GuildID = guildID
 		writeConfig(config)
 	}
+	if config.MinBetAmount == 0 && config.MaxBetAmount == 0 {
+		config.MinBetAmount = config.BetAmount
+		config.MaxBetAmount = config.BetAmount * defaultMaxBetMultiplier
+		writeConfig(config)
+		slog.Debug("set blackjack bet limits", slog.String("guildID", guildID), slog.Int("min_bet_amount", config.MinBetAmount), slog.Int("max_bet_amount", config.MaxBetAmount))
+	}
+	if config.HouseRules == nil {
+		config.HouseRules = defaultHouseRules()
+		writeConfig(config)
+		slog.Debug("set blackjack house rules", slog.String("guildID", guildID), slog.Any("houseRules", config.HouseRules))
+	}
+	if config.SideBets == nil || config.SideBets.PerfectPairs == nil || config.SideBets.TwentyOnePlusThree == nil {
+		config.SideBets = defaultSideBets()
+		writeConfig(config)
+		slog.Debug("set blackjack side bets", slog.String("guildID", guildID), slog.Any("sideBets", config.SideBets))
+	}
 	if config.SinglePlayerMode {
 		config.MaxPlayers = 1
 		config.WaitForPlayers = 0
@@ -73,6 +103,8 @@ func defaultConfig() *Config {
 		MaxPlayers:        5,
 		Decks:             6,
 		BetAmount:         50,
+		MinBetAmount:      50,
+		MaxBetAmount:      500,
 		DelayBetweenGames: 10 * time.Second,
 		WaitForPlayers:    15 * time.Second,
 		PlayerTimeout:     30 * time.Second,
@@ -80,7 +112,31 @@ func defaultConfig() *Config {
 		ShowDealerTurn:    0 * time.Second,
 		PayoutPercent:     200,
 		SinglePlayerMode:  false,
+		HouseRules:        defaultHouseRules(),
+		SideBets:          defaultSideBets(),
+	}
+}
+
+// defaultBet returns the bet placed by a player who doesn't choose one, which is the configured bet
+// amount kept within the table limits.
+func (c *Config) defaultBet() int {
+	return min(max(c.BetAmount, c.MinBetAmount), c.MaxBetAmount)
+}
+
+// cardTheme returns the name of the directory with the card art used when cards are shown as images.
+func (c *Config) cardTheme() string {
+	if c.CardTheme == "" {
+		return defaultCardTheme
+	}
+	return c.CardTheme
+}
+
+// checkBet returns an error if the bet is outside the table limits.
+func (c *Config) checkBet(bet int) error {
+	if bet < c.MinBetAmount || bet > c.MaxBetAmount {
+		return ErrInvalidBet{c.MinBetAmount, c.MaxBetAmount}
 	}
+	return nil
 }
 
 // readConfigFromFile reads the configuration from a JSON file and returns a Config instance.
@@ -7,8 +7,11 @@ import (
 )
 
 const (
-	blackjackMemberCollection = "blackjack_members"
-	blackjackConfigCollection = "blackjack_configs"
+	blackjackMemberCollection     = "blackjack_members"
+	blackjackConfigCollection     = "blackjack_configs"
+	blackjackGameCollection       = "blackjack_games"
+	blackjackRoundCollection      = "blackjack_rounds"
+	blackjackTournamentCollection = "blackjack_tournaments"
 )
 
 // readConfig loads the blackjack configuration from the database. If it does not exist, then
@@ -63,3 +66,102 @@ func writeMember(member *Member) {
 		slog.Error("error writing blackjack member to the database", slog.String("guildID", member.GuildID), slog.String("memberID", member.MemberID), slog.Any("error", err))
 	}
 }
+
+// readSavedGames loads the saved blackjack games for all guilds.
+func readSavedGames() []*SavedGame {
+	var games []*SavedGame
+	if err := db.FindMany(blackjackGameCollection, bson.M{}, &games, bson.M{}, 0); err != nil {
+		slog.Error("unable to read saved blackjack games", slog.Any("error", err))
+		return nil
+	}
+
+	return games
+}
+
+// writeSavedGame creates or updates the saved blackjack game in the database.
+func writeSavedGame(game *SavedGame) {
+	filter := bson.M{"uid": game.UID}
+	if err := db.UpdateOrInsert(blackjackGameCollection, filter, game); err != nil {
+		slog.Error("error writing blackjack game to the database", slog.String("guildID", game.GuildID), slog.String("uid", game.UID), slog.Any("error", err))
+	}
+}
+
+// deleteSavedGame removes the saved blackjack game from the database.
+func deleteSavedGame(uid string) {
+	filter := bson.M{"uid": uid}
+	if err := db.DeleteMany(blackjackGameCollection, filter); err != nil {
+		slog.Error("error deleting blackjack game from the database", slog.String("uid", uid), slog.Any("error", err))
+	}
+}
+
+// readRoundRecord loads the saved record of a round from the database. If it does not exist, then
+// a `nil` value is returned.
+func readRoundRecord(guildID string, roundID bson.ObjectID) *RoundRecord {
+	filter := bson.D{{Key: "_id", Value: roundID}, {Key: "guild_id", Value: guildID}}
+	var record RoundRecord
+	err := db.FindOne(blackjackRoundCollection, filter, &record)
+	if err != nil {
+		slog.Debug("blackjack round not found in the database", slog.String("guildID", guildID), slog.String("roundID", roundID.Hex()), slog.Any("error", err))
+		return nil
+	}
+
+	return &record
+}
+
+// writeRoundRecord stores the record of a round in the database.
+func writeRoundRecord(record *RoundRecord) {
+	filter := bson.D{{Key: "_id", Value: record.ID}}
+	if err := db.UpdateOrInsert(blackjackRoundCollection, filter, record); err != nil {
+		slog.Error("error writing blackjack round to the database", slog.String("guildID", record.GuildID), slog.String("uid", record.TableUID), slog.Any("error", err))
+	}
+}
+
+// readRoundRecords loads the most recent rounds that match the filter, newest first.
+func readRoundRecords(filter bson.D, limit int64) ([]*RoundRecord, error) {
+	var records []*RoundRecord
+	sort := bson.D{{Key: "played_at", Value: -1}}
+	if err := db.FindMany(blackjackRoundCollection, filter, &records, sort, limit); err != nil {
+		slog.Debug("unable to read blackjack rounds", slog.Any("filter", filter), slog.Any("error", err))
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// readActiveTournament loads the tournament that is scheduled or running in the guild. If there
+// isn't one, then a `nil` value is returned.
+func readActiveTournament(guildID string) *Tournament {
+	filter := bson.D{
+		{Key: "guild_id", Value: guildID},
+		{Key: "state", Value: bson.D{{Key: "$in", Value: bson.A{TournamentScheduled, TournamentRunning}}}},
+	}
+	var tournament Tournament
+	err := db.FindOne(blackjackTournamentCollection, filter, &tournament)
+	if err != nil {
+		slog.Debug("blackjack tournament not found in the database", slog.String("guildID", guildID), slog.Any("error", err))
+		return nil
+	}
+
+	return &tournament
+}
+
+// readActiveTournaments loads the tournaments that are scheduled or running in all guilds.
+func readActiveTournaments() ([]*Tournament, error) {
+	var tournaments []*Tournament
+	filter := bson.D{{Key: "state", Value: bson.D{{Key: "$in", Value: bson.A{TournamentScheduled, TournamentRunning}}}}}
+	sort := bson.D{{Key: "start", Value: 1}}
+	if err := db.FindMany(blackjackTournamentCollection, filter, &tournaments, sort, 0); err != nil {
+		slog.Debug("unable to read blackjack tournaments", slog.Any("error", err))
+		return nil, err
+	}
+
+	return tournaments, nil
+}
+
+// writeTournament creates or updates the tournament in the database.
+func writeTournament(tournament *Tournament) {
+	filter := bson.D{{Key: "_id", Value: tournament.ID}}
+	if err := db.UpdateOrInsert(blackjackTournamentCollection, filter, tournament); err != nil {
+		slog.Error("error writing blackjack tournament to the database", slog.String("guildID", tournament.GuildID), slog.String("name", tournament.Name), slog.Any("error", err))
+	}
+}
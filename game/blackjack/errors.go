@@ -1,15 +1,88 @@
 package blackjack
 
-import "errors"
+import (
+	"errors"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
 
 var (
-	ErrAllPlayersBusted    = errors.New("all players have busted. The dealer wins.")
-	ErrCannotDoubleDown    = errors.New("you cannot double down on this hand.")
-	ErrCannotSplit         = errors.New("you cannot split this hand.")
-	ErrCannotSurrender     = errors.New("you cannot surrender this hand.")
-	ErrGameActive          = errors.New("the game has already started.")
-	ErrGameFull            = errors.New("the game is already full.")
-	ErrGameNotStarted      = errors.New("the blackjack game has not started yet. Please wait for the game to start before joining.")
-	ErrNotActivePlayer     = errors.New("you are not the active player.")
-	ErrPlayerAlreadyInGame = errors.New("you already joined the game.")
+	ErrAllPlayersBusted           = errors.New("all players have busted. The dealer wins.")
+	ErrAlreadyInTournament        = errors.New("you have already entered the tournament.")
+	ErrCannotDoubleDown           = errors.New("you cannot double down on this hand.")
+	ErrCannotSplit                = errors.New("you cannot split this hand.")
+	ErrCannotSurrender            = errors.New("you cannot surrender this hand.")
+	ErrGameActive                 = errors.New("the game has already started.")
+	ErrGameFull                   = errors.New("the game is already full.")
+	ErrGameNotStarted             = errors.New("the blackjack game has not started yet. Please wait for the game to start before joining.")
+	ErrInsuranceClosed            = errors.New("insurance is not being offered.")
+	ErrInsuranceDecided           = errors.New("you already made your insurance decision.")
+	ErrNoBlackjack                = errors.New("you need a blackjack to take even money.")
+	ErrNoTournament               = errors.New("there isn't a blackjack tournament scheduled.")
+	ErrNotEnoughChips             = errors.New("you don't have enough chips.")
+	ErrNotInGame                  = errors.New("you are not playing in this game.")
+	ErrNotInTournament            = errors.New("you haven't entered the tournament.")
+	ErrNotActivePlayer            = errors.New("you are not the active player.")
+	ErrPlayerAlreadyInGame        = errors.New("you already joined the game.")
+	ErrTournamentAlreadyScheduled = errors.New("a blackjack tournament is already scheduled.")
+	ErrTournamentFull             = errors.New("the tournament is full.")
+	ErrTournamentStarted          = errors.New("the tournament has already started.")
 )
+
+// ErrInvalidBet is returned when a bet is outside the table limits.
+type ErrInvalidBet struct {
+	MinBetAmount int
+	MaxBetAmount int
+}
+
+// Error returns the error message for ErrInvalidBet.
+func (e ErrInvalidBet) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your bet must be between %d and %d credits.", e.MinBetAmount, e.MaxBetAmount)
+}
+
+// ErrInvalidTournamentBet is returned when a tournament bet is outside the tournament's limits.
+type ErrInvalidTournamentBet struct {
+	MinBetAmount int
+	MaxBetAmount int
+}
+
+// Error returns the error message for ErrInvalidTournamentBet.
+func (e ErrInvalidTournamentBet) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your bet must be between %d and %d chips.", e.MinBetAmount, e.MaxBetAmount)
+}
+
+// ErrInvalidInsurance is returned when an insurance bet is more than half the player's wager.
+type ErrInvalidInsurance struct {
+	MaxInsurance int
+}
+
+// Error returns the error message for ErrInvalidInsurance.
+func (e ErrInvalidInsurance) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your insurance bet must be between 1 and %d credits.", e.MaxInsurance)
+}
+
+// ErrSideBetNotOffered is returned when a player places a side bet that isn't offered at the table.
+type ErrSideBetNotOffered struct {
+	Name string
+}
+
+// Error returns the error message for ErrSideBetNotOffered.
+func (e ErrSideBetNotOffered) Error() string {
+	return "the " + e.Name + " side bet is not offered at this table."
+}
+
+// ErrInvalidSideBet is returned when a side bet is more than the most that may be bet.
+type ErrInvalidSideBet struct {
+	Name   string
+	MaxBet int
+}
+
+// Error returns the error message for ErrInvalidSideBet.
+func (e ErrInvalidSideBet) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your %s side bet must be between 1 and %d credits.", e.Name, e.MaxBet)
+}
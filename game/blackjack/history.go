@@ -0,0 +1,341 @@
+package blackjack
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/history"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// RoundRecord is the saved record of a round of blackjack, used by players to review their hands and
+// by admins to resolve disputes. Payouts are the amounts won after the payout percent, or the negative
+// amount lost.
+type RoundRecord struct {
+	ID            bson.ObjectID   `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID       string          `json:"guild_id" bson:"guild_id"`
+	TableUID      string          `json:"table_uid" bson:"table_uid"`
+	ChannelID     string          `json:"channel_id" bson:"channel_id"`
+	PlayedAt      time.Time       `json:"played_at" bson:"played_at"`
+	PayoutPercent int             `json:"payout_percent" bson:"payout_percent"`
+	HouseRules    *HouseRules     `json:"house_rules" bson:"house_rules"`
+	DealerCards   []string        `json:"dealer_cards" bson:"dealer_cards"`
+	DealerValue   int             `json:"dealer_value" bson:"dealer_value"`
+	Players       []*PlayerRecord `json:"players" bson:"players"`
+	TournamentID  bson.ObjectID   `json:"tournament_id,omitempty" bson:"tournament_id,omitempty"`
+}
+
+// PlayerRecord is a player in a saved round of blackjack.
+type PlayerRecord struct {
+	MemberID        string           `json:"member_id" bson:"member_id"`
+	Name            string           `json:"name" bson:"name"`
+	Stake           int              `json:"stake" bson:"stake"`
+	Insurance       int              `json:"insurance,omitempty" bson:"insurance,omitempty"`
+	InsurancePayout int              `json:"insurance_payout,omitempty" bson:"insurance_payout,omitempty"`
+	EvenMoney       bool             `json:"even_money,omitempty" bson:"even_money,omitempty"`
+	Hands           []*HandRecord    `json:"hands" bson:"hands"`
+	SideBets        []*SideBetRecord `json:"side_bets,omitempty" bson:"side_bets,omitempty"`
+}
+
+// HandRecord is a hand played in a saved round of blackjack, with the actions in the order they were taken.
+type HandRecord struct {
+	Cards   []string `json:"cards" bson:"cards"`
+	Actions []string `json:"actions" bson:"actions"`
+	Value   int      `json:"value" bson:"value"`
+	Bet     int      `json:"bet" bson:"bet"`
+	Result  string   `json:"result" bson:"result"`
+	Payout  int      `json:"payout" bson:"payout"`
+}
+
+// SideBetRecord is a side bet placed in a saved round of blackjack.
+type SideBetRecord struct {
+	SideBet string `json:"side_bet" bson:"side_bet"`
+	Stake   int    `json:"stake" bson:"stake"`
+	Outcome string `json:"outcome,omitempty" bson:"outcome,omitempty"`
+	Payout  int    `json:"payout" bson:"payout"`
+}
+
+// newRoundRecord returns the record of the round that was just paid out. The caller must hold the
+// game's lock.
+func newRoundRecord(g *Game) *RoundRecord {
+	dealerHand := g.Dealer().Hand()
+	record := &RoundRecord{
+		ID:            g.roundID,
+		GuildID:       g.guildID,
+		TableUID:      g.uid,
+		ChannelID:     g.channelID,
+		PlayedAt:      time.Now(),
+		PayoutPercent: g.config.PayoutPercent,
+		HouseRules:    g.config.HouseRules,
+		DealerCards:   cardNames(dealerHand),
+		DealerValue:   dealerHand.Value(),
+		Players:       make([]*PlayerRecord, 0, len(g.Players())),
+	}
+	if g.tournament != nil {
+		record.TournamentID = g.tournament.ID
+	}
+
+	for _, player := range g.Players() {
+		memberID := player.Name()
+		playerRecord := &PlayerRecord{
+			MemberID:  memberID,
+			Name:      guild.GetMember(g.guildID, memberID).Name,
+			Stake:     g.stakes[memberID],
+			Insurance: g.insurance[memberID],
+			EvenMoney: g.evenMoney[memberID],
+			Hands:     make([]*HandRecord, 0, len(player.Hands())),
+		}
+		if playerRecord.Insurance > 0 {
+			playerRecord.InsurancePayout = g.InsuranceWinnings(memberID)
+		}
+		for _, hand := range player.Hands() {
+			actions := make([]string, 0, len(hand.Actions()))
+			for _, action := range hand.Actions() {
+				actions = append(actions, formatAction(action))
+			}
+			playerRecord.Hands = append(playerRecord.Hands, &HandRecord{
+				Cards:   cardNames(hand),
+				Actions: actions,
+				Value:   hand.Value(),
+				Bet:     hand.Bet(),
+				Result:  g.handResult(memberID, hand),
+				Payout:  g.handPayout(hand),
+			})
+		}
+		for _, result := range g.sideBetResults[memberID] {
+			playerRecord.SideBets = append(playerRecord.SideBets, &SideBetRecord{
+				SideBet: result.SideBet,
+				Stake:   result.Stake,
+				Outcome: result.Outcome,
+				Payout:  result.Winnings,
+			})
+		}
+		record.Players = append(record.Players, playerRecord)
+	}
+
+	return record
+}
+
+// handResult returns the result of a hand, such as "win" or "surrender".
+func (g *Game) handResult(memberID string, hand *bj.Hand) string {
+	switch {
+	case hand.IsSurrendered():
+		return "surrender"
+	case g.evenMoney[memberID] && hand.IsBlackjack():
+		return "even money"
+	}
+	switch g.EvaluateHand(hand) {
+	case bj.PlayerWin:
+		return "win"
+	case bj.PlayerBlackjack:
+		return "blackjack"
+	case bj.Push:
+		return "push"
+	case bj.DealerBlackjack:
+		return "loss to dealer blackjack"
+	default:
+		return "loss"
+	}
+}
+
+// cardNames returns the names of the cards in the hand, such as "Ace of Spades".
+func cardNames(hand *bj.Hand) []string {
+	names := make([]string, 0, len(hand.Cards()))
+	for _, card := range hand.Cards() {
+		names = append(names, card.String())
+	}
+	return names
+}
+
+// formatAction returns an action taken on a hand, such as "hit Ten of Hearts".
+func formatAction(action bj.Action) string {
+	text := string(action.Type)
+	if action.Card != nil {
+		text += " " + action.Card.String()
+	}
+	if action.Details != "" {
+		text += " (" + action.Details + ")"
+	}
+	return text
+}
+
+// blackjackHistory shows the member their most recent rounds of blackjack.
+func blackjackHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	count := history.Count(i.ApplicationCommandData().Options[0].Options)
+	sendMemberHistory(s, i, i.Member.User.ID, count)
+}
+
+// adminHistory shows an admin the details of a single round, the history for a member, or the most
+// recent rounds in the guild.
+func adminHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	var roundID, memberID string
+	for _, option := range options {
+		switch option.Name {
+		case "id":
+			roundID = strings.TrimSpace(option.StringValue())
+		case "user":
+			memberID = option.UserValue(nil).ID
+		}
+	}
+	count := history.Count(options)
+
+	switch {
+	case roundID != "":
+		sendRoundDetails(s, i, roundID)
+	case memberID != "":
+		sendMemberHistory(s, i, memberID, count)
+	default:
+		sendRecentRounds(s, i, count)
+	}
+}
+
+// sendMemberHistory sends the member's hands from the most recent rounds they played.
+func sendMemberHistory(s *discordgo.Session, i *discordgo.InteractionCreate, memberID string, count int) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	filter := bson.D{
+		{Key: "guild_id", Value: i.GuildID},
+		{Key: "players.member_id", Value: memberID},
+	}
+	records, err := readRoundRecords(filter, int64(count))
+	if err != nil {
+		slog.Error("failed to read the blackjack history", slog.String("guildID", i.GuildID), slog.String("memberID", memberID), slog.Any("error", err))
+	}
+	if len(records) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("<@%s> has not played blackjack", memberID))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(records))
+	for _, record := range records {
+		value := p.Sprintf("<t:%d:f>\nDealer: %s (%d)\n%s", record.PlayedAt.Unix(), strings.Join(record.DealerCards, ", "), record.DealerValue, formatPlayerRecord(record.getPlayerRecord(memberID)))
+		fields = append(fields, history.Field(p.Sprintf("Round %s", record.ID.Hex()), value))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Blackjack History",
+		Description: p.Sprintf("The last %d rounds for <@%s>", len(records), memberID),
+		Fields:      fields,
+	}
+
+	history.Send(s, i, "blackjack", embed)
+}
+
+// sendRecentRounds sends a summary of the most recent rounds in the guild.
+func sendRecentRounds(s *discordgo.Session, i *discordgo.InteractionCreate, count int) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	records, err := readRoundRecords(bson.D{{Key: "guild_id", Value: i.GuildID}}, int64(count))
+	if err != nil {
+		slog.Error("failed to read the blackjack history", slog.String("guildID", i.GuildID), slog.Any("error", err))
+	}
+	if len(records) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent("No blackjack rounds have been played")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(records))
+	for _, record := range records {
+		players := make([]string, 0, len(record.Players))
+		for _, player := range record.Players {
+			players = append(players, p.Sprintf("%s (%d)", player.Name, player.payout()))
+		}
+		value := p.Sprintf("<t:%d:f>\nDealer: %d\nPlayers: %s", record.PlayedAt.Unix(), record.DealerValue, strings.Join(players, ", "))
+		fields = append(fields, history.Field(p.Sprintf("Round %s", record.ID.Hex()), value))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Blackjack History",
+		Description: p.Sprintf("The last %d rounds", len(records)),
+		Fields:      fields,
+	}
+
+	history.Send(s, i, "blackjack", embed)
+}
+
+// sendRoundDetails sends the dealer's hand and every player's hands, actions and payouts for a single round.
+func sendRoundDetails(s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	roundID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("%q is not a valid round ID", id))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	record := readRoundRecord(i.GuildID, roundID)
+	if record == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Round %s was not found", id))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(record.Players)+1)
+	fields = append(fields, history.Field("Dealer", p.Sprintf("%s\nValue: %d", strings.Join(record.DealerCards, ", "), record.DealerValue)))
+	for _, player := range record.Players {
+		fields = append(fields, history.Field(p.Sprintf("%s (<@%s>)", player.Name, player.MemberID), formatPlayerRecord(player)))
+	}
+	description := p.Sprintf("<t:%d:f> in <#%s>, table %s, payout percent %d", record.PlayedAt.Unix(), record.ChannelID, record.TableUID, record.PayoutPercent)
+	if !record.TournamentID.IsZero() {
+		description += ", tournament round played with chips"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       p.Sprintf("Round %s", record.ID.Hex()),
+		Description: description,
+		Fields:      fields,
+	}
+
+	history.Send(s, i, "blackjack", embed)
+}
+
+// getPlayerRecord returns the record for the member in the round, or `nil` if they didn't play.
+func (r *RoundRecord) getPlayerRecord(memberID string) *PlayerRecord {
+	for _, player := range r.Players {
+		if player.MemberID == memberID {
+			return player
+		}
+	}
+	return nil
+}
+
+// payout returns the net amount the player won or lost in the round.
+func (r *PlayerRecord) payout() int {
+	total := r.InsurancePayout
+	for _, hand := range r.Hands {
+		total += hand.Payout
+	}
+	for _, sideBet := range r.SideBets {
+		total += sideBet.Payout
+	}
+	return total
+}
+
+// formatPlayerRecord returns the player's hands, with their actions and payouts, along with any
+// insurance and side bets.
+func formatPlayerRecord(player *PlayerRecord) string {
+	if player == nil {
+		return ""
+	}
+	p := message.NewPrinter(language.AmericanEnglish)
+	lines := make([]string, 0, len(player.Hands)+len(player.SideBets)+1)
+	for n, hand := range player.Hands {
+		lines = append(lines, p.Sprintf("Hand %d: %s (%d), bet %d, %s %s\n  %s", n+1, strings.Join(hand.Cards, ", "), hand.Value, hand.Bet, hand.Result, formatPayout(hand.Payout), strings.Join(hand.Actions, ", ")))
+	}
+	if player.Insurance > 0 {
+		lines = append(lines, p.Sprintf("Insurance: bet %d, %s", player.Insurance, formatPayout(player.InsurancePayout)))
+	}
+	for _, sideBet := range player.SideBets {
+		lines = append(lines, p.Sprintf("%s: bet %d, %s", sideBetName(sideBet.SideBet), sideBet.Stake, formatPayout(sideBet.Payout)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatPayout returns the amount won or lost, such as "+150" or "-100".
+func formatPayout(payout int) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	if payout > 0 {
+		return p.Sprintf("+%d", payout)
+	}
+	return p.Sprintf("%d", payout)
+}
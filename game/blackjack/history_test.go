@@ -0,0 +1,55 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/rbrabson/cards"
+	"github.com/rbrabson/goblin/bank"
+)
+
+func TestRoundRecordPayouts(t *testing.T) {
+	defer deleteBankTestGame()
+
+	g := newBankTestGame(t, map[string]int{"win": 100, "push": 100, "lose": 100, "surrender": 100})
+	g.config.PayoutPercent = 90
+	if err := g.chipManager("win").DeductChips(50); err != nil {
+		t.Fatal(err)
+	}
+	g.insurance["win"] = 50
+
+	hands := map[string][]cards.Card{
+		"win":       {{Rank: cards.Ten, Suit: cards.Spades}, {Rank: cards.Queen, Suit: cards.Hearts}},
+		"push":      {{Rank: cards.Ten, Suit: cards.Clubs}, {Rank: cards.Nine, Suit: cards.Hearts}},
+		"lose":      {{Rank: cards.Ten, Suit: cards.Diamonds}, {Rank: cards.Seven, Suit: cards.Hearts}},
+		"surrender": {{Rank: cards.Jack, Suit: cards.Spades}, {Rank: cards.Six, Suit: cards.Hearts}},
+	}
+	for memberID, dealt := range hands {
+		for _, card := range dealt {
+			g.GetPlayer(memberID).CurrentHand().AddCard(card)
+		}
+	}
+	for _, card := range []cards.Card{{Rank: cards.King, Suit: cards.Spades}, {Rank: cards.Nine, Suit: cards.Diamonds}} {
+		g.Dealer().DealCard(card)
+	}
+	if err := g.PlayerSurrender(g.GetPlayer("surrender")); err != nil {
+		t.Fatal(err)
+	}
+	g.PayoutResults()
+
+	record := newRoundRecord(g)
+	want := map[string]int{
+		"win":       180 - 100 - 50, // The 200 credited for the hand is reduced by the payout percent, and the insurance is lost
+		"push":      90 - 100,
+		"lose":      -100,
+		"surrender": 45 - 100,
+	}
+	for memberID, payout := range want {
+		player := record.getPlayerRecord(memberID)
+		if got := player.payout(); got != payout {
+			t.Errorf("%s: expected a recorded payout of %d, got %d", memberID, payout, got)
+		}
+		if change := bank.GetAccount(testGuildID, memberID).GetBalance() - 1000; change != player.payout() {
+			t.Errorf("%s: expected the recorded payout of %d to match the balance change of %d", memberID, player.payout(), change)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package blackjack
+
+import (
+	"log/slog"
+
+	"github.com/rbrabson/cards"
+)
+
+const (
+	insurancePayout = 2 // Insurance pays 2 to 1 when the dealer has blackjack
+)
+
+// dealerShowsAce returns whether the dealer's face up card is an ace. The dealer's first card is
+// the hole card.
+func (g *Game) dealerShowsAce() bool {
+	dealerCards := g.Dealer().Hand().Cards()
+	return len(dealerCards) >= 2 && dealerCards[1].Rank == cards.Ace
+}
+
+// OfferInsurance opens the insurance phase of the round, during which players may place an
+// insurance bet or take even money.
+func (g *Game) OfferInsurance() {
+	g.Lock()
+	defer g.Unlock()
+
+	g.SetState(OfferingInsurance)
+}
+
+// CloseInsurance ends the insurance phase of the round so the players may take their turns.
+func (g *Game) CloseInsurance() {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.IsOfferingInsurance() {
+		g.SetState(DealingHands)
+	}
+}
+
+// IsOfferingInsurance returns whether players may currently take insurance.
+func (g *Game) IsOfferingInsurance() bool {
+	return g.state == OfferingInsurance
+}
+
+// MaxInsurance returns the largest insurance bet the player may place, which is half their wager.
+func (g *Game) MaxInsurance(memberID string) int {
+	return g.stakes[memberID] / 2
+}
+
+// PlayerInsurance places an insurance bet for the player. The bet is taken from the player's
+// account and paid at 2 to 1 if the dealer has blackjack.
+func (g *Game) PlayerInsurance(memberID string, amount int) error {
+	g.Lock()
+	defer g.Unlock()
+
+	if err := g.insuranceChecks(memberID); err != nil {
+		return err
+	}
+	maxInsurance := g.MaxInsurance(memberID)
+	if amount <= 0 || amount > maxInsurance {
+		return ErrInvalidInsurance{maxInsurance}
+	}
+	if err := g.chipManager(memberID).DeductChips(amount); err != nil {
+		return err
+	}
+	g.insurance[memberID] = amount
+
+	slog.Debug("player took insurance", slog.String("guildID", g.guildID), slog.String("memberID", memberID), slog.Int("amount", amount))
+	return nil
+}
+
+// PlayerEvenMoney settles a player's blackjack at 1 to 1 when the round is paid out, whether or not
+// the dealer has blackjack.
+func (g *Game) PlayerEvenMoney(memberID string) error {
+	g.Lock()
+	defer g.Unlock()
+
+	if err := g.insuranceChecks(memberID); err != nil {
+		return err
+	}
+	if !g.GetPlayer(memberID).CurrentHand().IsBlackjack() {
+		return ErrNoBlackjack
+	}
+	g.evenMoney[memberID] = true
+
+	slog.Debug("player took even money", slog.String("guildID", g.guildID), slog.String("memberID", memberID))
+	return nil
+}
+
+// PlayerDeclineInsurance records that the player doesn't want insurance or even money.
+func (g *Game) PlayerDeclineInsurance(memberID string) error {
+	g.Lock()
+	defer g.Unlock()
+
+	if err := g.insuranceChecks(memberID); err != nil {
+		return err
+	}
+	g.insurance[memberID] = 0
+	return nil
+}
+
+// insuranceChecks returns an error if the member may not place an insurance bet or take even money.
+// The caller must hold the game's lock.
+func (g *Game) insuranceChecks(memberID string) error {
+	if !g.IsOfferingInsurance() {
+		return ErrInsuranceClosed
+	}
+	if g.GetPlayer(memberID) == nil {
+		return ErrNotInGame
+	}
+	if g.hasDecidedInsurance(memberID) {
+		return ErrInsuranceDecided
+	}
+	return nil
+}
+
+// hasDecidedInsurance returns whether the player has taken or declined insurance or even money.
+func (g *Game) hasDecidedInsurance(memberID string) bool {
+	_, insured := g.insurance[memberID]
+	return insured || g.evenMoney[memberID]
+}
+
+// AllDecidedInsurance returns whether every player has taken or declined insurance or even money.
+func (g *Game) AllDecidedInsurance() bool {
+	g.Lock()
+	defer g.Unlock()
+
+	for _, player := range g.Players() {
+		if !g.hasDecidedInsurance(player.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Insurance returns the insurance bet placed by the player, or zero if they didn't take insurance.
+func (g *Game) Insurance(memberID string) int {
+	return g.insurance[memberID]
+}
+
+// InsuranceWinnings returns the amount the player won or lost on their insurance bet. A winning bet is
+// the amount credited to the player, less the bet, so it includes the payout percent when the chips are
+// added to a bank account.
+func (g *Game) InsuranceWinnings(memberID string) int {
+	amount := g.insurance[memberID]
+	if amount <= 0 || !g.Dealer().HasBlackjack() {
+		return -amount
+	}
+	return g.creditedChips(amount*(insurancePayout+1)) - amount
+}
+
+// TookEvenMoney returns whether the player took even money on their blackjack.
+func (g *Game) TookEvenMoney(memberID string) bool {
+	return g.evenMoney[memberID]
+}
+
+// settleInsurance pays the insurance bets if the dealer has blackjack. Losing insurance bets were
+// already taken from the players' accounts. The caller must hold the game's lock.
+func (g *Game) settleInsurance() {
+	dealerBlackjack := g.Dealer().HasBlackjack()
+	for memberID, amount := range g.insurance {
+		if amount <= 0 {
+			continue
+		}
+		if dealerBlackjack {
+			g.chipManager(memberID).AddChips(amount * (insurancePayout + 1))
+		}
+		g.settleWager(memberID, amount)
+	}
+}
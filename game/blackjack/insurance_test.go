@@ -0,0 +1,122 @@
+package blackjack
+
+import (
+	"errors"
+	"testing"
+
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+)
+
+// newTournamentTestGame returns a tournament game in which each member has bet their stake and has
+// the given number of chips left. Tournament chips aren't backed by a bank account.
+func newTournamentTestGame(chips int, stakes map[string]int) *Game {
+	tournament := &Tournament{GuildID: "123"}
+	g := &Game{
+		guildID:    "123",
+		game:       bj.New(1),
+		config:     &Config{PayoutPercent: 100, HouseRules: defaultHouseRules()},
+		tournament: tournament,
+		stakes:     stakes,
+		insurance:  make(map[string]int),
+		evenMoney:  make(map[string]bool),
+	}
+	for memberID := range stakes {
+		tournament.Entrants = append(tournament.Entrants, &TournamentEntrant{MemberID: memberID, Chips: chips})
+		g.game.AddPlayer(memberID, bj.WithChipManager(NewTournamentChipManager(tournament, memberID)))
+	}
+	return g
+}
+
+func TestPlayerInsurance(t *testing.T) {
+	g := newTournamentTestGame(1000, map[string]int{"alice": 100, "bob": 100})
+
+	if err := g.PlayerInsurance("alice", 50); !errors.Is(err, ErrInsuranceClosed) {
+		t.Errorf("expected %v before insurance is offered, got %v", ErrInsuranceClosed, err)
+	}
+
+	g.OfferInsurance()
+	if err := g.PlayerInsurance("carol", 50); !errors.Is(err, ErrNotInGame) {
+		t.Errorf("expected %v for a member not in the game, got %v", ErrNotInGame, err)
+	}
+	var invalid ErrInvalidInsurance
+	if err := g.PlayerInsurance("alice", 51); !errors.As(err, &invalid) || invalid.MaxInsurance != 50 {
+		t.Errorf("expected insurance of more than half the stake to be rejected, got %v", err)
+	}
+	if err := g.PlayerInsurance("alice", 50); err != nil {
+		t.Fatalf("expected the insurance bet to be placed, got %v", err)
+	}
+	if err := g.PlayerInsurance("alice", 10); !errors.Is(err, ErrInsuranceDecided) {
+		t.Errorf("expected %v for a second insurance bet, got %v", ErrInsuranceDecided, err)
+	}
+	if g.AllDecidedInsurance() {
+		t.Error("expected bob to still need to decide on insurance")
+	}
+	if err := g.PlayerDeclineInsurance("bob"); err != nil {
+		t.Fatalf("expected bob to decline insurance, got %v", err)
+	}
+	if !g.AllDecidedInsurance() {
+		t.Error("expected every player to have decided on insurance")
+	}
+
+	if got := g.tournament.getEntrant("alice").Chips; got != 950 {
+		t.Errorf("expected the insurance bet to be taken from alice's chips, got %d chips", got)
+	}
+	if got := g.Insurance("bob"); got != 0 {
+		t.Errorf("expected bob to have no insurance, got %d", got)
+	}
+}
+
+func TestSettleInsurance(t *testing.T) {
+	tests := []struct {
+		name      string
+		dealer    []cards.Card
+		wantAlice int
+	}{
+		{"dealer blackjack", []cards.Card{{Rank: cards.King, Suit: cards.Spades}, {Rank: cards.Ace, Suit: cards.Hearts}}, 950 + 150},
+		{"no dealer blackjack", []cards.Card{{Rank: cards.Nine, Suit: cards.Spades}, {Rank: cards.Ace, Suit: cards.Hearts}}, 950},
+	}
+	for _, tt := range tests {
+		g := newTournamentTestGame(1000, map[string]int{"alice": 100, "bob": 100})
+		g.OfferInsurance()
+		if err := g.PlayerInsurance("alice", 50); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.PlayerDeclineInsurance("bob"); err != nil {
+			t.Fatal(err)
+		}
+		for _, card := range tt.dealer {
+			g.Dealer().DealCard(card)
+		}
+
+		g.settleInsurance()
+		if got := g.tournament.getEntrant("alice").Chips; got != tt.wantAlice {
+			t.Errorf("%s: expected alice to have %d chips, got %d", tt.name, tt.wantAlice, got)
+		}
+		if got := g.InsuranceWinnings("alice"); got != tt.wantAlice-1000 {
+			t.Errorf("%s: expected alice's insurance winnings to be %d, got %d", tt.name, tt.wantAlice-1000, got)
+		}
+		if got := g.tournament.getEntrant("bob").Chips; got != 1000 {
+			t.Errorf("%s: expected bob's chips to be unchanged, got %d", tt.name, got)
+		}
+	}
+}
+
+func TestInsuranceWinningsWithPayoutPercent(t *testing.T) {
+	g := &Game{
+		game:      bj.New(1),
+		config:    &Config{PayoutPercent: 90, HouseRules: defaultHouseRules()},
+		insurance: map[string]int{"alice": 50, "bob": 0},
+	}
+	for _, card := range []cards.Card{{Rank: cards.King, Suit: cards.Spades}, {Rank: cards.Ace, Suit: cards.Hearts}} {
+		g.Dealer().DealCard(card)
+	}
+
+	// The 150 credited for a winning bet of 50 is reduced to 135 by the payout percent
+	if got := g.InsuranceWinnings("alice"); got != 85 {
+		t.Errorf("expected insurance winnings of 85, got %d", got)
+	}
+	if got := g.InsuranceWinnings("bob"); got != 0 {
+		t.Errorf("expected no insurance winnings without a bet, got %d", got)
+	}
+}
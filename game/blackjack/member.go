@@ -10,21 +10,33 @@ import (
 
 // Member represents a member's statistics for the blackjack game.
 type Member struct {
-	ID           bson.ObjectID `json:"id" bson:"_id,omitempty"`
-	GuildID      string        `json:"guild_id" bson:"guild_id"`
-	MemberID     string        `json:"member_id" bson:"member_id"`
-	RoundsPlayed int           `json:"rounds_played" bson:"rounds_played"`
-	HandsPlayed  int           `json:"hands_played" bson:"hands_played"`
-	Wins         int           `json:"wins" bson:"wins"`
-	Losses       int           `json:"losses" bson:"losses"`
-	Pushes       int           `json:"pushes" bson:"pushes"`
-	Blackjacks   int           `json:"blackjacks" bson:"blackjacks"`
-	Splits       int           `json:"splits" bson:"splits"`
-	Surrenders   int           `json:"surrenders" bson:"surrenders"`
-	CreditsBet   int           `json:"credits_bet" bson:"credits_bet"`
-	CreditsWon   int           `json:"credits_won" bson:"credits_won"`
-	CreditsLost  int           `json:"credits_lost" bson:"credits_lost"`
-	LastPlayed   time.Time     `json:"last_played" bson:"last_played"`
+	ID                      bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	GuildID                 string        `json:"guild_id" bson:"guild_id"`
+	MemberID                string        `json:"member_id" bson:"member_id"`
+	RoundsPlayed            int           `json:"rounds_played" bson:"rounds_played"`
+	HandsPlayed             int           `json:"hands_played" bson:"hands_played"`
+	Wins                    int           `json:"wins" bson:"wins"`
+	Losses                  int           `json:"losses" bson:"losses"`
+	Pushes                  int           `json:"pushes" bson:"pushes"`
+	Blackjacks              int           `json:"blackjacks" bson:"blackjacks"`
+	Splits                  int           `json:"splits" bson:"splits"`
+	Surrenders              int           `json:"surrenders" bson:"surrenders"`
+	Insurances              int           `json:"insurances" bson:"insurances"`
+	InsuranceWon            int           `json:"insurance_won" bson:"insurance_won"`
+	EvenMoney               int           `json:"even_money" bson:"even_money"`
+	CreditsBet              int           `json:"credits_bet" bson:"credits_bet"`
+	CreditsWon              int           `json:"credits_won" bson:"credits_won"`
+	CreditsLost             int           `json:"credits_lost" bson:"credits_lost"`
+	SideBets                int           `json:"side_bets" bson:"side_bets"`
+	SideBetsWon             int           `json:"side_bets_won" bson:"side_bets_won"`
+	SideBetCreditsBet       int           `json:"side_bet_credits_bet" bson:"side_bet_credits_bet"`
+	SideBetCreditsWon       int           `json:"side_bet_credits_won" bson:"side_bet_credits_won"`
+	SideBetCreditsLost      int           `json:"side_bet_credits_lost" bson:"side_bet_credits_lost"`
+	Coach                   bool          `json:"coach" bson:"coach"`
+	CoachedDecisions        int           `json:"coached_decisions" bson:"coached_decisions"`
+	Deviations              int           `json:"deviations" bson:"deviations"`
+	DeviatedHandCreditsLost int           `json:"deviated_hand_credits_lost" bson:"deviated_hand_credits_lost"`
+	LastPlayed              time.Time     `json:"last_played" bson:"last_played"`
 }
 
 // String returns a string representation of the Member struct.
@@ -41,9 +53,21 @@ func (m *Member) String() string {
 		", Blackjacks: " + strconv.Itoa(m.Blackjacks) +
 		", Splits: " + strconv.Itoa(m.Splits) +
 		", Surrenders: " + strconv.Itoa(m.Surrenders) +
+		", Insurances: " + strconv.Itoa(m.Insurances) +
+		", InsuranceWon: " + strconv.Itoa(m.InsuranceWon) +
+		", EvenMoney: " + strconv.Itoa(m.EvenMoney) +
 		", CreditsBet: " + strconv.Itoa(m.CreditsBet) +
 		", CreditsWon: " + strconv.Itoa(m.CreditsWon) +
 		", CreditsLost: " + strconv.Itoa(m.CreditsLost) +
+		", SideBets: " + strconv.Itoa(m.SideBets) +
+		", SideBetsWon: " + strconv.Itoa(m.SideBetsWon) +
+		", SideBetCreditsBet: " + strconv.Itoa(m.SideBetCreditsBet) +
+		", SideBetCreditsWon: " + strconv.Itoa(m.SideBetCreditsWon) +
+		", SideBetCreditsLost: " + strconv.Itoa(m.SideBetCreditsLost) +
+		", Coach: " + strconv.FormatBool(m.Coach) +
+		", CoachedDecisions: " + strconv.Itoa(m.CoachedDecisions) +
+		", Deviations: " + strconv.Itoa(m.Deviations) +
+		", DeviatedHandCreditsLost: " + strconv.Itoa(m.DeviatedHandCreditsLost) +
 		", LastPlayed: " + m.LastPlayed.String() +
 		"}"
 }
@@ -69,18 +93,34 @@ func newMember(guildID, userID string) *Member {
 	return member
 }
 
+// SetCoach turns basic strategy hints on or off for the member.
+func (m *Member) SetCoach(enabled bool) {
+	m.Coach = enabled
+	writeMember(m)
+}
+
 // RoundPlayed updates the member statistics based on the results of a played round.
 func (m *Member) RoundPlayed(game *Game, player *bj.Player) {
 	m.RoundsPlayed++
 	m.HandsPlayed += len(player.Hands())
 	for _, hand := range player.Hands() {
-		switch game.EvaluateHand(hand) {
+		result := game.EvaluateHand(hand)
+		if game.TookEvenMoney(player.Name()) && hand.IsBlackjack() {
+			// Even money is paid as a win, even if the dealer also has blackjack
+			result = bj.PlayerWin
+		}
+		switch result {
 		case bj.PlayerWin, bj.PlayerBlackjack:
 			m.Wins++
 			m.CreditsWon += hand.Winnings() * game.config.PayoutPercent / 100
 		case bj.DealerWin, bj.DealerBlackjack:
 			m.Losses++
 			m.CreditsLost += -hand.Winnings()
+			// The whole amount lost on a hand on which the player didn't follow basic strategy, which
+			// isn't the same as the cost of the deviation
+			if game.DeviatedOn(hand) {
+				m.DeviatedHandCreditsLost += -hand.Winnings()
+			}
 		case bj.Push:
 			m.Pushes++
 		}
@@ -95,6 +135,33 @@ func (m *Member) RoundPlayed(game *Game, player *bj.Player) {
 		}
 		m.CreditsBet += hand.Bet()
 	}
+	if insurance := game.Insurance(player.Name()); insurance > 0 {
+		m.Insurances++
+		m.CreditsBet += insurance
+		if game.Dealer().HasBlackjack() {
+			m.InsuranceWon++
+			m.CreditsWon += insurance * insurancePayout * game.config.PayoutPercent / 100
+		} else {
+			m.CreditsLost += insurance
+		}
+	}
+	if game.TookEvenMoney(player.Name()) {
+		m.EvenMoney++
+	}
+	decisions, deviations := game.Decisions(player.Name())
+	m.CoachedDecisions += decisions
+	m.Deviations += deviations
+	// Side bets are tracked apart from the main hands
+	for _, result := range game.SideBetResults(player.Name()) {
+		m.SideBets++
+		m.SideBetCreditsBet += result.Stake
+		if result.Winnings > 0 {
+			m.SideBetsWon++
+			m.SideBetCreditsWon += result.Winnings
+		} else {
+			m.SideBetCreditsLost += result.Stake
+		}
+	}
 	m.LastPlayed = time.Now()
 
 	writeMember(m)
@@ -45,7 +45,7 @@ func (plugin *Plugin) Stop() {
 
 	newState := discord.PluginStopped
 	for _, game := range games {
-		if game.IsWaitingForPlayers() || game.IsStartingRound() || game.IsDealingHands() {
+		if game.IsWaitingForPlayers() || game.IsStartingRound() || game.IsDealingHands() || game.IsOfferingInsurance() {
 			newState = discord.PluginStopping
 			break
 		}
@@ -68,6 +68,10 @@ func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
 	if blackjackTheme == "" {
 		blackjackTheme = defaultBlackjackTheme
 	}
+	bot.Session.AddHandlerOnce(func(s *discordgo.Session, r *discordgo.Ready) {
+		restoreGames()
+		restoreTournaments()
+	})
 }
 
 // SetDB sets the database to be used by the slots system. This is used for testing.
@@ -0,0 +1,344 @@
+package blackjack
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/pixfont"
+)
+
+const (
+	DisplayEmoji = "emoji" // Cards are shown using the emoji in the symbols file
+	DisplayImage = "image" // Cards are drawn on an image of the table attached to the message
+)
+
+const (
+	defaultCardTheme = "classic"
+	tableImageName   = "blackjack.png"
+
+	cardWidth     = 48
+	cardHeight    = 68
+	cardGap       = 6
+	cardBorder    = 2
+	tableMargin   = 12
+	labelGap      = 6
+	handGap       = 10
+	minTableWidth = 360
+	rankScale     = 2
+	suitScale     = 4
+	labelScale    = 2
+	maxNameChars  = 12
+)
+
+var (
+	feltColor      = color.RGBA{0x1e, 0x5e, 0x3a, 0xff}
+	cardColor      = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	cardEdgeColor  = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	redSuitColor   = color.RGBA{0xc8, 0x1e, 0x1e, 0xff}
+	blackSuitColor = color.RGBA{0x11, 0x11, 0x11, 0xff}
+	cardBackColor  = color.RGBA{0x2a, 0x4d, 0x9b, 0xff}
+	cardBackStripe = color.RGBA{0x4a, 0x6d, 0xbb, 0xff}
+	labelColor     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	highlightColor = color.RGBA{0xf1, 0xc4, 0x0f, 0xff}
+
+	suitGlyphs = map[cards.Suit]string{
+		cards.Clubs:    "♣",
+		cards.Diamonds: "♦",
+		cards.Hearts:   "♥",
+		cards.Spades:   "♠",
+	}
+
+	cardImages     = make(map[string]image.Image)
+	cardImagesLock = sync.Mutex{}
+)
+
+// tableHand is a hand shown on the image of the table, along with its label.
+type tableHand struct {
+	label  string
+	hand   *bj.Hand
+	hidden bool
+	active bool
+}
+
+// renderTable draws the dealer's hand and each player's hands as a PNG image. The dealer's hole card is
+// drawn face down if it is hidden, and the hand being played is highlighted. Cards are drawn using the
+// card art in the theme directory, or drawn from scratch for any card without art.
+func renderTable(g *Game, hideHoleCard bool, current *bj.Hand) ([]byte, error) {
+	hands := tableHands(g, hideHoleCard, current)
+	theme := g.config.cardTheme()
+
+	width := minTableWidth
+	height := tableMargin
+	for _, th := range hands {
+		width = max(width, 2*tableMargin+pixfont.Width(th.label, labelScale), 2*tableMargin+len(th.hand.Cards())*(cardWidth+cardGap)-cardGap)
+		height += pixfont.Height(labelScale) + labelGap + cardHeight + handGap
+	}
+	height += tableMargin - handGap
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(feltColor), image.Point{}, draw.Src)
+
+	y := tableMargin
+	for _, th := range hands {
+		textColor := labelColor
+		if th.active {
+			textColor = highlightColor
+		}
+		pixfont.Draw(img, tableMargin, y, th.label, textColor, labelScale)
+		y += pixfont.Height(labelScale) + labelGap
+
+		for idx, card := range th.hand.Cards() {
+			at := image.Pt(tableMargin+idx*(cardWidth+cardGap), y)
+			if th.hidden && idx == 0 {
+				drawCardBack(img, at, theme)
+			} else {
+				drawCard(img, at, card, theme)
+			}
+		}
+		if th.active {
+			underline := image.Rect(tableMargin, y+cardHeight+2, tableMargin+len(th.hand.Cards())*(cardWidth+cardGap)-cardGap, y+cardHeight+4)
+			draw.Draw(img, underline, image.NewUniform(highlightColor), image.Point{}, draw.Src)
+		}
+		y += cardHeight + handGap
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tableHands returns the hands shown on the table, starting with the dealer's.
+func tableHands(g *Game, hideHoleCard bool, current *bj.Hand) []*tableHand {
+	dealerHand := g.Dealer().Hand()
+	hands := []*tableHand{{
+		label:  "Dealer: " + strings.TrimSpace(GetHandValue(dealerHand, hideHoleCard)),
+		hand:   dealerHand,
+		hidden: hideHoleCard,
+	}}
+	for _, player := range g.Players() {
+		name := []rune(strings.TrimSpace(guild.GetMember(g.guildID, player.Name()).Name))
+		if len(name) > maxNameChars {
+			name = name[:maxNameChars]
+		}
+		for idx, hand := range player.Hands() {
+			label := string(name)
+			if len(player.Hands()) > 1 {
+				label = fmt.Sprintf("%s hand %d", label, idx+1)
+			}
+			hands = append(hands, &tableHand{
+				label:  label + ": " + strings.TrimSpace(GetHandValue(hand, false)),
+				hand:   hand,
+				active: hand == current,
+			})
+		}
+	}
+	return hands
+}
+
+// tableState returns a description of what is shown on the image of the table, used to tell whether
+// the image needs to be drawn again.
+func tableState(g *Game, hideHoleCard bool, current *bj.Hand) string {
+	var sb strings.Builder
+	for _, th := range tableHands(g, hideHoleCard, current) {
+		fmt.Fprintf(&sb, "%s|%v|%v|%v;", th.label, th.hidden, th.active, th.hand.Cards())
+	}
+	return sb.String()
+}
+
+// drawCard draws the face of a card with its top left corner at the given point.
+func drawCard(img *image.RGBA, at image.Point, card cards.Card, theme string) {
+	rect := image.Rectangle{Min: at, Max: at.Add(image.Pt(cardWidth, cardHeight))}
+	if art := getCardImage(theme, cardImageName(card)); art != nil {
+		drawScaled(img, rect, art)
+		return
+	}
+
+	drawCardOutline(img, rect, cardColor)
+	suitColor := blackSuitColor
+	if card.Suit == cards.Hearts || card.Suit == cards.Diamonds {
+		suitColor = redSuitColor
+	}
+	rank := rankLabel(card.Rank)
+	pixfont.Draw(img, at.X+cardBorder+3, at.Y+cardBorder+3, rank, suitColor, rankScale)
+	suit := suitGlyphs[card.Suit]
+	pixfont.Draw(img,
+		at.X+(cardWidth-pixfont.Width(suit, suitScale))/2,
+		at.Y+cardHeight-cardBorder-4-pixfont.Height(suitScale),
+		suit, suitColor, suitScale)
+}
+
+// drawCardBack draws the back of a card with its top left corner at the given point.
+func drawCardBack(img *image.RGBA, at image.Point, theme string) {
+	rect := image.Rectangle{Min: at, Max: at.Add(image.Pt(cardWidth, cardHeight))}
+	if art := getCardImage(theme, "back.png"); art != nil {
+		drawScaled(img, rect, art)
+		return
+	}
+
+	drawCardOutline(img, rect, cardBackColor)
+	inner := rect.Inset(cardBorder + 2)
+	for y := inner.Min.Y; y < inner.Max.Y; y++ {
+		for x := inner.Min.X; x < inner.Max.X; x++ {
+			if (x+y)%6 < 2 {
+				img.Set(x, y, cardBackStripe)
+			}
+		}
+	}
+}
+
+// drawCardOutline draws a card filled with the given color, with a border around it.
+func drawCardOutline(img *image.RGBA, rect image.Rectangle, fill color.Color) {
+	draw.Draw(img, rect, image.NewUniform(cardEdgeColor), image.Point{}, draw.Src)
+	draw.Draw(img, rect.Inset(cardBorder), image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+// drawScaled draws the image into the rectangle, scaling it using the nearest pixel. Transparent pixels
+// are skipped so the table shows through.
+func drawScaled(img *image.RGBA, rect image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	for y := range rect.Dy() {
+		for x := range rect.Dx() {
+			c := src.At(sb.Min.X+x*sb.Dx()/rect.Dx(), sb.Min.Y+y*sb.Dy()/rect.Dy())
+			if _, _, _, a := c.RGBA(); a < 0x8000 {
+				continue
+			}
+			img.Set(rect.Min.X+x, rect.Min.Y+y, c)
+		}
+	}
+}
+
+// rankLabel returns the short name of a rank shown in the corner of a card, such as "A" or "10".
+func rankLabel(rank cards.Rank) string {
+	switch rank {
+	case cards.Ace:
+		return "A"
+	case cards.Jack:
+		return "J"
+	case cards.Queen:
+		return "Q"
+	case cards.King:
+		return "K"
+	default:
+		return fmt.Sprintf("%d", int(rank))
+	}
+}
+
+// cardImageName returns the name of the file in the theme directory with the art for a card, such as
+// "ace_of_spades.png".
+func cardImageName(card cards.Card) string {
+	return strings.ToLower(card.Rank.String() + "_of_" + card.Suit.String() + ".png")
+}
+
+// cardThemeDir returns the directory with the card art for the theme.
+func cardThemeDir(theme string) string {
+	return filepath.Join(discord.ConfigDir, "blackjack", "cards", theme)
+}
+
+// cardThemeExists returns whether there is a directory with card art for the theme.
+func cardThemeExists(theme string) bool {
+	info, err := os.Stat(cardThemeDir(theme))
+	return err == nil && info.IsDir()
+}
+
+// getCardImage returns the card art with the given file name in the theme directory. Images are read once
+// and cached, including missing images, so a theme without art for every card doesn't read the disk on
+// every update. If the image can't be read, then a `nil` value is returned.
+func getCardImage(theme string, name string) image.Image {
+	path := filepath.Join(cardThemeDir(theme), name)
+
+	cardImagesLock.Lock()
+	defer cardImagesLock.Unlock()
+	if img, ok := cardImages[path]; ok {
+		return img
+	}
+
+	img, err := readCardImage(path)
+	if err != nil {
+		slog.Debug("blackjack card art not found, drawing the card", slog.String("path", path), slog.Any("error", err))
+	}
+	cardImages[path] = img
+	return img
+}
+
+// readCardImage reads and decodes the PNG image in the file.
+func readCardImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return png.Decode(f)
+}
+
+// handCards returns the cards in the hand as text. The emoji in the symbols file are used when the guild
+// shows cards as emoji, and the rank and suit of each card otherwise, so the text can be read without
+// custom emoji.
+func (g *Game) handCards(hand *bj.Hand, hidden bool) string {
+	if g.config.Display != DisplayImage {
+		return g.symbols.GetHandWithoutValue(hand, hidden)
+	}
+	names := make([]string, 0, len(hand.Cards()))
+	for idx, card := range hand.Cards() {
+		if hidden && idx == 0 {
+			names = append(names, "??")
+			continue
+		}
+		names = append(names, rankLabel(card.Rank)+suitGlyphs[card.Suit])
+	}
+	return strings.Join(names, " ")
+}
+
+// title returns the title of a blackjack message, decorated with the symbol for a deck of cards when
+// the guild shows cards as emoji.
+func (g *Game) title(title string) string {
+	if g.config.Display == DisplayImage {
+		return title
+	}
+	return g.symbols["Cards"]["Multiple"] + title + g.symbols["Cards"]["Multiple"]
+}
+
+// tableImage returns an image of the table to attach to the message when the guild shows cards as images,
+// and points the first embed at it. A new image is only returned when the table has changed since it was
+// last shown, as the image already attached to the message can be reused.
+func (g *Game) tableImage(embeds []*discordgo.MessageEmbed, hideHoleCard bool, current *bj.Hand) []*discordgo.File {
+	if g.config.Display != DisplayImage || len(embeds) == 0 {
+		return nil
+	}
+	state := tableState(g, hideHoleCard, current)
+	if g.message != nil && state == g.tableState {
+		embeds[0].Image = &discordgo.MessageEmbedImage{URL: "attachment://" + tableImageName}
+		return nil
+	}
+
+	data, err := renderTable(g, hideHoleCard, current)
+	if err != nil {
+		slog.Error("failed to render the blackjack table", slog.String("guildID", g.guildID), slog.String("uid", g.uid), slog.Any("error", err))
+		return nil
+	}
+	g.tableState = state
+	embeds[0].Image = &discordgo.MessageEmbedImage{URL: "attachment://" + tableImageName}
+	return []*discordgo.File{
+		{
+			Name:        tableImageName,
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(data),
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package blackjack
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+	"github.com/rbrabson/goblin/discord"
+	"github.com/rbrabson/goblin/internal/pixfont"
+)
+
+// newDealerOnlyGame returns a game in which only the dealer has been dealt the cards.
+func newDealerOnlyGame(theme string, dealt ...cards.Card) *Game {
+	g := &Game{
+		game:   bj.New(1),
+		config: &Config{Display: DisplayImage, CardTheme: theme},
+	}
+	for _, card := range dealt {
+		g.Dealer().DealCard(card)
+	}
+	return g
+}
+
+// setConfigDir points the config directory at dir for the duration of the test.
+func setConfigDir(t *testing.T, dir string) {
+	t.Helper()
+	configDir := discord.ConfigDir
+	discord.ConfigDir = dir
+	t.Cleanup(func() {
+		discord.ConfigDir = configDir
+	})
+}
+
+// renderDealer renders the table and returns the decoded image and the top of the dealer's cards.
+func renderDealer(t *testing.T, g *Game, hideHoleCard bool) (image.Image, int) {
+	t.Helper()
+	data, err := renderTable(g, hideHoleCard, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img, tableMargin + pixfont.Height(labelScale) + labelGap
+}
+
+func sameColor(a color.Color, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+func TestRenderTable(t *testing.T) {
+	setConfigDir(t, t.TempDir())
+	g := newDealerOnlyGame("", cards.Card{Rank: cards.King, Suit: cards.Spades}, cards.Card{Rank: cards.Seven, Suit: cards.Hearts})
+
+	img, top := renderDealer(t, g, true)
+	bounds := img.Bounds()
+	if bounds.Dx() != minTableWidth {
+		t.Errorf("expected a width of %d, got %d", minTableWidth, bounds.Dx())
+	}
+	if want := top + cardHeight + tableMargin; bounds.Dy() != want {
+		t.Errorf("expected a height of %d, got %d", want, bounds.Dy())
+	}
+
+	// The hole card is face down and the second card is face up.
+	back := img.At(tableMargin+cardWidth/2, top+cardHeight/2)
+	if !sameColor(back, cardBackColor) && !sameColor(back, cardBackStripe) {
+		t.Errorf("expected the hole card to be face down, got %v", back)
+	}
+	face := img.At(tableMargin+cardWidth+cardGap+cardWidth-cardBorder-3, top+cardHeight/3)
+	if !sameColor(face, cardColor) {
+		t.Errorf("expected the second card to be face up, got %v", face)
+	}
+	if edge := img.At(tableMargin, top); !sameColor(edge, cardEdgeColor) {
+		t.Errorf("expected the card to have a border, got %v", edge)
+	}
+	if felt := img.At(bounds.Max.X-1, bounds.Max.Y-1); !sameColor(felt, feltColor) {
+		t.Errorf("expected the table to be drawn behind the cards, got %v", felt)
+	}
+
+	// Once the hole card is shown, both cards are face up.
+	img, top = renderDealer(t, g, false)
+	if face := img.At(tableMargin+cardWidth-cardBorder-3, top+cardHeight/3); !sameColor(face, cardColor) {
+		t.Errorf("expected the hole card to be face up, got %v", face)
+	}
+}
+
+func TestRenderTableWithCardArt(t *testing.T) {
+	dir := t.TempDir()
+	setConfigDir(t, dir)
+	themeDir := filepath.Join(dir, "blackjack", "cards", "test")
+	if err := os.MkdirAll(themeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	artColor := color.RGBA{0x80, 0x20, 0x60, 0xff}
+	for _, name := range []string{"back.png", "ace_of_spades.png"} {
+		writeTestPNG(t, filepath.Join(themeDir, name), artColor)
+	}
+	g := newDealerOnlyGame("test", cards.Card{Rank: cards.Ace, Suit: cards.Spades}, cards.Card{Rank: cards.Ten, Suit: cards.Hearts})
+
+	img, top := renderDealer(t, g, true)
+	if back := img.At(tableMargin+cardWidth/2, top+cardHeight/2); !sameColor(back, artColor) {
+		t.Errorf("expected the hole card to use the theme's back.png, got %v", back)
+	}
+	// There is no art for the ten of hearts, so it is drawn instead.
+	if face := img.At(tableMargin+cardWidth+cardGap+cardWidth-cardBorder-3, top+cardHeight/3); !sameColor(face, cardColor) {
+		t.Errorf("expected the card without art to be drawn, got %v", face)
+	}
+
+	img, top = renderDealer(t, g, false)
+	if face := img.At(tableMargin+cardWidth/2, top+cardHeight/2); !sameColor(face, artColor) {
+		t.Errorf("expected the ace of spades to use the theme's art, got %v", face)
+	}
+}
+
+func TestCardThemeExists(t *testing.T) {
+	dir := t.TempDir()
+	setConfigDir(t, dir)
+	if err := os.MkdirAll(filepath.Join(dir, "blackjack", "cards", "classic"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "blackjack", "cards", "file"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"classic": true,
+		"missing": false,
+		"file":    false,
+	}
+	for theme, want := range tests {
+		if got := cardThemeExists(theme); got != want {
+			t.Errorf("cardThemeExists(%q) = %t, expected %t", theme, got, want)
+		}
+	}
+}
+
+func TestCardImageName(t *testing.T) {
+	tests := map[cards.Card]string{
+		{Rank: cards.Ace, Suit: cards.Spades}:   "ace_of_spades.png",
+		{Rank: cards.Ten, Suit: cards.Hearts}:   "ten_of_hearts.png",
+		{Rank: cards.Queen, Suit: cards.Clubs}:  "queen_of_clubs.png",
+		{Rank: cards.Two, Suit: cards.Diamonds}: "two_of_diamonds.png",
+	}
+	for card, want := range tests {
+		if got := cardImageName(card); got != want {
+			t.Errorf("cardImageName(%v) = %q, expected %q", card, got, want)
+		}
+	}
+}
+
+func TestHandCards(t *testing.T) {
+	g := newDealerOnlyGame("", cards.Card{Rank: cards.Ace, Suit: cards.Spades}, cards.Card{Rank: cards.Ten, Suit: cards.Hearts})
+	hand := g.Dealer().Hand()
+
+	if got, want := g.handCards(hand, false), "A♠ 10♥"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := g.handCards(hand, true), "?? 10♥"; got != want {
+		t.Errorf("expected %q with the hole card hidden, got %q", want, got)
+	}
+}
+
+// writeTestPNG writes a small PNG filled with the color.
+func writeTestPNG(t *testing.T, path string, c color.Color) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := range 4 {
+		for x := range 4 {
+			img.Set(x, y, c)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,108 @@
+package blackjack
+
+import (
+	"fmt"
+	"strings"
+
+	bj "github.com/rbrabson/blackjack"
+)
+
+const (
+	Payout3To2 = "3:2" // A blackjack pays 3 to 2
+	Payout6To5 = "6:5" // A blackjack pays 6 to 5
+
+	minSplitHands  = 2  // Splitting is allowed, but not re-splitting
+	maxSplitHands  = 4  // The most hands the blackjack library allows a player to split into
+	minPenetration = 50 // Lowest percentage of the shoe dealt before it is reshuffled
+	maxPenetration = 75 // The blackjack library always reshuffles once 75% of the shoe is dealt
+)
+
+// HouseRules are the table rules used for the blackjack game in a guild.
+type HouseRules struct {
+	DealerHitsSoft17 bool   `json:"dealer_hits_soft_17" bson:"dealer_hits_soft_17"`
+	BlackjackPayout  string `json:"blackjack_payout" bson:"blackjack_payout"`
+	DoubleAfterSplit bool   `json:"double_after_split" bson:"double_after_split"`
+	MaxSplitHands    int    `json:"max_split_hands" bson:"max_split_hands"`
+	LateSurrender    bool   `json:"late_surrender" bson:"late_surrender"`
+	Penetration      int    `json:"penetration" bson:"penetration"`
+}
+
+// defaultHouseRules returns the house rules that match how the game was played before the rules
+// could be configured.
+func defaultHouseRules() *HouseRules {
+	return &HouseRules{
+		DealerHitsSoft17: true,
+		BlackjackPayout:  Payout3To2,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    maxSplitHands,
+		LateSurrender:    true,
+		Penetration:      maxPenetration,
+	}
+}
+
+// String returns a string representation of the house rules.
+func (r *HouseRules) String() string {
+	var sb strings.Builder
+	sb.WriteString("HouseRules{")
+	fmt.Fprintf(&sb, "DealerHitsSoft17: %t, ", r.DealerHitsSoft17)
+	fmt.Fprintf(&sb, "BlackjackPayout: %s, ", r.BlackjackPayout)
+	fmt.Fprintf(&sb, "DoubleAfterSplit: %t, ", r.DoubleAfterSplit)
+	fmt.Fprintf(&sb, "MaxSplitHands: %d, ", r.MaxSplitHands)
+	fmt.Fprintf(&sb, "LateSurrender: %t, ", r.LateSurrender)
+	fmt.Fprintf(&sb, "Penetration: %d", r.Penetration)
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// blackjackMultiplier returns the multiplier applied to the bet when a player wins with a blackjack.
+func (r *HouseRules) blackjackMultiplier() float64 {
+	if r.BlackjackPayout == Payout6To5 {
+		return 1.2
+	}
+	return 1.5
+}
+
+// dealerShouldHit returns whether the dealer must take another card with the given hand.
+func (r *HouseRules) dealerShouldHit(hand *bj.Hand) bool {
+	value := hand.Value()
+	switch {
+	case hand.IsBusted():
+		return false
+	case value < 17:
+		return true
+	case value == 17 && isSoft(hand):
+		return r.DealerHitsSoft17
+	default:
+		return false
+	}
+}
+
+// canDoubleDown returns whether the player may double down on the hand under the house rules.
+func (r *HouseRules) canDoubleDown(hand *bj.Hand) bool {
+	if hand.IsSplit() && !r.DoubleAfterSplit {
+		return false
+	}
+	return hand.CanDoubleDown()
+}
+
+// canSplit returns whether the player may split the hand under the house rules.
+func (r *HouseRules) canSplit(player *bj.Player, hand *bj.Hand) bool {
+	if len(player.Hands()) >= r.MaxSplitHands {
+		return false
+	}
+	return hand.CanSplit()
+}
+
+// canSurrender returns whether the player may surrender the hand under the house rules. Players
+// only get a turn once the dealer has checked for blackjack, so any surrender is a late surrender.
+func (r *HouseRules) canSurrender(hand *bj.Hand) bool {
+	return r.LateSurrender && hand.CanSurrender()
+}
+
+// formatSoft17 returns how the dealer plays a soft 17.
+func formatSoft17(hitsSoft17 bool) string {
+	if hitsSoft17 {
+		return "hits"
+	}
+	return "stands"
+}
@@ -0,0 +1,56 @@
+package blackjack
+
+import (
+	"testing"
+
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+)
+
+// newDealerHand returns a dealer's hand with the given cards.
+func newDealerHand(dealt ...cards.Card) *bj.Hand {
+	hand := bj.NewDealerHand()
+	for _, card := range dealt {
+		hand.AddCard(card)
+	}
+	return hand
+}
+
+func TestDealerShouldHit(t *testing.T) {
+	tests := []struct {
+		name    string
+		cards   []cards.Card
+		hitsS17 bool
+		want    bool
+	}{
+		{"hard 16", []cards.Card{{Rank: cards.Ten, Suit: cards.Spades}, {Rank: cards.Six, Suit: cards.Hearts}}, false, true},
+		{"hard 17", []cards.Card{{Rank: cards.Ten, Suit: cards.Spades}, {Rank: cards.Seven, Suit: cards.Hearts}}, true, false},
+		{"soft 17 when the dealer hits soft 17", []cards.Card{{Rank: cards.Ace, Suit: cards.Spades}, {Rank: cards.Six, Suit: cards.Hearts}}, true, true},
+		{"soft 17 when the dealer stands on soft 17", []cards.Card{{Rank: cards.Ace, Suit: cards.Spades}, {Rank: cards.Six, Suit: cards.Hearts}}, false, false},
+		{"soft 18", []cards.Card{{Rank: cards.Ace, Suit: cards.Spades}, {Rank: cards.Seven, Suit: cards.Hearts}}, true, false},
+		{"multi-card soft 17", []cards.Card{{Rank: cards.Ace, Suit: cards.Spades}, {Rank: cards.Two, Suit: cards.Hearts}, {Rank: cards.Four, Suit: cards.Clubs}}, true, true},
+		{"soft 17 with two aces", []cards.Card{{Rank: cards.Ace, Suit: cards.Spades}, {Rank: cards.Ace, Suit: cards.Hearts}, {Rank: cards.Five, Suit: cards.Clubs}}, true, true},
+		{"hard 18 with two aces", []cards.Card{{Rank: cards.Ace, Suit: cards.Spades}, {Rank: cards.Ace, Suit: cards.Hearts}, {Rank: cards.Six, Suit: cards.Clubs}, {Rank: cards.Ten, Suit: cards.Diamonds}}, true, false},
+		{"busted", []cards.Card{{Rank: cards.Ten, Suit: cards.Spades}, {Rank: cards.Six, Suit: cards.Hearts}, {Rank: cards.Nine, Suit: cards.Clubs}}, true, false},
+	}
+	for _, tt := range tests {
+		rules := &HouseRules{DealerHitsSoft17: tt.hitsS17}
+		if got := rules.dealerShouldHit(newDealerHand(tt.cards...)); got != tt.want {
+			t.Errorf("%s: dealerShouldHit() = %t, expected %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBlackjackMultiplier(t *testing.T) {
+	tests := map[string]float64{
+		Payout3To2: 1.5,
+		Payout6To5: 1.2,
+		"":         1.5,
+	}
+	for payout, want := range tests {
+		rules := &HouseRules{BlackjackPayout: payout}
+		if got := rules.blackjackMultiplier(); got != want {
+			t.Errorf("blackjackMultiplier() for %q = %v, expected %v", payout, got, want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package blackjack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+)
+
+const (
+	PerfectPairs       = "perfect_pairs"         // Side bet on the player's first two cards being a pair
+	TwentyOnePlusThree = "twenty_one_plus_three" // Side bet on the player's first two cards and the dealer's upcard making a poker hand
+)
+
+const (
+	MixedPair     = "mixed_pair"      // A pair of different colors
+	ColoredPair   = "colored_pair"    // A pair of the same color but different suits
+	PerfectPair   = "perfect_pair"    // A pair of the same suit
+	Flush         = "flush"           // Three cards of the same suit
+	Straight      = "straight"        // Three cards in sequence
+	ThreeOfAKind  = "three_of_a_kind" // Three cards of the same rank
+	StraightFlush = "straight_flush"  // Three cards in sequence of the same suit
+	SuitedTrips   = "suited_trips"    // Three cards of the same rank and suit
+)
+
+// SideBetTable is the configuration for a side bet. The payouts are the amount won for each credit bet,
+// by outcome.
+type SideBetTable struct {
+	Enabled bool           `json:"enabled" bson:"enabled"`
+	MaxBet  int            `json:"max_bet" bson:"max_bet"`
+	Payouts map[string]int `json:"payouts" bson:"payouts"`
+}
+
+// SideBets is the configuration for the side bets offered at the table.
+type SideBets struct {
+	PerfectPairs       *SideBetTable `json:"perfect_pairs" bson:"perfect_pairs"`
+	TwentyOnePlusThree *SideBetTable `json:"twenty_one_plus_three" bson:"twenty_one_plus_three"`
+}
+
+// SideBetStakes are the side bets placed by a player when joining the game.
+type SideBetStakes struct {
+	PerfectPairs       int
+	TwentyOnePlusThree int
+}
+
+// SideBetResult is the outcome of a side bet placed by a player.
+type SideBetResult struct {
+	SideBet  string // The side bet, such as PerfectPairs
+	Stake    int    // Amount bet
+	Outcome  string // Winning outcome, or an empty string if the bet lost
+	Winnings int    // Amount won after the payout percent, or the negative stake if the bet lost
+}
+
+// defaultSideBets returns the side bets, with common payouts, used if none are configured. The side
+// bets are disabled until enabled by an admin.
+func defaultSideBets() *SideBets {
+	return &SideBets{
+		PerfectPairs: &SideBetTable{
+			MaxBet: 100,
+			Payouts: map[string]int{
+				MixedPair:   6,
+				ColoredPair: 12,
+				PerfectPair: 25,
+			},
+		},
+		TwentyOnePlusThree: &SideBetTable{
+			MaxBet: 100,
+			Payouts: map[string]int{
+				Flush:         5,
+				Straight:      10,
+				ThreeOfAKind:  30,
+				StraightFlush: 40,
+				SuitedTrips:   100,
+			},
+		},
+	}
+}
+
+// table returns the configuration for the given side bet.
+func (sb *SideBets) table(sideBet string) *SideBetTable {
+	if sideBet == PerfectPairs {
+		return sb.PerfectPairs
+	}
+	return sb.TwentyOnePlusThree
+}
+
+// anyEnabled returns whether any side bet is offered at the table.
+func (sb *SideBets) anyEnabled() bool {
+	return sb.PerfectPairs.Enabled || sb.TwentyOnePlusThree.Enabled
+}
+
+// check returns an error if a side bet isn't offered or is more than the most that may be bet.
+func (sb *SideBets) check(stakes SideBetStakes) error {
+	for _, sideBet := range []string{PerfectPairs, TwentyOnePlusThree} {
+		stake := stakes.stake(sideBet)
+		if stake == 0 {
+			continue
+		}
+		table := sb.table(sideBet)
+		if !table.Enabled {
+			return ErrSideBetNotOffered{sideBetName(sideBet)}
+		}
+		if stake < 0 || stake > table.MaxBet {
+			return ErrInvalidSideBet{sideBetName(sideBet), table.MaxBet}
+		}
+	}
+	return nil
+}
+
+// stake returns the amount bet on the given side bet.
+func (s SideBetStakes) stake(sideBet string) int {
+	if sideBet == PerfectPairs {
+		return s.PerfectPairs
+	}
+	return s.TwentyOnePlusThree
+}
+
+// total returns the total amount bet on side bets.
+func (s SideBetStakes) total() int {
+	return s.PerfectPairs + s.TwentyOnePlusThree
+}
+
+// perfectPairsOutcome returns the Perfect Pairs outcome for the player's first two cards, or an empty
+// string if they aren't a pair.
+func perfectPairsOutcome(first cards.Card, second cards.Card) string {
+	switch {
+	case first.Rank != second.Rank:
+		return ""
+	case first.Suit == second.Suit:
+		return PerfectPair
+	case isRed(first) == isRed(second):
+		return ColoredPair
+	default:
+		return MixedPair
+	}
+}
+
+// twentyOnePlusThreeOutcome returns the 21+3 outcome for the player's first two cards and the dealer's
+// upcard, or an empty string if they don't make a winning poker hand.
+func twentyOnePlusThreeOutcome(first cards.Card, second cards.Card, upcard cards.Card) string {
+	flush := first.Suit == second.Suit && second.Suit == upcard.Suit
+	trips := first.Rank == second.Rank && second.Rank == upcard.Rank
+	straight := isStraight(first.Rank, second.Rank, upcard.Rank)
+	switch {
+	case trips && flush:
+		return SuitedTrips
+	case straight && flush:
+		return StraightFlush
+	case trips:
+		return ThreeOfAKind
+	case straight:
+		return Straight
+	case flush:
+		return Flush
+	default:
+		return ""
+	}
+}
+
+// isStraight returns whether the three ranks are in sequence. An ace may be low or high.
+func isStraight(ranks ...cards.Rank) bool {
+	sorted := slices.Clone(ranks)
+	slices.Sort(sorted)
+	if sorted[0] == cards.Ace && sorted[1] == cards.Queen && sorted[2] == cards.King {
+		return true
+	}
+	return sorted[1] == sorted[0]+1 && sorted[2] == sorted[1]+1
+}
+
+// isRed returns whether the card is a diamond or heart.
+func isRed(card cards.Card) bool {
+	return card.Suit == cards.Diamonds || card.Suit == cards.Hearts
+}
+
+// sideBetName returns the name of the side bet shown to players.
+func sideBetName(sideBet string) string {
+	if sideBet == PerfectPairs {
+		return "Perfect Pairs"
+	}
+	return "21+3"
+}
+
+// outcomeName returns the name of a side bet outcome shown to players, such as "colored pair".
+func outcomeName(outcome string) string {
+	return strings.ReplaceAll(outcome, "_", " ")
+}
+
+// formatSideBetTable returns whether the side bet is offered, its maximum bet and its payouts.
+func formatSideBetTable(sideBet string, table *SideBetTable) string {
+	if !table.Enabled {
+		return sideBetName(sideBet) + ": off"
+	}
+	outcomes := make([]string, 0, len(table.Payouts))
+	for outcome := range table.Payouts {
+		outcomes = append(outcomes, outcome)
+	}
+	slices.SortFunc(outcomes, func(a, b string) int {
+		return table.Payouts[a] - table.Payouts[b]
+	})
+	payouts := make([]string, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		payouts = append(payouts, fmt.Sprintf("%s %d:1", outcomeName(outcome), table.Payouts[outcome]))
+	}
+	return fmt.Sprintf("%s: up to %d credits (%s)", sideBetName(sideBet), table.MaxBet, strings.Join(payouts, ", "))
+}
+
+// placeSideBets takes the player's side bets from their account. The caller must hold the game's lock.
+func (g *Game) placeSideBets(cm bj.ChipManager, memberID string, stakes SideBetStakes) error {
+	if stakes.total() == 0 {
+		return nil
+	}
+	if err := cm.DeductChips(stakes.total()); err != nil {
+		return err
+	}
+	g.sideBets[memberID] = stakes
+	return nil
+}
+
+// settleSideBets pays the winning side bets once the initial cards are dealt. The caller must hold
+// the game's lock.
+func (g *Game) settleSideBets() {
+	dealerCards := g.Dealer().Hand().Cards()
+	if len(dealerCards) < 2 {
+		return
+	}
+	upcard := dealerCards[1]
+
+	for memberID, stakes := range g.sideBets {
+		player := g.GetPlayer(memberID)
+		if player == nil || len(player.CurrentHand().Cards()) < 2 {
+			continue
+		}
+		playerCards := player.CurrentHand().Cards()
+		results := make([]*SideBetResult, 0, 2)
+		for _, sideBet := range []string{PerfectPairs, TwentyOnePlusThree} {
+			stake := stakes.stake(sideBet)
+			if stake == 0 {
+				continue
+			}
+			var outcome string
+			if sideBet == PerfectPairs {
+				outcome = perfectPairsOutcome(playerCards[0], playerCards[1])
+			} else {
+				outcome = twentyOnePlusThreeOutcome(playerCards[0], playerCards[1], upcard)
+			}
+			result := &SideBetResult{SideBet: sideBet, Stake: stake, Winnings: -stake}
+			if payout := g.config.SideBets.table(sideBet).Payouts[outcome]; outcome != "" && payout > 0 {
+				result.Outcome = outcome
+				credit := stake * (payout + 1)
+				result.Winnings = g.creditedChips(credit) - stake
+				g.chipManager(memberID).AddChips(credit)
+			}
+			results = append(results, result)
+		}
+		g.sideBetResults[memberID] = results
+		// The side bets are settled, so they are no longer refunded if the game is interrupted
+		g.settleWager(memberID, stakes.total())
+	}
+}
+
+// SideBetResults returns the results of the side bets placed by the player.
+func (g *Game) SideBetResults(memberID string) []*SideBetResult {
+	return g.sideBetResults[memberID]
+}
@@ -0,0 +1,80 @@
+package blackjack
+
+import (
+	"testing"
+
+	"github.com/rbrabson/cards"
+)
+
+func TestPerfectPairsOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  cards.Card
+		second cards.Card
+		want   string
+	}{
+		{"no pair", cards.Card{Rank: cards.King, Suit: cards.Spades}, cards.Card{Rank: cards.Queen, Suit: cards.Spades}, ""},
+		{"mixed pair", cards.Card{Rank: cards.Eight, Suit: cards.Spades}, cards.Card{Rank: cards.Eight, Suit: cards.Hearts}, MixedPair},
+		{"black colored pair", cards.Card{Rank: cards.Eight, Suit: cards.Spades}, cards.Card{Rank: cards.Eight, Suit: cards.Clubs}, ColoredPair},
+		{"red colored pair", cards.Card{Rank: cards.Ace, Suit: cards.Diamonds}, cards.Card{Rank: cards.Ace, Suit: cards.Hearts}, ColoredPair},
+		{"perfect pair", cards.Card{Rank: cards.Five, Suit: cards.Diamonds}, cards.Card{Rank: cards.Five, Suit: cards.Diamonds}, PerfectPair},
+	}
+	for _, tt := range tests {
+		if got := perfectPairsOutcome(tt.first, tt.second); got != tt.want {
+			t.Errorf("%s: perfectPairsOutcome() = %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTwentyOnePlusThreeOutcome(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards [3]cards.Card
+		want  string
+	}{
+		{"nothing", [3]cards.Card{{Rank: cards.Two, Suit: cards.Spades}, {Rank: cards.Nine, Suit: cards.Hearts}, {Rank: cards.King, Suit: cards.Clubs}}, ""},
+		{"pair", [3]cards.Card{{Rank: cards.Nine, Suit: cards.Spades}, {Rank: cards.Nine, Suit: cards.Hearts}, {Rank: cards.King, Suit: cards.Clubs}}, ""},
+		{"flush", [3]cards.Card{{Rank: cards.Two, Suit: cards.Hearts}, {Rank: cards.Nine, Suit: cards.Hearts}, {Rank: cards.King, Suit: cards.Hearts}}, Flush},
+		{"straight", [3]cards.Card{{Rank: cards.Nine, Suit: cards.Spades}, {Rank: cards.Jack, Suit: cards.Hearts}, {Rank: cards.Ten, Suit: cards.Clubs}}, Straight},
+		{"three of a kind", [3]cards.Card{{Rank: cards.Seven, Suit: cards.Spades}, {Rank: cards.Seven, Suit: cards.Hearts}, {Rank: cards.Seven, Suit: cards.Clubs}}, ThreeOfAKind},
+		{"straight flush", [3]cards.Card{{Rank: cards.Queen, Suit: cards.Clubs}, {Rank: cards.Ace, Suit: cards.Clubs}, {Rank: cards.King, Suit: cards.Clubs}}, StraightFlush},
+		{"suited trips", [3]cards.Card{{Rank: cards.Four, Suit: cards.Diamonds}, {Rank: cards.Four, Suit: cards.Diamonds}, {Rank: cards.Four, Suit: cards.Diamonds}}, SuitedTrips},
+	}
+	for _, tt := range tests {
+		if got := twentyOnePlusThreeOutcome(tt.cards[0], tt.cards[1], tt.cards[2]); got != tt.want {
+			t.Errorf("%s: twentyOnePlusThreeOutcome() = %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsStraight(t *testing.T) {
+	tests := []struct {
+		ranks [3]cards.Rank
+		want  bool
+	}{
+		{[3]cards.Rank{cards.Two, cards.Three, cards.Four}, true},
+		{[3]cards.Rank{cards.Four, cards.Two, cards.Three}, true},
+		{[3]cards.Rank{cards.Ace, cards.Two, cards.Three}, true},
+		{[3]cards.Rank{cards.Queen, cards.King, cards.Ace}, true},
+		{[3]cards.Rank{cards.Jack, cards.Queen, cards.King}, true},
+		{[3]cards.Rank{cards.King, cards.Ace, cards.Two}, false},
+		{[3]cards.Rank{cards.Two, cards.Three, cards.Five}, false},
+		{[3]cards.Rank{cards.Five, cards.Five, cards.Six}, false},
+	}
+	for _, tt := range tests {
+		if got := isStraight(tt.ranks[:]...); got != tt.want {
+			t.Errorf("isStraight(%v) = %t, expected %t", tt.ranks, got, tt.want)
+		}
+	}
+}
+
+func TestCreditedChips(t *testing.T) {
+	g := &Game{config: &Config{PayoutPercent: 80}}
+	if got := g.creditedChips(500); got != 400 {
+		t.Errorf("expected the payout percent to be applied to credits, got %d", got)
+	}
+	g.tournament = &Tournament{}
+	if got := g.creditedChips(500); got != 500 {
+		t.Errorf("expected tournament chips to be credited in full, got %d", got)
+	}
+}
@@ -0,0 +1,248 @@
+package blackjack
+
+import (
+	"fmt"
+	"log/slog"
+
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+)
+
+// cardValue returns the blackjack value of a card, counting an ace as 11.
+func cardValue(card cards.Card) int {
+	switch {
+	case card.Rank == cards.Ace:
+		return 11
+	case card.Rank >= cards.Ten:
+		return 10
+	default:
+		return int(card.Rank)
+	}
+}
+
+// isSoft returns whether the hand is soft, which is when an ace still counts as 11 in its best total.
+// The library's Hand.IsSoft counts every ace as 11, so it calls a hand with two aces, such as A-A-5,
+// hard.
+func isSoft(hand *bj.Hand) bool {
+	total, aces := 0, 0
+	for _, card := range hand.Cards() {
+		total += cardValue(card)
+		if card.Rank == cards.Ace {
+			aces++
+		}
+	}
+	for aces > 0 && total > 21 {
+		total -= 10
+		aces--
+	}
+	return aces > 0
+}
+
+// basicStrategy returns the basic strategy action for the player's hand against the dealer's upcard,
+// for a multi-deck shoe played under the house rules. If the best action isn't allowed, such as
+// doubling down on a hand with more than two cards, the best action that is allowed is returned.
+func basicStrategy(rules *HouseRules, player *bj.Player, hand *bj.Hand, upcard cards.Card) Action {
+	dealer := cardValue(upcard)
+	handCards := hand.Cards()
+
+	if rules.canSurrender(hand) && shouldSurrender(rules, hand, dealer) {
+		return Surrender
+	}
+	if rules.canSplit(player, hand) && shouldSplit(rules, cardValue(handCards[0]), dealer) {
+		return Split
+	}
+
+	var action Action
+	if isSoft(hand) {
+		action = softStrategy(rules, hand.Value(), dealer)
+	} else {
+		action = hardStrategy(rules, hand.Value(), dealer)
+	}
+	if action == DoubleDown && !rules.canDoubleDown(hand) {
+		// A soft 18 or 19 that can't be doubled is better stood on than hit
+		if isSoft(hand) && hand.Value() >= 18 {
+			return Stand
+		}
+		return Hit
+	}
+	return action
+}
+
+// shouldSurrender returns whether basic strategy surrenders the hand against the dealer's upcard.
+func shouldSurrender(rules *HouseRules, hand *bj.Hand, dealer int) bool {
+	handCards := hand.Cards()
+	if isSoft(hand) || len(handCards) != 2 {
+		return false
+	}
+	isPair := handCards[0].Rank == handCards[1].Rank
+	switch value := hand.Value(); {
+	case value == 16 && isPair:
+		// Eights are split, except against an ace when the dealer hits soft 17
+		return dealer == 11 && rules.DealerHitsSoft17
+	case value == 16:
+		return dealer >= 9
+	case value == 15:
+		return dealer == 10 || (dealer == 11 && rules.DealerHitsSoft17)
+	case value == 17:
+		return dealer == 11 && rules.DealerHitsSoft17
+	default:
+		return false
+	}
+}
+
+// shouldSplit returns whether basic strategy splits a pair of cards with the given value against the
+// dealer's upcard.
+func shouldSplit(rules *HouseRules, pair int, dealer int) bool {
+	switch pair {
+	case 11, 8:
+		return true
+	case 9:
+		return dealer <= 9 && dealer != 7
+	case 7:
+		return dealer <= 7
+	case 6:
+		return dealer <= 6 && (rules.DoubleAfterSplit || dealer >= 3)
+	case 4:
+		return rules.DoubleAfterSplit && (dealer == 5 || dealer == 6)
+	case 3, 2:
+		return dealer <= 7 && (rules.DoubleAfterSplit || dealer >= 4)
+	default:
+		return false
+	}
+}
+
+// softStrategy returns the basic strategy action for a soft hand against the dealer's upcard.
+func softStrategy(rules *HouseRules, value int, dealer int) Action {
+	switch {
+	case value >= 20:
+		return Stand
+	case value == 19:
+		if dealer == 6 && rules.DealerHitsSoft17 {
+			return DoubleDown
+		}
+		return Stand
+	case value == 18:
+		switch {
+		case dealer == 2 && rules.DealerHitsSoft17, dealer >= 3 && dealer <= 6:
+			return DoubleDown
+		case dealer <= 8:
+			return Stand
+		default:
+			return Hit
+		}
+	case value == 17:
+		if dealer >= 3 && dealer <= 6 {
+			return DoubleDown
+		}
+		return Hit
+	case value >= 15:
+		if dealer >= 4 && dealer <= 6 {
+			return DoubleDown
+		}
+		return Hit
+	case value >= 13:
+		if dealer >= 5 && dealer <= 6 {
+			return DoubleDown
+		}
+		return Hit
+	default:
+		return Hit
+	}
+}
+
+// hardStrategy returns the basic strategy action for a hard hand against the dealer's upcard.
+func hardStrategy(rules *HouseRules, value int, dealer int) Action {
+	switch {
+	case value >= 17:
+		return Stand
+	case value >= 13:
+		if dealer <= 6 {
+			return Stand
+		}
+		return Hit
+	case value == 12:
+		if dealer >= 4 && dealer <= 6 {
+			return Stand
+		}
+		return Hit
+	case value == 11:
+		if dealer <= 10 || rules.DealerHitsSoft17 {
+			return DoubleDown
+		}
+		return Hit
+	case value == 10:
+		if dealer <= 9 {
+			return DoubleDown
+		}
+		return Hit
+	case value == 9:
+		if dealer >= 3 && dealer <= 6 {
+			return DoubleDown
+		}
+		return Hit
+	default:
+		return Hit
+	}
+}
+
+// Recommend returns the basic strategy action for the player's hand against the dealer's upcard. The
+// dealer's first card is the hole card.
+func (g *Game) Recommend(player *bj.Player, hand *bj.Hand) Action {
+	return basicStrategy(g.config.HouseRules, player, hand, g.Dealer().Hand().Cards()[1])
+}
+
+// recordDecision records whether a coached player followed basic strategy with the action taken on
+// their hand.
+func (g *Game) recordDecision(player *bj.Player, hand *bj.Hand, action Action) {
+	g.Lock()
+	defer g.Unlock()
+
+	memberID := player.Name()
+	recommended := g.Recommend(player, hand)
+	g.decisions[memberID]++
+	if action != recommended {
+		g.deviations[memberID]++
+		g.deviatedHands[hand] = true
+		slog.Debug("player deviated from basic strategy", slog.String("guildID", g.guildID), slog.String("memberID", memberID), slog.String("action", action.String()), slog.String("recommended", recommended.String()))
+	}
+}
+
+// Decisions returns the number of actions taken by a coached player this round, and how many of
+// those weren't the basic strategy action.
+func (g *Game) Decisions(memberID string) (int, int) {
+	return g.decisions[memberID], g.deviations[memberID]
+}
+
+// IsCoached returns whether the player wants basic strategy hints during their turn.
+func (g *Game) IsCoached(memberID string) bool {
+	return g.coached[memberID]
+}
+
+// DeviatedOn returns whether the player made a decision on the hand that wasn't the basic strategy
+// action.
+func (g *Game) DeviatedOn(hand *bj.Hand) bool {
+	return g.deviatedHands[hand]
+}
+
+// coachHint returns the hint shown to a coached player for their hand.
+func coachHint(action Action, hand *bj.Hand, upcard cards.Card) string {
+	handCards := hand.Cards()
+	var description string
+	switch {
+	case len(handCards) == 2 && handCards[0].Rank == handCards[1].Rank:
+		description = "a pair of " + cardName(handCards[0]) + "s"
+	case isSoft(hand):
+		description = fmt.Sprintf("soft %d", hand.Value())
+	default:
+		description = fmt.Sprintf("hard %d", hand.Value())
+	}
+	return fmt.Sprintf("Coach: basic strategy is to **%s** with %s against the dealer's %s.", action, description, cardName(upcard))
+}
+
+// cardName returns the name of a card by its blackjack value, such as "ace" or "10".
+func cardName(card cards.Card) string {
+	if card.Rank == cards.Ace {
+		return "ace"
+	}
+	return fmt.Sprintf("%d", cardValue(card))
+}
@@ -0,0 +1,98 @@
+package blackjack
+
+import (
+	"testing"
+
+	bj "github.com/rbrabson/blackjack"
+	"github.com/rbrabson/cards"
+)
+
+// newPlayerHand returns a player whose only hand holds the cards with the given ranks.
+func newPlayerHand(ranks ...cards.Rank) (*bj.Player, *bj.Hand) {
+	player := bj.NewPlayer("alice")
+	hand := player.CurrentHand()
+	for _, rank := range ranks {
+		hand.AddCard(cards.Card{Rank: rank, Suit: cards.Spades})
+	}
+	return player, hand
+}
+
+func TestBasicStrategy(t *testing.T) {
+	h17 := defaultHouseRules()
+	s17 := defaultHouseRules()
+	s17.DealerHitsSoft17 = false
+	noDAS := defaultHouseRules()
+	noDAS.DoubleAfterSplit = false
+	noSurrender := defaultHouseRules()
+	noSurrender.LateSurrender = false
+
+	tests := []struct {
+		name   string
+		rules  *HouseRules
+		ranks  []cards.Rank
+		upcard cards.Rank
+		want   Action
+	}{
+		{"hard 16 against a ten surrenders", h17, []cards.Rank{cards.Ten, cards.Six}, cards.King, Surrender},
+		{"hard 16 against a ten hits without surrender", noSurrender, []cards.Rank{cards.Ten, cards.Six}, cards.King, Hit},
+		{"hard 15 against an ace surrenders when the dealer hits soft 17", h17, []cards.Rank{cards.Ten, cards.Five}, cards.Ace, Surrender},
+		{"hard 15 against an ace hits when the dealer stands on soft 17", s17, []cards.Rank{cards.Ten, cards.Five}, cards.Ace, Hit},
+		{"eights against an ace surrender when the dealer hits soft 17", h17, []cards.Rank{cards.Eight, cards.Eight}, cards.Ace, Surrender},
+		{"eights against an ace split when the dealer stands on soft 17", s17, []cards.Rank{cards.Eight, cards.Eight}, cards.Ace, Split},
+		{"eights against a ten split", h17, []cards.Rank{cards.Eight, cards.Eight}, cards.Ten, Split},
+		{"aces split", h17, []cards.Rank{cards.Ace, cards.Ace}, cards.Six, Split},
+		{"tens stand", h17, []cards.Rank{cards.King, cards.Queen}, cards.Six, Stand},
+		{"fours against a five split with double after split", h17, []cards.Rank{cards.Four, cards.Four}, cards.Five, Split},
+		{"fours against a five hit without double after split", noDAS, []cards.Rank{cards.Four, cards.Four}, cards.Five, Hit},
+		{"twos against a three split with double after split", h17, []cards.Rank{cards.Two, cards.Two}, cards.Three, Split},
+		{"twos against a three hit without double after split", noDAS, []cards.Rank{cards.Two, cards.Two}, cards.Three, Hit},
+		{"soft 19 against a six doubles when the dealer hits soft 17", h17, []cards.Rank{cards.Ace, cards.Eight}, cards.Six, DoubleDown},
+		{"soft 19 against a six stands when the dealer stands on soft 17", s17, []cards.Rank{cards.Ace, cards.Eight}, cards.Six, Stand},
+		{"soft 18 against a two doubles when the dealer hits soft 17", h17, []cards.Rank{cards.Ace, cards.Seven}, cards.Two, DoubleDown},
+		{"soft 18 against a two stands when the dealer stands on soft 17", s17, []cards.Rank{cards.Ace, cards.Seven}, cards.Two, Stand},
+		{"soft 18 against a nine hits", h17, []cards.Rank{cards.Ace, cards.Seven}, cards.Nine, Hit},
+		{"soft 18 with three cards stands instead of doubling", h17, []cards.Rank{cards.Ace, cards.Four, cards.Three}, cards.Four, Stand},
+		{"soft 15 with three cards hits instead of doubling", h17, []cards.Rank{cards.Ace, cards.Two, cards.Two}, cards.Five, Hit},
+		{"soft 17 with two aces hits instead of doubling", h17, []cards.Rank{cards.Ace, cards.Ace, cards.Five}, cards.Three, Hit},
+		{"hard 18 with two aces against a nine stands", h17, []cards.Rank{cards.Ace, cards.Ace, cards.Six, cards.Ten}, cards.Nine, Stand},
+		{"hard 11 against an ace doubles when the dealer hits soft 17", h17, []cards.Rank{cards.Six, cards.Five}, cards.Ace, DoubleDown},
+		{"hard 11 against an ace hits when the dealer stands on soft 17", s17, []cards.Rank{cards.Six, cards.Five}, cards.Ace, Hit},
+		{"hard 12 against a four stands", h17, []cards.Rank{cards.Ten, cards.Two}, cards.Four, Stand},
+		{"hard 12 against a two hits", h17, []cards.Rank{cards.Ten, cards.Two}, cards.Two, Hit},
+		{"hard 9 against a three doubles", h17, []cards.Rank{cards.Five, cards.Four}, cards.Three, DoubleDown},
+	}
+	for _, tt := range tests {
+		player, hand := newPlayerHand(tt.ranks...)
+		upcard := cards.Card{Rank: tt.upcard, Suit: cards.Hearts}
+		if got := basicStrategy(tt.rules, player, hand, upcard); got != tt.want {
+			t.Errorf("%s: basicStrategy() = %s, expected %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBasicStrategyAfterSplit(t *testing.T) {
+	tests := []struct {
+		name             string
+		doubleAfterSplit bool
+		want             Action
+	}{
+		{"doubles with double after split", true, DoubleDown},
+		{"hits without double after split", false, Hit},
+	}
+	for _, tt := range tests {
+		rules := defaultHouseRules()
+		rules.DoubleAfterSplit = tt.doubleAfterSplit
+
+		// Split a pair of sixes, and draw a five to the first hand for a hard 11
+		player, hand := newPlayerHand(cards.Six, cards.Six)
+		if err := hand.Split(); err != nil {
+			t.Fatal(err)
+		}
+		hand.AddCard(cards.Card{Rank: cards.Five, Suit: cards.Clubs})
+
+		upcard := cards.Card{Rank: cards.Six, Suit: cards.Hearts}
+		if got := basicStrategy(rules, player, hand, upcard); got != tt.want {
+			t.Errorf("hard 11 after a split %s: basicStrategy() = %s, expected %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,956 @@
+package blackjack
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/form"
+	"github.com/rbrabson/goblin/internal/unicode"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	tournamentBetButtonID      = "blackjack_tournament_bet"
+	tournamentBetModalID       = "blackjack_tournament_bet_modal"
+	defaultTournamentSignup    = 10 * time.Minute
+	tournamentBetTime          = 20 * time.Second
+	maxTournamentStandingsShow = 10
+	maxTournamentEntrants      = 7 // the seats at a blackjack table
+)
+
+// TournamentState is the stage a tournament has reached.
+type TournamentState string
+
+const (
+	TournamentScheduled TournamentState = "scheduled"
+	TournamentRunning   TournamentState = "running"
+	TournamentCompleted TournamentState = "completed"
+	TournamentCancelled TournamentState = "cancelled"
+)
+
+var (
+	defaultTournamentPayouts = []int{50, 30, 20}
+
+	tournamentTimers = make(map[string]*time.Timer)
+	tournamentLock   = sync.Mutex{}
+)
+
+// Tournament is a series of blackjack rounds played with chips instead of credits. Each entrant pays
+// an entry fee into the prize pool and is given the same stack of chips. After the last round, the
+// entrants with the largest stacks split the prize pool.
+type Tournament struct {
+	ID            bson.ObjectID        `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID       string               `json:"guild_id" bson:"guild_id"`
+	Name          string               `json:"name" bson:"name"`
+	ChannelID     string               `json:"channel_id" bson:"channel_id"`
+	Start         time.Time            `json:"start" bson:"start"`
+	NumRounds     int                  `json:"num_rounds" bson:"num_rounds"`
+	RoundsPlayed  int                  `json:"rounds_played" bson:"rounds_played"`
+	EntryFee      int                  `json:"entry_fee" bson:"entry_fee"`
+	StartingChips int                  `json:"starting_chips" bson:"starting_chips"`
+	MinBet        int                  `json:"min_bet" bson:"min_bet"`
+	MaxBet        int                  `json:"max_bet" bson:"max_bet"`
+	Payouts       []int                `json:"payouts" bson:"payouts"`
+	PrizePool     int                  `json:"prize_pool" bson:"prize_pool"`
+	State         TournamentState      `json:"state" bson:"state"`
+	Entrants      []*TournamentEntrant `json:"entrants" bson:"entrants"`
+}
+
+// TournamentEntrant is a member entered into a tournament, along with their chip stack.
+type TournamentEntrant struct {
+	MemberID string `json:"member_id" bson:"member_id"`
+	Name     string `json:"name" bson:"name"`
+	Chips    int    `json:"chips" bson:"chips"`
+	Bet      int    `json:"bet" bson:"bet"`
+	Prize    int    `json:"prize,omitempty" bson:"prize,omitempty"`
+}
+
+// NewTournament creates a new tournament that starts once the sign up period ends. The payouts are a
+// comma separated list of the percent of the prize pool won by each finishing position.
+func NewTournament(name string, channelID string, numRounds int, entryFee int, startingChips int, minBet int, maxBet int, payouts string, signup time.Duration) (*Tournament, error) {
+	if numRounds < 1 {
+		return nil, fmt.Errorf("there must be at least one round")
+	}
+	if entryFee < 0 {
+		return nil, fmt.Errorf("the entry fee can't be negative")
+	}
+	if minBet < 1 {
+		return nil, fmt.Errorf("the minimum bet must be at least one chip")
+	}
+	if maxBet < minBet {
+		return nil, fmt.Errorf("the maximum bet can't be less than the minimum bet")
+	}
+	if startingChips < minBet {
+		return nil, fmt.Errorf("the starting chips must cover the minimum bet")
+	}
+	if signup <= 0 {
+		signup = defaultTournamentSignup
+	}
+	payoutTable, err := parsePayouts(payouts)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament := &Tournament{
+		Name:          name,
+		ChannelID:     channelID,
+		Start:         time.Now().Add(signup),
+		NumRounds:     numRounds,
+		EntryFee:      entryFee,
+		StartingChips: startingChips,
+		MinBet:        minBet,
+		MaxBet:        maxBet,
+		Payouts:       payoutTable,
+		State:         TournamentScheduled,
+		Entrants:      make([]*TournamentEntrant, 0),
+	}
+
+	return tournament, nil
+}
+
+// parsePayouts returns the percent of the prize pool won by each finishing position. An empty string
+// returns the default payouts.
+func parsePayouts(payouts string) ([]int, error) {
+	if strings.TrimSpace(payouts) == "" {
+		return slices.Clone(defaultTournamentPayouts), nil
+	}
+
+	fields := strings.Split(payouts, ",")
+	table := make([]int, 0, len(fields))
+	total := 0
+	for _, field := range fields {
+		value, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(field), "%")))
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("the payouts must be a comma separated list of percentages, such as `50,30,20`")
+		}
+		table = append(table, value)
+		total += value
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("the payouts must add up to 100 percent")
+	}
+
+	return table, nil
+}
+
+// CreateTournament schedules the tournament for the guild. Only one tournament may be scheduled or running
+// in a guild at a time.
+func CreateTournament(guildID string, tournament *Tournament) error {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	if readActiveTournament(guildID) != nil {
+		return ErrTournamentAlreadyScheduled
+	}
+
+	tournament.ID = bson.NewObjectID()
+	tournament.GuildID = guildID
+	writeTournament(tournament)
+	scheduleTournament(tournament)
+
+	slog.Info("scheduled blackjack tournament",
+		slog.String("guildID", guildID),
+		slog.String("name", tournament.Name),
+		slog.Time("start", tournament.Start),
+		slog.Int("rounds", tournament.NumRounds),
+	)
+
+	return nil
+}
+
+// CancelTournament cancels the tournament that is scheduled or running in the guild, and refunds the
+// entry fees. A round that is being played is finished, but no more rounds are played.
+func CancelTournament(guildID string) (*Tournament, error) {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	tournament.State = TournamentCancelled
+	tournament.refundEntryFees()
+	writeTournament(tournament)
+	stopTournamentTimer(guildID)
+
+	slog.Info("cancelled blackjack tournament", slog.String("guildID", guildID), slog.String("name", tournament.Name))
+
+	return tournament, nil
+}
+
+// GetTournament returns the tournament that is scheduled or running in the guild, or nil if there isn't one.
+func GetTournament(guildID string) *Tournament {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	return readActiveTournament(guildID)
+}
+
+// JoinTournament enters the member into the tournament for the guild, paying the entry fee from their
+// bank account. Members may only join before the first round is played.
+func JoinTournament(guildID string, memberID string, name string) (*Tournament, error) {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	if err := tournament.addEntrant(memberID, name, maxTournamentEntrants); err != nil {
+		return nil, err
+	}
+	if tournament.EntryFee > 0 {
+		account := bank.GetAccount(guildID, memberID)
+		if err := account.Withdraw(tournament.EntryFee); err != nil {
+			return nil, err
+		}
+	}
+	writeTournament(tournament)
+
+	return tournament, nil
+}
+
+// LeaveTournament removes the member from the tournament for the guild and refunds their entry fee.
+// Members may only leave before the first round is played.
+func LeaveTournament(guildID string, memberID string) (*Tournament, error) {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	if err := tournament.removeEntrant(memberID); err != nil {
+		return nil, err
+	}
+	tournament.refundEntryFee(memberID)
+	writeTournament(tournament)
+
+	return tournament, nil
+}
+
+// SetTournamentBet sets the number of chips the member bets on each round of the tournament. If the
+// member has fewer chips than their bet, they bet all of their chips.
+func SetTournamentBet(guildID string, memberID string, bet int) (*Tournament, error) {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	entrant := tournament.getEntrant(memberID)
+	if entrant == nil {
+		return nil, ErrNotInTournament
+	}
+	if bet < tournament.MinBet || bet > tournament.MaxBet {
+		return nil, ErrInvalidTournamentBet{tournament.MinBet, tournament.MaxBet}
+	}
+	entrant.Bet = bet
+	writeTournament(tournament)
+
+	return tournament, nil
+}
+
+// addEntrant adds the member to the tournament with the starting chip stack.
+func (t *Tournament) addEntrant(memberID string, name string, maxEntrants int) error {
+	if t.State != TournamentScheduled {
+		return ErrTournamentStarted
+	}
+	if t.getEntrant(memberID) != nil {
+		return ErrAlreadyInTournament
+	}
+	if len(t.Entrants) >= maxEntrants {
+		return ErrTournamentFull
+	}
+	t.Entrants = append(t.Entrants, &TournamentEntrant{
+		MemberID: memberID,
+		Name:     name,
+		Chips:    t.StartingChips,
+		Bet:      t.MinBet,
+	})
+	return nil
+}
+
+// removeEntrant removes the member from the tournament.
+func (t *Tournament) removeEntrant(memberID string) error {
+	if t.State != TournamentScheduled {
+		return ErrTournamentStarted
+	}
+	index := slices.IndexFunc(t.Entrants, func(entrant *TournamentEntrant) bool {
+		return entrant.MemberID == memberID
+	})
+	if index < 0 {
+		return ErrNotInTournament
+	}
+	t.Entrants = slices.Delete(t.Entrants, index, index+1)
+	return nil
+}
+
+// getEntrant returns the entrant for the member, or nil if the member hasn't entered the tournament.
+func (t *Tournament) getEntrant(memberID string) *TournamentEntrant {
+	for _, entrant := range t.Entrants {
+		if entrant.MemberID == memberID {
+			return entrant
+		}
+	}
+	return nil
+}
+
+// rankings returns the entrants ordered by the size of their chip stacks. Entrants with the same number
+// of chips keep the order in which they entered the tournament.
+func (t *Tournament) rankings() []*TournamentEntrant {
+	rankings := slices.Clone(t.Entrants)
+	slices.SortStableFunc(rankings, func(a, b *TournamentEntrant) int {
+		return cmp.Compare(b.Chips, a.Chips)
+	})
+	return rankings
+}
+
+// activeEntrants returns the entrants who have enough chips to cover the minimum bet.
+func (t *Tournament) activeEntrants() []*TournamentEntrant {
+	active := make([]*TournamentEntrant, 0, len(t.Entrants))
+	for _, entrant := range t.Entrants {
+		if entrant.Chips >= t.MinBet {
+			active = append(active, entrant)
+		}
+	}
+	return active
+}
+
+// isOver returns whether all rounds have been played, or there is no longer more than one entrant with
+// enough chips to keep playing.
+func (t *Tournament) isOver() bool {
+	return t.RoundsPlayed >= t.NumRounds || len(t.activeEntrants()) < 2
+}
+
+// tableConfig returns the configuration for the table the tournament is played at. The guild's house
+// rules and timers are used, but bets are limited to the tournament's limits and are paid at full odds
+// in chips. Side bets aren't offered.
+func (t *Tournament) tableConfig(base *Config) *Config {
+	config := *base
+	config.BetAmount = t.MinBet
+	config.MinBetAmount = t.MinBet
+	config.MaxBetAmount = t.MaxBet
+	config.PayoutPercent = 100
+	config.MaxPlayers = len(t.Entrants)
+	config.SinglePlayerMode = false
+	config.SideBets = &SideBets{
+		PerfectPairs:       &SideBetTable{},
+		TwentyOnePlusThree: &SideBetTable{},
+	}
+	return &config
+}
+
+// awardPrizes splits the prize pool between the entrants with the largest chip stacks. Any credits not
+// paid out, either due to rounding or because there were fewer entrants than paid positions, go to the
+// winner.
+func (t *Tournament) awardPrizes() {
+	rankings := t.rankings()
+	if len(rankings) == 0 {
+		return
+	}
+	paid := 0
+	for n, percent := range t.Payouts[:min(len(t.Payouts), len(rankings))] {
+		rankings[n].Prize = t.PrizePool * percent / 100
+		paid += rankings[n].Prize
+	}
+	rankings[0].Prize += t.PrizePool - paid
+}
+
+// payPrizes deposits the prizes awarded to the entrants.
+func (t *Tournament) payPrizes() {
+	for _, entrant := range t.Entrants {
+		if entrant.Prize == 0 {
+			continue
+		}
+		account := bank.GetAccount(t.GuildID, entrant.MemberID)
+		if err := account.Deposit(entrant.Prize); err != nil {
+			slog.Error("failed to pay the blackjack tournament prize",
+				slog.String("guildID", t.GuildID),
+				slog.String("memberID", entrant.MemberID),
+				slog.Int("prize", entrant.Prize),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// refundEntryFees returns the entry fee to every entrant in the tournament.
+func (t *Tournament) refundEntryFees() {
+	for _, entrant := range t.Entrants {
+		t.refundEntryFee(entrant.MemberID)
+	}
+}
+
+// refundEntryFee returns the entry fee to the member.
+func (t *Tournament) refundEntryFee(memberID string) {
+	if t.EntryFee == 0 {
+		return
+	}
+	account := bank.GetAccount(t.GuildID, memberID)
+	if err := account.Deposit(t.EntryFee); err != nil {
+		slog.Error("failed to refund the blackjack tournament entry fee",
+			slog.String("guildID", t.GuildID),
+			slog.String("memberID", memberID),
+			slog.Int("entryFee", t.EntryFee),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// scheduleTournament sets a timer to start the tournament once sign up ends.
+func scheduleTournament(tournament *Tournament) {
+	guildID, id := tournament.GuildID, tournament.ID
+	timer := time.AfterFunc(time.Until(tournament.Start), func() {
+		runTournament(guildID, id)
+	})
+
+	if existing := tournamentTimers[guildID]; existing != nil {
+		existing.Stop()
+	}
+	tournamentTimers[guildID] = timer
+}
+
+// stopTournamentTimer stops the timer for the tournament in the guild.
+func stopTournamentTimer(guildID string) {
+	if timer := tournamentTimers[guildID]; timer != nil {
+		timer.Stop()
+		delete(tournamentTimers, guildID)
+	}
+}
+
+// runTournament plays each round of the tournament, showing the standings between rounds, and then
+// pays the prizes. The tournament's lock isn't held while a round is played, so members may change
+// their bets and admins may cancel the tournament.
+func runTournament(guildID string, id bson.ObjectID) {
+	if !startTournament(guildID, id) {
+		return
+	}
+
+	game := newTournamentGame(guildID)
+	defer closeTournamentGame(game)
+
+	for {
+		tournament := GetTournament(guildID)
+		if tournament == nil || tournament.ID != id {
+			slog.Info("blackjack tournament is no longer running", slog.String("guildID", guildID), slog.String("tournamentID", id.Hex()))
+			return
+		}
+		if tournament.isOver() {
+			finishTournament(guildID, id)
+			return
+		}
+
+		msg := announceTournament(tournament, tournamentStandingsEmbed(tournament), tournamentBetComponents())
+		time.Sleep(tournamentBetTime)
+		removeTournamentComponents(tournament, msg)
+
+		// Pick up any bets that were changed while waiting
+		tournament = GetTournament(guildID)
+		if tournament == nil || tournament.ID != id {
+			return
+		}
+		playTournamentRound(bot.Session, game, tournament)
+		if !recordTournamentRound(tournament) {
+			return
+		}
+	}
+}
+
+// startTournament starts the tournament if enough members have entered, or cancels it and refunds the
+// entry fees if they haven't. It returns whether the tournament was started.
+func startTournament(guildID string, id bson.ObjectID) bool {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil || tournament.ID != id || tournament.State != TournamentScheduled {
+		return false
+	}
+	delete(tournamentTimers, guildID)
+
+	if len(tournament.Entrants) < 2 {
+		tournament.State = TournamentCancelled
+		tournament.refundEntryFees()
+		writeTournament(tournament)
+		slog.Info("cancelled blackjack tournament without enough players", slog.String("guildID", guildID), slog.String("name", tournament.Name))
+		announceTournament(tournament, &discordgo.MessageEmbed{
+			Title:       tournament.Name,
+			Description: "The tournament has been cancelled, as not enough players entered. Entry fees have been refunded.",
+		}, nil)
+		return false
+	}
+
+	tournament.State = TournamentRunning
+	tournament.PrizePool = tournament.EntryFee * len(tournament.Entrants)
+	writeTournament(tournament)
+
+	slog.Info("started blackjack tournament",
+		slog.String("guildID", guildID),
+		slog.String("name", tournament.Name),
+		slog.Int("entrants", len(tournament.Entrants)),
+		slog.Int("prizePool", tournament.PrizePool),
+	)
+
+	return true
+}
+
+// recordTournamentRound saves the chip stacks after a round of the tournament has been played. It
+// returns false if the tournament was cancelled during the round.
+func recordTournamentRound(played *Tournament) bool {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(played.GuildID)
+	if tournament == nil || tournament.ID != played.ID {
+		return false
+	}
+	for _, entrant := range tournament.Entrants {
+		if playedEntrant := played.getEntrant(entrant.MemberID); playedEntrant != nil {
+			entrant.Chips = playedEntrant.Chips
+		}
+	}
+	tournament.RoundsPlayed++
+	writeTournament(tournament)
+
+	slog.Debug("played blackjack tournament round", slog.String("guildID", tournament.GuildID), slog.String("name", tournament.Name), slog.Int("round", tournament.RoundsPlayed))
+
+	return true
+}
+
+// finishTournament pays the prizes to the entrants with the largest chip stacks and posts the final
+// standings. The tournament is saved as completed before the prizes are paid, so a restart of the bot
+// part way through can't also refund the entry fees.
+func finishTournament(guildID string, id bson.ObjectID) {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil || tournament.ID != id {
+		return
+	}
+	tournament.awardPrizes()
+	tournament.State = TournamentCompleted
+	writeTournament(tournament)
+	tournament.payPrizes()
+
+	winner := tournament.rankings()[0]
+	slog.Info("blackjack tournament completed",
+		slog.String("guildID", guildID),
+		slog.String("name", tournament.Name),
+		slog.String("winner", winner.MemberID),
+		slog.Int("prizePool", tournament.PrizePool),
+	)
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	announceTournament(tournament, &discordgo.MessageEmbed{
+		Title:       p.Sprintf("%s: Final Standings", tournament.Name),
+		Description: p.Sprintf("<@%s> wins the tournament with %d chips!", winner.MemberID, winner.Chips),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   p.Sprintf("Standings (prize pool %d credits)", tournament.PrizePool),
+				Value:  unicode.Truncate(formatTournamentStandings(tournament), maxFieldLength),
+				Inline: false,
+			},
+		},
+	}, nil)
+}
+
+// newTournamentGame creates the table at which the rounds of the guild's tournament are played.
+func newTournamentGame(guildID string) *Game {
+	gamesLock.Lock()
+	defer gamesLock.Unlock()
+
+	uid := guildID + "-tournament"
+	game := newGame(guildID, uid, GetConfig(guildID).Decks)
+	games[uid] = game
+	slog.Info("created blackjack tournament game", slog.String("guildID", guildID), slog.String("uid", uid))
+
+	return game
+}
+
+// closeTournamentGame removes the tournament's table once the tournament is over.
+func closeTournamentGame(game *Game) {
+	gamesLock.Lock()
+	defer gamesLock.Unlock()
+
+	destroyButtons(game)
+	delete(games, game.uid)
+	slog.Info("deleted blackjack tournament game", slog.String("guildID", game.guildID), slog.String("uid", game.uid))
+}
+
+// startTournamentRound seats each entrant who can cover the minimum bet at the tournament's table with
+// their chosen bet.
+func (g *Game) startTournamentRound(tournament *Tournament) error {
+	g.Lock()
+	defer g.Unlock()
+
+	if !g.NotStarted() {
+		return ErrGameActive
+	}
+	g.tournament = tournament
+	g.config = tournament.tableConfig(GetConfig(g.guildID))
+	g.channelID = tournament.ChannelID
+	g.SetState(WaitingForPlayers)
+
+	for _, entrant := range tournament.activeEntrants() {
+		bet := min(entrant.Bet, entrant.Chips)
+		if err := g.addPlayer(entrant.MemberID, bet, SideBetStakes{}); err != nil {
+			slog.Error("failed to add player to blackjack tournament round",
+				slog.String("guildID", g.guildID),
+				slog.String("memberID", entrant.MemberID),
+				slog.Int("bet", bet),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	return nil
+}
+
+// tournamentStandingsEmbed returns the standings shown before each round of the tournament.
+func tournamentStandingsEmbed(tournament *Tournament) *discordgo.MessageEmbed {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return &discordgo.MessageEmbed{
+		Title:       p.Sprintf("%s: Round %d of %d", tournament.Name, tournament.RoundsPlayed+1, tournament.NumRounds),
+		Description: p.Sprintf("The next round is dealt <t:%d:R>. Bets are %d to %d chips.", time.Now().Add(tournamentBetTime).Unix(), tournament.MinBet, tournament.MaxBet),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   p.Sprintf("Standings (prize pool %d credits)", tournament.PrizePool),
+				Value:  unicode.Truncate(formatTournamentStandings(tournament), maxFieldLength),
+				Inline: false,
+			},
+		},
+	}
+}
+
+// tournamentBetComponents returns the button used by entrants to change their bet.
+func tournamentBetComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Change Bet",
+					Style:    discordgo.PrimaryButton,
+					CustomID: tournamentBetButtonID,
+				},
+			},
+		},
+	}
+}
+
+// formatTournamentStandings returns the leading entrants in the tournament, with their chips and bet or
+// prize. Entrants without enough chips to cover the minimum bet are marked as out.
+func formatTournamentStandings(tournament *Tournament) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	rankings := tournament.rankings()
+	if len(rankings) == 0 {
+		return "No players have entered"
+	}
+	var sb strings.Builder
+	for n, entrant := range rankings[:min(len(rankings), maxTournamentStandingsShow)] {
+		sb.WriteString(p.Sprintf("%d. %s: %d chips", n+1, entrant.Name, entrant.Chips))
+		switch {
+		case entrant.Prize > 0:
+			sb.WriteString(p.Sprintf(", wins %d credits", entrant.Prize))
+		case entrant.Chips < tournament.MinBet:
+			sb.WriteString(" (out)")
+		case tournament.State != TournamentCompleted:
+			sb.WriteString(p.Sprintf(" (bet %d)", min(entrant.Bet, entrant.Chips)))
+		}
+		sb.WriteString("\n")
+	}
+	if len(rankings) > maxTournamentStandingsShow {
+		sb.WriteString(p.Sprintf("...and %d more", len(rankings)-maxTournamentStandingsShow))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatPayouts returns the percent of the prize pool won by each finishing position.
+func formatPayouts(payouts []int) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	positions := make([]string, 0, len(payouts))
+	for n, percent := range payouts {
+		positions = append(positions, p.Sprintf("#%d: %d%%", n+1, percent))
+	}
+	return strings.Join(positions, ", ")
+}
+
+// announceTournament posts a message about the tournament to its channel.
+func announceTournament(tournament *Tournament, embed *discordgo.MessageEmbed, components []discordgo.MessageComponent) *discordgo.Message {
+	if bot == nil {
+		return nil
+	}
+	msg, err := bot.Session.ChannelMessageSendComplex(tournament.ChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: components,
+	})
+	if err != nil {
+		slog.Error("failed to send blackjack tournament message",
+			slog.String("guildID", tournament.GuildID),
+			slog.String("channelID", tournament.ChannelID),
+			slog.Any("error", err),
+		)
+		return nil
+	}
+	return msg
+}
+
+// removeTournamentComponents removes the buttons from a message about the tournament.
+func removeTournamentComponents(tournament *Tournament, msg *discordgo.Message) {
+	if msg == nil {
+		return
+	}
+	if _, err := bot.Session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    msg.ChannelID,
+		ID:         msg.ID,
+		Components: &[]discordgo.MessageComponent{},
+	}); err != nil {
+		slog.Error("failed to remove the blackjack tournament buttons", slog.String("guildID", tournament.GuildID), slog.Any("error", err))
+	}
+}
+
+// restoreTournaments sets the timers for the scheduled tournaments in all guilds. A tournament that was
+// being played when the bot stopped can't be resumed, so it is cancelled and the entry fees are refunded.
+// This is called when the bot starts.
+func restoreTournaments() {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	tournaments, err := readActiveTournaments()
+	if err != nil {
+		slog.Error("failed to read blackjack tournaments", slog.Any("error", err))
+		return
+	}
+	for _, tournament := range tournaments {
+		if tournament.State == TournamentScheduled {
+			scheduleTournament(tournament)
+			continue
+		}
+		tournament.State = TournamentCancelled
+		tournament.refundEntryFees()
+		writeTournament(tournament)
+		slog.Info("cancelled interrupted blackjack tournament", slog.String("guildID", tournament.GuildID), slog.String("name", tournament.Name))
+		announceTournament(tournament, &discordgo.MessageEmbed{
+			Title:       tournament.Name,
+			Description: "The tournament was interrupted and has been cancelled. Entry fees have been refunded.",
+		}, nil)
+	}
+
+	slog.Debug("restored blackjack tournaments", slog.Int("count", len(tournaments)))
+}
+
+// blackjackTournament routes the `blackjack tournament` commands to the proper handlers.
+func blackjackTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "join":
+		joinTournament(s, i)
+	case "leave":
+		leaveTournament(s, i)
+	case "standings":
+		tournamentStandings(s, i)
+	}
+}
+
+// adminTournament routes the `blackjack-admin tournament` commands to the proper handlers.
+func adminTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "create":
+		createTournament(s, i)
+	case "cancel":
+		cancelTournament(s, i)
+	}
+}
+
+// joinTournament enters the member into the blackjack tournament.
+func joinTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
+	tournament, err := JoinTournament(i.GuildID, i.Member.User.ID, guildMember.Name)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("%s has entered **%s**, which starts <t:%d:R>.", guildMember.Name, tournament.Name, tournament.Start.Unix()))).Send(s, i.Interaction)
+}
+
+// leaveTournament removes the member from the blackjack tournament.
+func leaveTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	tournament, err := LeaveTournament(i.GuildID, i.Member.User.ID)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	content := p.Sprintf("You have left **%s**.", tournament.Name)
+	if tournament.EntryFee > 0 {
+		content = p.Sprintf("You have left **%s**, and your entry fee of %d credits has been refunded.", tournament.Name, tournament.EntryFee)
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(content)).SendEphemeral(s, i.Interaction)
+}
+
+// tournamentStandings shows the format and current standings for the blackjack tournament.
+func tournamentStandings(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	tournament := GetTournament(i.GuildID)
+	if tournament == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrNoTournament.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	description := p.Sprintf("Round %d of %d is being played in <#%s>.", tournament.RoundsPlayed+1, tournament.NumRounds, tournament.ChannelID)
+	if tournament.State == TournamentScheduled {
+		description = p.Sprintf("Starts <t:%d:R> in <#%s>. Enter with `/blackjack tournament join`.", tournament.Start.Unix(), tournament.ChannelID)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       tournament.Name,
+		Description: description,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Format",
+				Value:  p.Sprintf("%d rounds. Entry costs %d credits for a stack of %d chips, and bets are %d to %d chips.", tournament.NumRounds, tournament.EntryFee, tournament.StartingChips, tournament.MinBet, tournament.MaxBet),
+				Inline: false,
+			},
+			{
+				Name:   "Payouts",
+				Value:  formatPayouts(tournament.Payouts),
+				Inline: false,
+			},
+			{
+				Name:   p.Sprintf("Standings (%d players)", len(tournament.Entrants)),
+				Value:  unicode.Truncate(formatTournamentStandings(tournament), maxFieldLength),
+				Inline: false,
+			},
+		},
+	}
+
+	disgomsg.NewResponse(disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed})).SendEphemeral(s, i.Interaction)
+}
+
+// createTournament schedules a new blackjack tournament.
+func createTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	config := GetConfig(i.GuildID)
+	var name, payouts string
+	var numRounds, entryFee, startingChips int
+	minBet, maxBet := config.MinBetAmount, config.MaxBetAmount
+	var signup time.Duration
+	channelID := i.ChannelID
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "name":
+			name = strings.TrimSpace(option.StringValue())
+		case "rounds":
+			numRounds = int(option.IntValue())
+		case "entry-fee":
+			entryFee = int(option.IntValue())
+		case "chips":
+			startingChips = int(option.IntValue())
+		case "min-bet":
+			minBet = int(option.IntValue())
+		case "max-bet":
+			maxBet = int(option.IntValue())
+		case "payouts":
+			payouts = option.StringValue()
+		case "signup":
+			signup = time.Duration(option.IntValue()) * time.Minute
+		case "channel":
+			channelID = option.ChannelValue(s).ID
+		}
+	}
+
+	tournament, err := NewTournament(name, channelID, numRounds, entryFee, startingChips, minBet, maxBet, payouts, signup)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to schedule the tournament: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if err := CreateTournament(i.GuildID, tournament); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to schedule the tournament: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("**%s** starts <t:%d:R> in <#%s>! Enter with `/blackjack tournament join` for %d credits to get %d chips and play %d rounds. The largest stacks split the prize pool (%s).",
+		tournament.Name, tournament.Start.Unix(), tournament.ChannelID, tournament.EntryFee, tournament.StartingChips, tournament.NumRounds, formatPayouts(tournament.Payouts)))).Send(s, i.Interaction)
+}
+
+// cancelTournament cancels the blackjack tournament.
+func cancelTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	tournament, err := CancelTournament(i.GuildID)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Cancelled the tournament %q. Entry fees have been refunded.", tournament.Name))).Send(s, i.Interaction)
+}
+
+// blackjackTournamentBet sends an entrant a form used to change their bet for the tournament's rounds.
+func blackjackTournamentBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	tournament := GetTournament(i.GuildID)
+	if tournament == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrNoTournament.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	entrant := tournament.getEntrant(i.Member.User.ID)
+	if entrant == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrNotInTournament.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: tournamentBetModalID,
+			Title:    "Tournament Bet",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:  betInputID,
+						Label:     p.Sprintf("Bet (%d to %d chips)", tournament.MinBet, tournament.MaxBet),
+						Style:     discordgo.TextInputShort,
+						Value:     p.Sprintf("%d", entrant.Bet),
+						Required:  true,
+						MaxLength: 12,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the blackjack tournament bet form", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+	}
+}
+
+// blackjackTournamentBetSubmit handles the bet entered by an entrant in the tournament.
+func blackjackTournamentBetSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	bet, err := form.ParseAmount(form.TextInputValue(i.ModalSubmitData().Components, betInputID), "chips")
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if _, err := SetTournamentBet(i.GuildID, i.Member.User.ID, bet); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You will bet %d chips on each round, starting with the next round.", bet))).SendEphemeral(s, i.Interaction)
+}
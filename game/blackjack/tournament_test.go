@@ -0,0 +1,100 @@
+package blackjack
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePayouts(t *testing.T) {
+	tests := []struct {
+		payouts string
+		want    []int
+		wantErr bool
+	}{
+		{"", defaultTournamentPayouts, false},
+		{"100", []int{100}, false},
+		{"60,40", []int{60, 40}, false},
+		{" 50%, 30% ,20 ", []int{50, 30, 20}, false},
+		{"50,30", nil, true},
+		{"50,30,30", nil, true},
+		{"50,abc,50", nil, true},
+		{"100,0", nil, true},
+		{"110,-10", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parsePayouts(tt.payouts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePayouts(%q) error = %v, expected an error: %t", tt.payouts, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parsePayouts(%q) = %v, expected %v", tt.payouts, got, tt.want)
+		}
+	}
+}
+
+func TestRankings(t *testing.T) {
+	tournament := &Tournament{
+		Entrants: []*TournamentEntrant{
+			{MemberID: "alice", Chips: 500},
+			{MemberID: "bob", Chips: 1500},
+			{MemberID: "carol", Chips: 500},
+			{MemberID: "dave", Chips: 0},
+		},
+	}
+
+	rankings := tournament.rankings()
+	var got []string
+	for _, entrant := range rankings {
+		got = append(got, entrant.MemberID)
+	}
+	// Ties keep the order in which the entrants joined
+	if want := []string{"bob", "alice", "carol", "dave"}; !slices.Equal(got, want) {
+		t.Errorf("rankings() = %v, expected %v", got, want)
+	}
+	if tournament.Entrants[0].MemberID != "alice" {
+		t.Error("expected rankings() to leave the entrants in the order they joined")
+	}
+}
+
+func TestAwardPrizes(t *testing.T) {
+	tests := []struct {
+		name      string
+		prizePool int
+		payouts   []int
+		chips     []int
+		want      []int
+	}{
+		{"three paid positions", 1000, []int{50, 30, 20}, []int{300, 900, 100, 500}, []int{200, 500, 0, 300}},
+		{"rounding goes to the winner", 1001, []int{50, 30, 20}, []int{300, 900, 100, 500}, []int{200, 501, 0, 300}},
+		{"fewer entrants than paid positions", 1000, []int{50, 30, 20}, []int{100, 900}, []int{300, 700}},
+		{"winner takes all", 1000, []int{100}, []int{100, 900, 500}, []int{0, 1000, 0}},
+		{"empty prize pool", 0, []int{50, 30, 20}, []int{100, 900}, []int{0, 0}},
+	}
+	for _, tt := range tests {
+		tournament := &Tournament{PrizePool: tt.prizePool, Payouts: tt.payouts}
+		for idx, chips := range tt.chips {
+			tournament.Entrants = append(tournament.Entrants, &TournamentEntrant{MemberID: string(rune('a' + idx)), Chips: chips})
+		}
+
+		tournament.awardPrizes()
+		got := make([]int, 0, len(tournament.Entrants))
+		total := 0
+		for _, entrant := range tournament.Entrants {
+			got = append(got, entrant.Prize)
+			total += entrant.Prize
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: prizes = %v, expected %v", tt.name, got, tt.want)
+		}
+		if total != tt.prizePool {
+			t.Errorf("%s: %d of the %d credit prize pool was awarded", tt.name, total, tt.prizePool)
+		}
+	}
+}
+
+func TestAwardPrizesWithoutEntrants(t *testing.T) {
+	// There is no one to award the prizes to, so this must not panic
+	tournament := &Tournament{PrizePool: 1000, Payouts: []int{50, 30, 20}}
+	tournament.awardPrizes()
+}
@@ -0,0 +1,120 @@
+package heist
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/rbrabson/disgomsg"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// PoliceAlertState is the time at which the police stop patrolling in a guild after a heist.
+// Police alerts are saved so they survive a restart of the bot.
+type PoliceAlertState struct {
+	ID        bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID   string        `json:"guild_id" bson:"guild_id"`
+	ExpiresAt time.Time     `json:"expires_at" bson:"expires_at"`
+	Announced bool          `json:"announced" bson:"announced"`
+}
+
+// getPoliceAlert returns the time at which the police alert for the guild expires.
+func getPoliceAlert(guildID string) time.Time {
+	alertTimesLock.Lock()
+	defer alertTimesLock.Unlock()
+
+	alertTime, ok := alertTimes[guildID]
+	if !ok {
+		if alert := readPoliceAlert(guildID); alert != nil {
+			alertTime = alert.ExpiresAt
+		}
+		alertTimes[guildID] = alertTime
+	}
+
+	return alertTime
+}
+
+// setPoliceAlert puts the police in the guild on alert until the given time, and
+// schedules the announcement for when the alert expires.
+func setPoliceAlert(guildID string, expiresAt time.Time) {
+	// The database only stores times to the millisecond, so truncate the time to allow it to
+	// be compared with the saved value when the alert expires.
+	expiresAt = expiresAt.Truncate(time.Millisecond)
+
+	alertTimesLock.Lock()
+	alertTimes[guildID] = expiresAt
+	alertTimesLock.Unlock()
+
+	alert := readPoliceAlert(guildID)
+	if alert == nil {
+		alert = &PoliceAlertState{GuildID: guildID}
+	}
+	alert.ExpiresAt = expiresAt
+	alert.Announced = false
+	writePoliceAlert(alert)
+
+	schedulePoliceAlertAnnouncement(alert)
+}
+
+// schedulePoliceAlertAnnouncement announces the end of the police alert once it expires.
+func schedulePoliceAlertAnnouncement(alert *PoliceAlertState) {
+	guildID := alert.GuildID
+	expiresAt := alert.ExpiresAt
+	time.AfterFunc(time.Until(expiresAt), func() {
+		announcePoliceAlertExpired(guildID, expiresAt)
+	})
+}
+
+// announcePoliceAlertExpired sends a message to the guild's announcement channel, if one is configured,
+// letting members know that a new heist may be planned. The announcement is skipped if the
+// police alert has since been extended by another heist.
+func announcePoliceAlertExpired(guildID string, expiresAt time.Time) {
+	alert := readPoliceAlert(guildID)
+	if alert == nil || alert.Announced || !alert.ExpiresAt.Equal(expiresAt) {
+		return
+	}
+	alert.Announced = true
+	writePoliceAlert(alert)
+
+	config := GetConfig(guildID)
+	if config.AlertChannelID == "" || bot == nil {
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	msg := disgomsg.NewMessage(
+		disgomsg.WithContent(p.Sprintf("The %s have gone back to sleep. It is safe to plan a new %s!", config.Theme.Police, config.Theme.Heist)),
+	)
+	if _, err := msg.Send(bot.Session, config.AlertChannelID); err != nil {
+		slog.Error("failed to send police alert announcement",
+			slog.String("guildID", guildID),
+			slog.String("channelID", config.AlertChannelID),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	slog.Debug("police alert expired",
+		slog.String("guildID", guildID),
+		slog.String("channelID", config.AlertChannelID),
+	)
+}
+
+// restorePoliceAlerts loads the police alerts that have not been announced and schedules
+// the announcement for each of them. This is called when the bot starts.
+func restorePoliceAlerts() {
+	alerts := readUnannouncedPoliceAlerts()
+
+	alertTimesLock.Lock()
+	for _, alert := range alerts {
+		alertTimes[alert.GuildID] = alert.ExpiresAt
+	}
+	alertTimesLock.Unlock()
+
+	for _, alert := range alerts {
+		schedulePoliceAlertAnnouncement(alert)
+	}
+
+	slog.Debug("restored police alerts", slog.Int("count", len(alerts)))
+}
@@ -0,0 +1,330 @@
+package heist
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rbrabson/disgomsg"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	BoostStartLayout = "2006-01-02 15:04"
+)
+
+var (
+	boostTimers     = make(map[string]*time.Timer)
+	boostTimersLock = sync.Mutex{}
+)
+
+// BoostRepeat is how often a scheduled boost repeats.
+type BoostRepeat string
+
+const (
+	RepeatNone   BoostRepeat = "none"
+	RepeatDaily  BoostRepeat = "daily"
+	RepeatWeekly BoostRepeat = "weekly"
+)
+
+// BoostSchedule is a window of time during which boosts are automatically enabled for heists.
+type BoostSchedule struct {
+	Name       string        `json:"name" bson:"name"`
+	Start      time.Time     `json:"start" bson:"start"`
+	TimeZone   string        `json:"time_zone" bson:"time_zone"`
+	Duration   time.Duration `json:"duration" bson:"duration"`
+	Percentage float64       `json:"percentage" bson:"percentage"`
+	Repeat     BoostRepeat   `json:"repeat" bson:"repeat"`
+}
+
+// Window returns the boost window that is active at the given time. If no window is active,
+// the next window is returned instead. If there are no more windows, then ok is false.
+func (b *BoostSchedule) Window(now time.Time) (start time.Time, end time.Time, ok bool) {
+	start = b.Start.In(b.location())
+	days := b.Repeat.days()
+	if days == 0 || now.Before(start) {
+		end = start.Add(b.Duration)
+		return start, end, now.Before(end)
+	}
+
+	// Find the most recent occurrence that started at or before the given time. Dates are
+	// added in the boost's time zone, so the boost starts at the same local time each day,
+	// even when daylight saving time starts or ends.
+	periods := int(now.Sub(start) / (time.Duration(days) * 24 * time.Hour))
+	occurrence := start.AddDate(0, 0, periods*days)
+	for occurrence.After(now) {
+		periods--
+		occurrence = start.AddDate(0, 0, periods*days)
+	}
+	for {
+		next := start.AddDate(0, 0, (periods+1)*days)
+		if next.After(now) {
+			break
+		}
+		periods++
+		occurrence = next
+	}
+
+	if now.Before(occurrence.Add(b.Duration)) {
+		return occurrence, occurrence.Add(b.Duration), true
+	}
+	occurrence = start.AddDate(0, 0, (periods+1)*days)
+	return occurrence, occurrence.Add(b.Duration), true
+}
+
+// IsActive returns true if the boost is active at the given time.
+func (b *BoostSchedule) IsActive(now time.Time) bool {
+	start, end, ok := b.Window(now)
+	return ok && !start.After(now) && now.Before(end)
+}
+
+// location returns the time zone in which the boost is scheduled.
+func (b *BoostSchedule) location() *time.Location {
+	if b.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(b.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// String returns a string representation of the BoostSchedule.
+func (b *BoostSchedule) String() string {
+	return fmt.Sprintf("BoostSchedule{Name=%s, Start=%s, TimeZone=%s, Duration=%s, Percentage=%.2f, Repeat=%s}",
+		b.Name,
+		b.Start,
+		b.TimeZone,
+		b.Duration,
+		b.Percentage,
+		b.Repeat,
+	)
+}
+
+// days returns the number of days between each occurrence of a repeating boost, or zero if
+// the boost does not repeat.
+func (r BoostRepeat) days() int {
+	switch r {
+	case RepeatDaily:
+		return 1
+	case RepeatWeekly:
+		return 7
+	default:
+		return 0
+	}
+}
+
+// NewBoostSchedule creates a new boost schedule. The start time is parsed in the given time zone,
+// which defaults to UTC.
+func NewBoostSchedule(name string, start string, timeZone string, duration time.Duration, percentage float64, repeat BoostRepeat) (*BoostSchedule, error) {
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", timeZone)
+	}
+	startTime, err := time.ParseInLocation(BoostStartLayout, start, loc)
+	if err != nil {
+		return nil, fmt.Errorf("the start time must be in the format `YYYY-MM-DD HH:MM`")
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("the duration must be greater than zero")
+	}
+	if days := repeat.days(); days > 0 && duration >= time.Duration(days)*24*time.Hour {
+		return nil, fmt.Errorf("the duration must be shorter than the time between %s boosts", repeat)
+	}
+	if percentage <= 0 {
+		return nil, fmt.Errorf("the percentage must be greater than zero")
+	}
+
+	schedule := &BoostSchedule{
+		Name:       name,
+		Start:      startTime,
+		TimeZone:   timeZone,
+		Duration:   duration,
+		Percentage: percentage,
+		Repeat:     repeat,
+	}
+	if _, _, ok := schedule.Window(time.Now()); !ok {
+		return nil, fmt.Errorf("the boost would end before it could start")
+	}
+
+	return schedule, nil
+}
+
+// AddBoostSchedule adds the boost schedule to the guild, replacing any existing schedule with the same name.
+func AddBoostSchedule(guildID string, schedule *BoostSchedule) {
+	config := GetConfig(guildID)
+	config.saveVaults()
+	schedules := make([]*BoostSchedule, 0, len(config.BoostSchedules)+1)
+	for _, existing := range config.BoostSchedules {
+		if existing.Name != schedule.Name {
+			schedules = append(schedules, existing)
+		}
+	}
+	config.BoostSchedules = append(schedules, schedule)
+	writeConfig(config)
+
+	scheduleBoost(guildID, schedule)
+	slog.Info("added boost schedule",
+		slog.String("guildID", guildID),
+		slog.String("schedule", schedule.String()),
+	)
+}
+
+// RemoveBoostSchedule removes the named boost schedule from the guild. It returns false if there is
+// no schedule with the name.
+func RemoveBoostSchedule(guildID string, name string) bool {
+	config := GetConfig(guildID)
+	schedules := make([]*BoostSchedule, 0, len(config.BoostSchedules))
+	for _, existing := range config.BoostSchedules {
+		if existing.Name != name {
+			schedules = append(schedules, existing)
+		}
+	}
+	if len(schedules) == len(config.BoostSchedules) {
+		return false
+	}
+	config.saveVaults()
+	config.BoostSchedules = schedules
+	writeConfig(config)
+
+	stopBoostTimer(guildID, name)
+	slog.Info("removed boost schedule",
+		slog.String("guildID", guildID),
+		slog.String("name", name),
+	)
+
+	return true
+}
+
+// scheduleBoost sets a timer for the next time the boost starts or ends.
+func scheduleBoost(guildID string, schedule *BoostSchedule) {
+	now := time.Now()
+	start, end, ok := schedule.Window(now)
+	if !ok {
+		stopBoostTimer(guildID, schedule.Name)
+		return
+	}
+	next := start
+	if !start.After(now) {
+		next = end
+	}
+
+	name := schedule.Name
+	timer := time.AfterFunc(time.Until(next), func() {
+		boostTransition(guildID, name)
+	})
+
+	boostTimersLock.Lock()
+	key := boostTimerKey(guildID, name)
+	if existing := boostTimers[key]; existing != nil {
+		existing.Stop()
+	}
+	boostTimers[key] = timer
+	boostTimersLock.Unlock()
+}
+
+// stopBoostTimer stops the timer for the boost schedule.
+func stopBoostTimer(guildID string, name string) {
+	boostTimersLock.Lock()
+	defer boostTimersLock.Unlock()
+
+	key := boostTimerKey(guildID, name)
+	if timer := boostTimers[key]; timer != nil {
+		timer.Stop()
+		delete(boostTimers, key)
+	}
+}
+
+// boostTimerKey returns the key used to track the timer for a boost schedule in a guild.
+func boostTimerKey(guildID string, name string) string {
+	return guildID + ":" + name
+}
+
+// boostTransition is called when a scheduled boost starts or ends. It announces the change and
+// schedules the next transition. Boosts that don't repeat are removed once they end.
+func boostTransition(guildID string, name string) {
+	config := GetConfig(guildID)
+	var schedule *BoostSchedule
+	for _, s := range config.BoostSchedules {
+		if s.Name == name {
+			schedule = s
+			break
+		}
+	}
+	if schedule == nil {
+		stopBoostTimer(guildID, name)
+		return
+	}
+
+	now := time.Now()
+	if schedule.IsActive(now) {
+		_, end, _ := schedule.Window(now)
+		announceBoost(config, schedule, end, true)
+		scheduleBoost(guildID, schedule)
+		return
+	}
+
+	announceBoost(config, schedule, time.Time{}, false)
+	if _, _, ok := schedule.Window(now); ok {
+		scheduleBoost(guildID, schedule)
+		return
+	}
+	RemoveBoostSchedule(guildID, name)
+}
+
+// announceBoost sends a message to the guild's announcement channel, if one is configured, when a
+// scheduled boost starts or ends.
+func announceBoost(config *Config, schedule *BoostSchedule, end time.Time, started bool) {
+	slog.Info("scheduled boost",
+		slog.String("guildID", config.GuildID),
+		slog.String("name", schedule.Name),
+		slog.Bool("started", started),
+	)
+	if config.AlertChannelID == "" || bot == nil {
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	var content string
+	if started {
+		content = p.Sprintf("**%s** has started! Every %s pays out %.0f%% more until <t:%d:t> (<t:%d:R>).",
+			schedule.Name, config.Theme.Heist, schedule.Percentage, end.Unix(), end.Unix())
+	} else {
+		content = p.Sprintf("**%s** has ended. Thanks for playing!", schedule.Name)
+	}
+
+	msg := disgomsg.NewMessage(disgomsg.WithContent(content))
+	if _, err := msg.Send(bot.Session, config.AlertChannelID); err != nil {
+		slog.Error("failed to send boost announcement",
+			slog.String("guildID", config.GuildID),
+			slog.String("channelID", config.AlertChannelID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// restoreBoostSchedules sets the timers for the scheduled boosts in all guilds. Boosts that don't
+// repeat and ended while the bot was stopped are removed. This is called when the bot starts.
+func restoreBoostSchedules() {
+	configs := readAllConfigs()
+	now := time.Now()
+	count := 0
+	for _, config := range configs {
+		for _, schedule := range config.BoostSchedules {
+			if _, _, ok := schedule.Window(now); !ok {
+				RemoveBoostSchedule(config.GuildID, schedule.Name)
+				continue
+			}
+			scheduleBoost(config.GuildID, schedule)
+			count++
+		}
+	}
+
+	slog.Debug("restored boost schedules", slog.Int("count", count))
+}
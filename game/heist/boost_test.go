@@ -0,0 +1,173 @@
+package heist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBoostScheduleWindow(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone data is not available")
+	}
+	start := time.Date(2025, time.March, 1, 18, 0, 0, 0, newYork)
+
+	tests := []struct {
+		name       string
+		repeat     BoostRepeat
+		now        time.Time
+		wantStart  time.Time
+		wantOK     bool
+		wantActive bool
+	}{
+		{
+			name:       "one-off before start",
+			repeat:     RepeatNone,
+			now:        start.Add(-time.Hour),
+			wantStart:  start,
+			wantOK:     true,
+			wantActive: false,
+		},
+		{
+			name:       "one-off active",
+			repeat:     RepeatNone,
+			now:        start.Add(30 * time.Minute),
+			wantStart:  start,
+			wantOK:     true,
+			wantActive: true,
+		},
+		{
+			name:       "one-off ended",
+			repeat:     RepeatNone,
+			now:        start.Add(2 * time.Hour),
+			wantStart:  start,
+			wantOK:     false,
+			wantActive: false,
+		},
+		{
+			name:       "daily active",
+			repeat:     RepeatDaily,
+			now:        start.AddDate(0, 0, 3).Add(59 * time.Minute),
+			wantStart:  start.AddDate(0, 0, 3),
+			wantOK:     true,
+			wantActive: true,
+		},
+		{
+			name:       "daily between windows",
+			repeat:     RepeatDaily,
+			now:        start.AddDate(0, 0, 3).Add(time.Hour),
+			wantStart:  start.AddDate(0, 0, 4),
+			wantOK:     true,
+			wantActive: false,
+		},
+		{
+			name:       "daily across daylight saving time",
+			repeat:     RepeatDaily,
+			now:        time.Date(2025, time.March, 10, 18, 30, 0, 0, newYork),
+			wantStart:  time.Date(2025, time.March, 10, 18, 0, 0, 0, newYork),
+			wantOK:     true,
+			wantActive: true,
+		},
+		{
+			name:       "weekly next window",
+			repeat:     RepeatWeekly,
+			now:        start.AddDate(0, 0, 2),
+			wantStart:  start.AddDate(0, 0, 7),
+			wantOK:     true,
+			wantActive: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := &BoostSchedule{
+				Name:       "test",
+				Start:      start.UTC(),
+				TimeZone:   "America/New_York",
+				Duration:   time.Hour,
+				Percentage: 25,
+				Repeat:     tt.repeat,
+			}
+			gotStart, gotEnd, ok := schedule.Window(tt.now)
+			if ok != tt.wantOK {
+				t.Errorf("expected ok=%t, got %t", tt.wantOK, ok)
+			}
+			if !gotStart.Equal(tt.wantStart) {
+				t.Errorf("expected start %s, got %s", tt.wantStart, gotStart)
+			}
+			if !gotEnd.Equal(tt.wantStart.Add(time.Hour)) {
+				t.Errorf("expected end %s, got %s", tt.wantStart.Add(time.Hour), gotEnd)
+			}
+			if active := schedule.IsActive(tt.now); active != tt.wantActive {
+				t.Errorf("expected active=%t, got %t", tt.wantActive, active)
+			}
+		})
+	}
+}
+
+func TestNewBoostSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    string
+		timeZone string
+		duration time.Duration
+		repeat   BoostRepeat
+		wantErr  bool
+	}{
+		{name: "valid", start: "2099-01-01 18:00", timeZone: "America/New_York", duration: time.Hour, repeat: RepeatWeekly},
+		{name: "bad time zone", start: "2099-01-01 18:00", timeZone: "Nowhere/Special", duration: time.Hour, repeat: RepeatNone, wantErr: true},
+		{name: "bad start", start: "tomorrow", duration: time.Hour, repeat: RepeatNone, wantErr: true},
+		{name: "no duration", start: "2099-01-01 18:00", repeat: RepeatNone, wantErr: true},
+		{name: "overlapping windows", start: "2099-01-01 18:00", duration: 24 * time.Hour, repeat: RepeatDaily, wantErr: true},
+		{name: "already ended", start: "2000-01-01 18:00", duration: time.Hour, repeat: RepeatNone, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBoostSchedule("test", tt.start, tt.timeZone, tt.duration, 25, tt.repeat)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVaultRecoveryRate(t *testing.T) {
+	active := &BoostSchedule{
+		Name:       "active",
+		Start:      time.Now().Add(-time.Hour),
+		Duration:   2 * time.Hour,
+		Percentage: 25,
+		Repeat:     RepeatNone,
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		want   float64
+	}{
+		{
+			name:   "no boost",
+			config: &Config{BaseVaultRecovery: 1, BoostVaultRecovery: 2},
+			want:   1,
+		},
+		{
+			name:   "manual boost without a percentage",
+			config: &Config{BaseVaultRecovery: 1, BoostVaultRecovery: 2, BoostEnabled: true},
+			want:   2,
+		},
+		{
+			name:   "scheduled boost",
+			config: &Config{BaseVaultRecovery: 1, BoostVaultRecovery: 2, BoostSchedules: []*BoostSchedule{active}},
+			want:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.VaultRecoveryRate(); got != tt.want {
+				t.Errorf("VaultRecoveryRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
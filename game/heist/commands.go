@@ -1,9 +1,13 @@
 package heist
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math"
+	"net/http"
 	"strings"
 	"time"
 
@@ -27,7 +31,9 @@ import (
 )
 
 const (
-	MaxWinningsPerPage = 30
+	MaxWinningsPerPage     = 30
+	MaxThemeFileSize       = 1024 * 1024
+	MaxThemeProblemsLength = 1800
 )
 
 // componentHandlers are the buttons that appear on messages sent by this bot.
@@ -58,6 +64,79 @@ var (
 						},
 					},
 				},
+				{
+					Name:        "boost-schedule",
+					Description: "Schedules boosts for the heist game.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "add",
+							Description: "Adds or replaces a scheduled boost.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of the boost, such as \"Weekend Boost\".",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "start",
+									Description: "The time the boost starts, in the format YYYY-MM-DD HH:MM.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "duration",
+									Description: "The length of the boost, in minutes.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        "percentage",
+									Description: "The percentage to increase payouts during the boost.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "repeat",
+									Description: "How often the boost repeats.",
+									Required:    false,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Never", Value: string(RepeatNone)},
+										{Name: "Daily", Value: string(RepeatDaily)},
+										{Name: "Weekly", Value: string(RepeatWeekly)},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "timezone",
+									Description: "The time zone for the start time, such as America/New_York. Defaults to UTC.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "list",
+							Description: "Lists the scheduled boosts.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "remove",
+							Description: "Removes a scheduled boost.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of the boost to remove.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
 				{
 					Name:        "clear",
 					Description: "Clears the criminal settings for the user.",
@@ -107,6 +186,19 @@ var (
 								},
 							},
 						},
+						{
+							Name:        "channel",
+							Description: "Sets the channel where police alerts and scheduled boosts are announced.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionChannel,
+									Name:        "id",
+									Description: "The channel for announcements. Omit to disable announcements.",
+									Required:    false,
+								},
+							},
+						},
 						{
 							Name:        "cost",
 							Description: "Sets the cost to plan or join a heist.",
@@ -192,6 +284,31 @@ var (
 					Description: "Resets a new heist that is hung.",
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 				},
+				{
+					Name:        "theme",
+					Description: "Exports or imports the heist theme.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "export",
+							Description: "Exports the current theme and targets as a JSON file.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "import",
+							Description: "Imports a theme and targets from a JSON file.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionAttachment,
+									Name:        "file",
+									Description: "The JSON file containing the theme and targets.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
 				{
 					Name:        "vault-reset",
 					Description: "Resets the vaults to their maximum value.",
@@ -219,6 +336,11 @@ var (
 						},
 					},
 				},
+				{
+					Name:        "profile",
+					Description: "Shows your criminal level, progress to the next level, and active perks.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+				},
 				{
 					Name:        "stats",
 					Description: "Shows a user's stats.",
@@ -256,10 +378,14 @@ func heistAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		clearMember(s, i)
 	case "boost":
 		enableBoost(s, i)
+	case "boost-schedule":
+		boostSchedule(s, i)
 	case "config":
 		config(s, i)
 	case "reset":
 		resetHeist(s, i)
+	case "theme":
+		theme(s, i)
 	case "vault-reset":
 		resetVaults(s, i)
 	}
@@ -269,6 +395,8 @@ func heistAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
 func config(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	options := i.ApplicationCommandData().Options[0].Options
 	switch options[0].Name {
+	case "channel":
+		configChannel(s, i)
 	case "cost":
 		configCost(s, i)
 	case "sentence":
@@ -290,6 +418,30 @@ func config(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 }
 
+// boostSchedule routes the boost schedule commands to the proper handlers.
+func boostSchedule(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "add":
+		addBoostSchedule(s, i)
+	case "list":
+		listBoostSchedules(s, i)
+	case "remove":
+		removeBoostSchedule(s, i)
+	}
+}
+
+// theme routes the theme commands to the proper handlers.
+func theme(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "export":
+		exportTheme(s, i)
+	case "import":
+		importTheme(s, i)
+	}
+}
+
 // heist routes the commands to the subcommand and subcommand group handlers
 func heist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if discord.IsShuttingDown(s, i) {
@@ -300,6 +452,8 @@ func heist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch options[0].Name {
 	case "bail":
 		bailoutPlayer(s, i)
+	case "profile":
+		playerProfile(s, i)
 	case "start":
 		startHeist(s, i)
 	case "stats":
@@ -315,6 +469,7 @@ func enableBoost(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	boostEnabled := options[0].BoolValue()
 
 	config := GetConfig(i.GuildID)
+	config.saveVaults()
 	config.BoostEnabled = boostEnabled
 	writeConfig(config)
 
@@ -361,12 +516,18 @@ func startHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 	heistMessage(s, heist)
+
+	// The heist is saved as completed before the crew is paid, so a restart during the payout
+	// doesn't also refund the cost of the heist. The outcome is recorded along with the seed, so
+	// it may be reproduced later.
+	writeHeistRecord(newHeistRecord(heist, res))
+	heist.State = Completed
+	heist.Save()
 	sendHeistResults(s, i, heist, res)
 
 	res.Target.StealFromVault(res.TotalStolen)
-	heist.State = Completed
 	heistMessage(s, heist)
-	slog.Info("heist completed", slog.String("guildID", heist.GuildID), slog.Int("stolenAmount", res.TotalStolen))
+	slog.Info("heist completed", slog.String("guildID", heist.GuildID), slog.Int64("seed", res.Seed), slog.Int("stolenAmount", res.TotalStolen))
 }
 
 // createHeist creates a new heist and sets the organizer. It also withdraws the cost of planning the heist
@@ -389,6 +550,7 @@ func createHeist(i *discordgo.InteractionCreate) (*Heist, error) {
 
 	heist.Organizer.guildMember.SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
 	heist.interaction = i
+	heist.Save()
 
 	return heist, nil
 }
@@ -583,15 +745,18 @@ func joinHeist(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
 		return
 	}
-	heistMessage(s, heist)
 
-	// Withdraw the cost of the heist from the player's account.
+	// Withdraw the cost of the heist from the player's account. A member who can't pay is taken back
+	// out of the crew.
 	account := bank.GetAccount(i.GuildID, heistMember.MemberID)
 	if err := account.Withdraw(heist.config.HeistCost); err != nil {
+		heist.RemoveCrewMember(heistMember)
 		slog.Error("failed to withdraw heist", slog.String("guildID", i.GuildID), slog.Any("error", err))
 		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Unable to join the heist. Error: %s", err.Error()))).SendEphemeral(s, i.Interaction)
 		return
 	}
+	heist.Save()
+	heistMessage(s, heist)
 
 	heistMember.guildMember.SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
 	p := message.NewPrinter(language.AmericanEnglish)
@@ -685,6 +850,67 @@ func playerStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	})
 }
 
+// playerProfile shows the criminal level of the player, the progress to the next level, and the perks
+// the player has unlocked.
+func playerProfile(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+
+	player := getHeistMember(i.GuildID, i.Member.User.ID)
+	player.guildMember.SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
+
+	theme := GetTheme(i.GuildID, HEIST_THEME)
+	if theme == nil {
+		slog.Error("failed to get heist theme", slog.String("guildID", i.GuildID))
+		disgomsg.NewResponse(disgomsg.WithContent("Internal error: failed to get the heist theme")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	progress := "Maximum level reached"
+	if next, ok := player.CriminalLevel.NextLevel(); ok {
+		progress = p.Sprintf("%s at level %d (%d to go)", next, next, next-player.CriminalLevel)
+	}
+
+	nextUnlock := "None"
+	if perk := nextPerk(theme, player.CriminalLevel); perk != nil {
+		unlocked := GetPerks(&Theme{Perks: []*LevelPerk{perk}}, perk.Level)
+		nextUnlock = p.Sprintf("At level %d (%s): %s", perk.Level, perk.Level, strings.Join(unlocked.Descriptions(theme), ", "))
+	}
+
+	perks := GetPerks(theme, player.CriminalLevel)
+	embeds := []*discordgo.MessageEmbed{
+		{
+			Type:        discordgo.EmbedTypeRich,
+			Title:       player.guildMember.Name,
+			Description: p.Sprintf("%s (level %d)", player.CriminalLevel, player.CriminalLevel),
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:   "Next Level",
+					Value:  progress,
+					Inline: false,
+				},
+				{
+					Name:   "Active Perks",
+					Value:  joinPerks(perks.Descriptions(theme)),
+					Inline: false,
+				},
+				{
+					Name:   "Next Perk",
+					Value:  nextUnlock,
+					Inline: false,
+				},
+			},
+		},
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: embeds,
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
 // bailoutPlayer bails a player out from jail. This defaults to the player initiating the command, but can
 // be another player as well.
 func bailoutPlayer(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -823,6 +1049,13 @@ func heistMessage(s *discordgo.Session, heist *Heist) error {
 			},
 		},
 	}
+	if boost := boostStatus(heist.config); boost != "" {
+		embeds[0].Fields = append(embeds[0].Fields, &discordgo.MessageEmbedField{
+			Name:   "Boost",
+			Value:  boost,
+			Inline: true,
+		})
+	}
 	components := []discordgo.MessageComponent{
 		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
 			discordgo.Button{
@@ -844,6 +1077,18 @@ func heistMessage(s *discordgo.Session, heist *Heist) error {
 	return nil
 }
 
+// boostStatus returns a description of the boost that is currently active, or an empty string if
+// there is no active boost.
+func boostStatus(config *Config) string {
+	if boost, end := config.ActiveBoost(); boost != nil {
+		return fmt.Sprintf("%s: +%.0f%%, ends <t:%d:R>", boost.Name, boost.Percentage, end.Unix())
+	}
+	if percentage := config.CurrentBoostPercentage(); percentage > 0 {
+		return fmt.Sprintf("+%.0f%%", percentage)
+	}
+	return ""
+}
+
 /******** ADMIN COMMANDS ********/
 
 // Reset resets the heist in case it hangs
@@ -877,15 +1122,116 @@ func resetVaults(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	disgomsg.NewResponse(disgomsg.WithContent("Vaults have been reset to their maximum value")).Send(s, i.Interaction)
 }
 
+// exportTheme sends the current theme and targets as a JSON attachment.
+func exportTheme(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data, err := ExportTheme(i.GuildID)
+	if err != nil {
+		slog.Error("failed to export the heist theme", slog.String("guildID", i.GuildID), slog.Any("error", err))
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to export the theme: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Heist Theme",
+			Files: []*discordgo.File{
+				{
+					Name:        HEIST_THEME + ".json",
+					ContentType: "application/json",
+					Reader:      bytes.NewReader(data),
+				},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the heist theme", slog.String("guildID", i.GuildID), slog.Any("error", err))
+	}
+}
+
+// importTheme replaces the current theme and targets with those in the JSON attachment.
+func importTheme(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	attachmentID := options[0].Value.(string)
+	attachment := i.ApplicationCommandData().Resolved.Attachments[attachmentID]
+	if attachment == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No theme file was attached.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if attachment.Size > MaxThemeFileSize {
+		p := message.NewPrinter(language.AmericanEnglish)
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("The theme file is too large. The maximum size is %d bytes.", MaxThemeFileSize))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	data, err := downloadAttachment(attachment)
+	if err != nil {
+		slog.Error("failed to download the heist theme", slog.String("guildID", i.GuildID), slog.String("url", attachment.URL), slog.Any("error", err))
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to download the theme file: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	themeFile, err := ImportTheme(i.GuildID, data)
+	if err != nil {
+		slog.Warn("failed to import the heist theme", slog.String("guildID", i.GuildID), slog.Any("error", err))
+		disgomsg.NewResponse(disgomsg.WithContent(formatThemeProblems(err))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Imported the theme with %d targets.", len(themeFile.Targets)))).SendEphemeral(s, i.Interaction)
+}
+
+// downloadAttachment returns the contents of a file attached to an interaction.
+func downloadAttachment(attachment *discordgo.MessageAttachment) ([]byte, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(attachment.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, MaxThemeFileSize))
+}
+
+// formatThemeProblems returns the message sent when a theme fails to import, listing each
+// problem on a separate line. The list is truncated to fit within a Discord message.
+func formatThemeProblems(err error) string {
+	var invalidTheme ErrInvalidTheme
+	if !errors.As(err, &invalidTheme) {
+		return "Unable to import the theme: " + err.Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("The theme was not imported. Please correct the following problems:\n```\n")
+	for idx, problem := range invalidTheme.Problems {
+		line := problem + "\n"
+		if sb.Len()+len(line) > MaxThemeProblemsLength {
+			sb.WriteString(fmt.Sprintf("... and %d more\n", len(invalidTheme.Problems)-idx))
+			break
+		}
+		sb.WriteString(line)
+	}
+	sb.WriteString("```")
+
+	return sb.String()
+}
+
 // listTargets displays a list of available heist targets.
 func listTargets(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	theme := GetTheme(i.GuildID, HEIST_THEME)
+	config := GetConfig(i.GuildID)
+	theme := config.Theme
 	if theme == nil {
 		disgomsg.NewResponse(disgomsg.WithContent("There aren't any targets!")).SendEphemeral(s, i.Interaction)
 		return
 	}
 
-	targets := GetTargets(i.GuildID, theme.Name)
+	targets := config.Targets
 	if len(targets) == 0 {
 		disgomsg.NewResponse(disgomsg.WithContent("There aren't any targets!")).SendEphemeral(s, i.Interaction)
 		return
@@ -927,7 +1273,11 @@ func listTargets(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		slog.Error("failed to render the table", slog.Any("error", err))
 	}
 
-	disgomsg.NewResponse(disgomsg.WithContent("```\n"+tableBuffer.String()+"\n```")).SendEphemeral(s, i.Interaction)
+	content := "```\n" + tableBuffer.String() + "\n```"
+	if boost := boostStatus(config); boost != "" {
+		content = "**Boost active** " + boost + "\n" + content
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(content)).SendEphemeral(s, i.Interaction)
 }
 
 // clearMember clears the criminal state of the player.
@@ -943,6 +1293,112 @@ func clearMember(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Cleared %s's criminal record", member.Name))).Send(s, i.Interaction)
 }
 
+// addBoostSchedule adds a scheduled boost for the heist game.
+func addBoostSchedule(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var name, start, timeZone string
+	var duration time.Duration
+	var percentage float64
+	repeat := RepeatNone
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "name":
+			name = strings.TrimSpace(option.StringValue())
+		case "start":
+			start = strings.TrimSpace(option.StringValue())
+		case "duration":
+			duration = time.Duration(option.IntValue()) * time.Minute
+		case "percentage":
+			percentage = option.FloatValue()
+		case "repeat":
+			repeat = BoostRepeat(option.StringValue())
+		case "timezone":
+			timeZone = strings.TrimSpace(option.StringValue())
+		}
+	}
+
+	schedule, err := NewBoostSchedule(name, start, timeZone, duration, percentage, repeat)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to schedule the boost: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+	AddBoostSchedule(i.GuildID, schedule)
+
+	disgomsg.NewResponse(disgomsg.WithContent("Scheduled "+formatBoostSchedule(schedule, time.Now()))).Send(s, i.Interaction)
+}
+
+// listBoostSchedules lists the scheduled boosts for the heist game.
+func listBoostSchedules(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	if len(config.BoostSchedules) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent("There aren't any scheduled boosts.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	now := time.Now()
+	lines := make([]string, 0, len(config.BoostSchedules))
+	for _, schedule := range config.BoostSchedules {
+		lines = append(lines, "- "+formatBoostSchedule(schedule, now))
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(strings.Join(lines, "\n"))).SendEphemeral(s, i.Interaction)
+}
+
+// removeBoostSchedule removes a scheduled boost from the heist game.
+func removeBoostSchedule(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	name := strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue())
+	if !RemoveBoostSchedule(i.GuildID, name) {
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("There is no scheduled boost named %q.", name))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Removed the scheduled boost %q.", name))).Send(s, i.Interaction)
+}
+
+// formatBoostSchedule returns a description of the scheduled boost, including when it is next active.
+func formatBoostSchedule(schedule *BoostSchedule, now time.Time) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	start, end, ok := schedule.Window(now)
+	var when string
+	switch {
+	case !ok:
+		when = "ended"
+	case schedule.IsActive(now):
+		when = fmt.Sprintf("active, ends <t:%d:R>", end.Unix())
+	default:
+		when = fmt.Sprintf("starts <t:%d:f> (<t:%d:R>)", start.Unix(), start.Unix())
+	}
+
+	return p.Sprintf("**%s**: +%.0f%% for %s, repeats %s, %s",
+		schedule.Name,
+		schedule.Percentage,
+		format.Duration(schedule.Duration),
+		schedule.Repeat,
+		when,
+	)
+}
+
+// configChannel sets the channel where police alerts and scheduled boosts are announced.
+func configChannel(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	options := i.ApplicationCommandData().Options[0].Options[0].Options
+	if len(options) == 0 {
+		config.AlertChannelID = ""
+		writeConfig(config)
+		disgomsg.NewResponse(disgomsg.WithContent("Announcements have been disabled")).Send(s, i.Interaction)
+		return
+	}
+
+	channelID := options[0].ChannelValue(s).ID
+	if _, err := s.State.Channel(channelID); err != nil {
+		slog.Error("failed to get channel from state", slog.String("guildID", i.GuildID), slog.String("channelID", channelID), slog.Any("error", err))
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Failed to get channel %s: %s", channelID, err))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	config.AlertChannelID = channelID
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Announcements will be sent to <#%s>", channelID))).Send(s, i.Interaction)
+}
+
 // configCost sets the cost to plan or join a heist
 func configCost(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	config := GetConfig(i.GuildID)
@@ -1039,11 +1495,12 @@ func configDeath(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	writeConfig(config)
 }
 
-// configBoostVaultRecovery sets the percentage of the vault that is recovered every minute when boosts are enabled.
+// configBoostVaultRecovery sets the percentage of the vault that is recovered every recovery interval when boosts are enabled.
 func configBoostVaultRecovery(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	config := GetConfig(i.GuildID)
 	options := i.ApplicationCommandData().Options[0].Options[0].Options
 	boostVaultRecovery := options[0].IntValue()
+	config.saveVaults()
 	config.BoostVaultRecovery = float64(boostVaultRecovery) / 100.0
 
 	p := message.NewPrinter(language.AmericanEnglish)
@@ -1069,6 +1526,11 @@ func configWait(s *discordgo.Session, i *discordgo.InteractionCreate) {
 func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	config := GetConfig(i.GuildID)
 
+	channelValue := "None"
+	if config.AlertChannelID != "" {
+		channelValue = fmt.Sprintf("<#%s>", config.AlertChannelID)
+	}
+
 	embed := &discordgo.MessageEmbed{
 		Fields: []*discordgo.MessageEmbedField{
 			{
@@ -1086,6 +1548,16 @@ func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				Value:  fmt.Sprintf("%t", config.BoostEnabled),
 				Inline: true,
 			},
+			{
+				Name:   "boost schedules",
+				Value:  fmt.Sprintf("%d", len(config.BoostSchedules)),
+				Inline: true,
+			},
+			{
+				Name:   "channel",
+				Value:  channelValue,
+				Inline: true,
+			},
 			{
 				Name:   "cost",
 				Value:  fmt.Sprintf("%d", config.HeistCost),
@@ -23,21 +23,23 @@ const (
 
 // Config is the configuration data for new heists
 type Config struct {
-	ID                 bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID            string        `json:"guild_id" bson:"guild_id"`
-	BailBase           int           `json:"bail_base" bson:"bail_base"`
-	BoostPercentage    float64       `json:"boost_percentage" bson:"boost_percentage"`
-	BoostEnabled       bool          `json:"boost_enabled" bson:"boost_enabled"`
-	CrewOutput         string        `json:"crew_output" bson:"crew_output"`
-	DeathTimer         time.Duration `json:"death_timer" bson:"death_timer"`
-	HeistCost          int           `json:"heist_cost" bson:"heist_cost"`
-	PoliceAlert        time.Duration `json:"police_alert" bson:"police_alert"`
-	SentenceBase       time.Duration `json:"sentence_base" bson:"sentence_base"`
-	BaseVaultRecovery  float64       `json:"base_vault_recovery" bson:"base_vault_recovery"`
-	BoostVaultRecovery float64       `json:"boost_vault_recovery" bson:"boost_vault_recovery"`
-	WaitTime           time.Duration `json:"wait_time" bson:"wait_time"`
-	Theme              *Theme        `json:"-" bson:"-"`
-	Targets            []*Target     `json:"-" bson:"-"`
+	ID                 bson.ObjectID    `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID            string           `json:"guild_id" bson:"guild_id"`
+	AlertChannelID     string           `json:"alert_channel_id" bson:"alert_channel_id"`
+	BailBase           int              `json:"bail_base" bson:"bail_base"`
+	BoostPercentage    float64          `json:"boost_percentage" bson:"boost_percentage"`
+	BoostEnabled       bool             `json:"boost_enabled" bson:"boost_enabled"`
+	CrewOutput         string           `json:"crew_output" bson:"crew_output"`
+	DeathTimer         time.Duration    `json:"death_timer" bson:"death_timer"`
+	HeistCost          int              `json:"heist_cost" bson:"heist_cost"`
+	PoliceAlert        time.Duration    `json:"police_alert" bson:"police_alert"`
+	SentenceBase       time.Duration    `json:"sentence_base" bson:"sentence_base"`
+	BaseVaultRecovery  float64          `json:"base_vault_recovery" bson:"base_vault_recovery"`
+	BoostVaultRecovery float64          `json:"boost_vault_recovery" bson:"boost_vault_recovery"`
+	WaitTime           time.Duration    `json:"wait_time" bson:"wait_time"`
+	BoostSchedules     []*BoostSchedule `json:"boost_schedules" bson:"boost_schedules"`
+	Theme              *Theme           `json:"-" bson:"-"`
+	Targets            []*Target        `json:"-" bson:"-"`
 }
 
 // GetConfig retrieves the heist configuration for the specified guild. If
@@ -54,9 +56,92 @@ func GetConfig(guildID string) *Config {
 	}
 	config.Theme = GetTheme(guildID, HEIST_THEME)
 	config.Targets = GetTargets(guildID, config.Theme.Name)
+	recoverVaults(config.Targets, config.vaultRecoveryRateAt)
 	return config
 }
 
+// saveVaults writes the recovered vault balances for the guild's targets. It must be called before
+// the boost or vault recovery rates are changed, so the time that has already passed is recovered
+// at the rates that were in force.
+func (config *Config) saveVaults() {
+	for _, target := range config.Targets {
+		writeTarget(target)
+	}
+}
+
+// VaultRecoveryRate returns the percentage of a vault's maximum value that is recovered
+// during each recovery interval, taking into account whether a boost is active.
+func (config *Config) VaultRecoveryRate() float64 {
+	rate, _ := config.vaultRecoveryRateAt(time.Now())
+	return rate
+}
+
+// vaultRecoveryRateAt returns the vault recovery rate in force at the given time, along with the
+// time at which a scheduled boost next starts or ends. A manually enabled boost is treated as
+// having always been on, as the vaults are saved whenever it is turned on or off.
+func (config *Config) vaultRecoveryRateAt(at time.Time) (float64, time.Time) {
+	boosted := config.BoostEnabled
+	var until time.Time
+	for _, schedule := range config.BoostSchedules {
+		start, end, ok := schedule.Window(at)
+		if !ok {
+			continue
+		}
+		change := start
+		if !start.After(at) {
+			boosted = true
+			change = end
+		}
+		if until.IsZero() || change.Before(until) {
+			until = change
+		}
+	}
+	if boosted {
+		return config.BoostVaultRecovery, until
+	}
+	return config.BaseVaultRecovery, until
+}
+
+// ActiveBoost returns the scheduled boost that is currently active, along with the time at
+// which it ends. If more than one scheduled boost is active, the one with the largest
+// percentage is returned. If no scheduled boost is active, nil is returned.
+func (config *Config) ActiveBoost() (*BoostSchedule, time.Time) {
+	now := time.Now()
+	var active *BoostSchedule
+	var end time.Time
+	for _, schedule := range config.BoostSchedules {
+		if !schedule.IsActive(now) {
+			continue
+		}
+		if active == nil || schedule.Percentage > active.Percentage {
+			active = schedule
+			_, end, _ = schedule.Window(now)
+		}
+	}
+	return active, end
+}
+
+// IsBoostActive returns true if boosts are enabled or a scheduled boost is active.
+func (config *Config) IsBoostActive() bool {
+	if config.BoostEnabled {
+		return true
+	}
+	boost, _ := config.ActiveBoost()
+	return boost != nil
+}
+
+// CurrentBoostPercentage returns the percentage by which payouts are currently increased. A
+// scheduled boost takes precedence over a boost that was enabled manually.
+func (config *Config) CurrentBoostPercentage() float64 {
+	if boost, _ := config.ActiveBoost(); boost != nil {
+		return boost.Percentage
+	}
+	if config.BoostEnabled && config.BoostPercentage > 0 {
+		return config.BoostPercentage
+	}
+	return 0
+}
+
 // readConfigFromFile creates a new default configuration for the specified guild.
 // If the default configuration file cannot be read or decoded, then a default
 // configuration is created.
@@ -7,8 +7,11 @@ import (
 )
 
 const (
+	alertCollection  = "heist_alerts"
 	configCollection = "heist_configs"
+	heistCollection  = "heist_heists"
 	memberCollection = "heist_members"
+	recordCollection = "heist_records"
 	targetCollection = "heist_targets"
 	themeCollection  = "heist_themes"
 )
@@ -27,6 +30,18 @@ func readConfig(guildID string) *Config {
 	return &config
 }
 
+// readAllConfigs loads the heist configuration for all guilds.
+func readAllConfigs() []*Config {
+	var configs []*Config
+	err := db.FindMany(configCollection, bson.M{}, &configs, bson.M{}, 0)
+	if err != nil {
+		slog.Error("unable to read heist configurations", slog.Any("error", err))
+		return nil
+	}
+
+	return configs
+}
+
 // writeConfig stores the configuration in the database.
 func writeConfig(config *Config) {
 	var filter bson.M
@@ -96,7 +111,7 @@ func readTargets(guildID string, theme string) ([]*Target, error) {
 }
 
 // writeTarget writes the set of targets to the database. If they already exist, they are updated; otherwise, the set is created.
-func writeTarget(target *Target) {
+func writeTarget(target *Target) error {
 	var filter bson.M
 	if target.ID != bson.NilObjectID {
 		filter = bson.M{"_id": target.ID}
@@ -106,8 +121,21 @@ func writeTarget(target *Target) {
 
 	if err := db.UpdateOrInsert(targetCollection, filter, target); err != nil {
 		slog.Error("error writing target to database", slog.String("guildID", target.GuildID), slog.String("targetID", target.Name), slog.Any("error", err))
-		return
+		return err
 	}
+
+	return nil
+}
+
+// deleteTargets removes all targets for the given guild and theme from the database.
+func deleteTargets(guildID string, theme string) error {
+	filter := bson.M{"guild_id": guildID, "theme": theme}
+	if err := db.DeleteMany(targetCollection, filter); err != nil {
+		slog.Error("error deleting targets from the database", slog.String("guildID", guildID), slog.String("theme", theme), slog.Any("error", err))
+		return err
+	}
+
+	return nil
 }
 
 // readAllThemes loads all available themes for a guild
@@ -137,7 +165,12 @@ func readTheme(guildID string, themeName string) (*Theme, error) {
 }
 
 // write creates or updates the theme in the database
-func writeTheme(theme *Theme) {
+func writeTheme(theme *Theme) error {
+	// Perks are always saved, so a theme without perks isn't given the default perks when it is read.
+	if theme.Perks == nil {
+		theme.Perks = make([]*LevelPerk, 0)
+	}
+
 	var filter bson.M
 	if theme.ID != bson.NilObjectID {
 		filter = bson.M{"_id": theme.ID}
@@ -146,6 +179,83 @@ func writeTheme(theme *Theme) {
 	}
 	if err := db.UpdateOrInsert(themeCollection, filter, theme); err != nil {
 		slog.Error("error writing theme to the database", slog.String("guildID", theme.GuildID), slog.String("name", theme.Name), slog.Any("error", err))
-		return
+		return err
+	}
+
+	return nil
+}
+
+// readPoliceAlert loads the police alert for a guild. If it does not exist, then a `nil` value is returned.
+func readPoliceAlert(guildID string) *PoliceAlertState {
+	var alert PoliceAlertState
+	filter := bson.M{"guild_id": guildID}
+	err := db.FindOne(alertCollection, filter, &alert)
+	if err != nil {
+		slog.Debug("police alert not found in the database", slog.String("guildID", guildID), slog.Any("error", err))
+		return nil
+	}
+
+	return &alert
+}
+
+// readUnannouncedPoliceAlerts loads the police alerts, for all guilds, whose expiration has not been announced.
+func readUnannouncedPoliceAlerts() []*PoliceAlertState {
+	var alerts []*PoliceAlertState
+	filter := bson.M{"announced": false}
+	err := db.FindMany(alertCollection, filter, &alerts, bson.M{}, 0)
+	if err != nil {
+		slog.Error("unable to read police alerts", slog.Any("error", err))
+		return nil
+	}
+
+	return alerts
+}
+
+// writePoliceAlert creates or updates the police alert in the database.
+func writePoliceAlert(alert *PoliceAlertState) {
+	var filter bson.M
+	if alert.ID != bson.NilObjectID {
+		filter = bson.M{"_id": alert.ID}
+	} else {
+		filter = bson.M{"guild_id": alert.GuildID}
+	}
+	if err := db.UpdateOrInsert(alertCollection, filter, alert); err != nil {
+		slog.Error("error writing police alert to the database", slog.String("guildID", alert.GuildID), slog.Any("error", err))
+	}
+}
+
+// readSavedHeists loads the saved heists for all guilds.
+func readSavedHeists() []*SavedHeist {
+	var heists []*SavedHeist
+	err := db.FindMany(heistCollection, bson.M{}, &heists, bson.M{}, 0)
+	if err != nil {
+		slog.Error("unable to read saved heists", slog.Any("error", err))
+		return nil
+	}
+
+	return heists
+}
+
+// writeSavedHeist creates or updates the saved heist for a guild in the database.
+func writeSavedHeist(heist *SavedHeist) {
+	filter := bson.M{"guild_id": heist.GuildID}
+	if err := db.UpdateOrInsert(heistCollection, filter, heist); err != nil {
+		slog.Error("error writing heist to the database", slog.String("guildID", heist.GuildID), slog.Any("error", err))
+	}
+}
+
+// deleteSavedHeist removes the saved heist for a guild from the database.
+func deleteSavedHeist(guildID string) {
+	filter := bson.M{"guild_id": guildID}
+	if err := db.DeleteMany(heistCollection, filter); err != nil {
+		slog.Error("error deleting heist from the database", slog.String("guildID", guildID), slog.Any("error", err))
+	}
+}
+
+// writeHeistRecord stores the outcome of a completed heist in the database.
+func writeHeistRecord(record *HeistRecord) {
+	filter := bson.M{"_id": record.ID}
+	if err := db.UpdateOrInsert(recordCollection, filter, record); err != nil {
+		slog.Error("error writing heist record to the database", slog.String("guildID", record.GuildID), slog.Int64("seed", record.Seed), slog.Any("error", err))
 	}
 }
@@ -3,6 +3,7 @@ package heist
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rbrabson/goblin/internal/format"
@@ -17,6 +18,16 @@ var (
 	ErrThemeNotFound       = errors.New("no theme was not found")
 )
 
+// ErrInvalidTheme is returned when a theme being imported fails validation.
+type ErrInvalidTheme struct {
+	Problems []string
+}
+
+// Error returns the error message for ErrInvalidTheme.
+func (e ErrInvalidTheme) Error() string {
+	return "the theme is not valid:\n" + strings.Join(e.Problems, "\n")
+}
+
 // ErrNotEnoughMembers is returned when there are not enough members to start a heist.
 type ErrNotEnoughMembers struct {
 	Theme *Theme
@@ -49,6 +60,18 @@ func (e ErrNotEnoughCredits) Error() string {
 	)
 }
 
+// ErrCrewFull is returned when the crew for a heist has no more open slots.
+type ErrCrewFull struct {
+	Crew    string
+	MaxCrew int
+}
+
+// Error returns the error message for ErrCrewFull.
+func (e ErrCrewFull) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("The %s is full. No more than %d members may join.", e.Crew, e.MaxCrew)
+}
+
 // ErrPoliceOnAlert is returned when the police are on high alert after the last target.
 type ErrPoliceOnAlert struct {
 	Police        string
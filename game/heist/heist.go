@@ -11,8 +11,10 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/disgomsg"
 	"github.com/rbrabson/goblin/bank"
 	"github.com/rbrabson/goblin/stats"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
@@ -36,6 +38,7 @@ const (
 // Heist is a heist that is being planned, is in progress, or has completed
 type Heist struct {
 	GuildID      string
+	Seed         int64
 	Organizer    *HeistMember
 	Crew         []*HeistMember
 	StartTime    time.Time
@@ -46,6 +49,21 @@ type Heist struct {
 	mutex        sync.Mutex
 	goodMessages []*HeistMessage
 	badMessages  []*HeistMessage
+	rng          *rand.Rand
+}
+
+// SavedHeist is the saved state of a heist that is being planned or is in progress. It is used to
+// cancel a heist that is interrupted by a restart of the bot.
+type SavedHeist struct {
+	ID          bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID     string        `json:"guild_id" bson:"guild_id"`
+	ChannelID   string        `json:"channel_id" bson:"channel_id"`
+	OrganizerID string        `json:"organizer_id" bson:"organizer_id"`
+	CrewIDs     []string      `json:"crew_ids" bson:"crew_ids"`
+	HeistCost   int           `json:"heist_cost" bson:"heist_cost"`
+	StartTime   time.Time     `json:"start_time" bson:"start_time"`
+	State       HeistState    `json:"state" bson:"state"`
+	Seed        int64         `json:"seed" bson:"seed"`
 }
 
 // HeistResult are the results of a heist
@@ -56,6 +74,7 @@ type HeistResult struct {
 	Dead        []*HeistMemberResult
 	Target      *Target
 	TotalStolen int
+	Seed        int64
 	heist       *Heist
 }
 
@@ -87,8 +106,10 @@ func NewHeist(guildID string, memberID string) (*Heist, error) {
 	}
 
 	config := GetConfig(guildID)
+	seed := rand.Int64()
 	heist := &Heist{
 		GuildID:      guildID,
+		Seed:         seed,
 		Organizer:    getHeistMember(guildID, memberID),
 		Crew:         make([]*HeistMember, 0, 10),
 		StartTime:    time.Now(),
@@ -97,6 +118,7 @@ func NewHeist(guildID string, memberID string) (*Heist, error) {
 		mutex:        sync.Mutex{},
 		goodMessages: make([]*HeistMessage, 0, len(config.Theme.EscapedMessages)),
 		badMessages:  make([]*HeistMessage, 0, len(config.Theme.ApprehendedMessages)+len(config.Theme.DiedMessages)),
+		rng:          newHeistRand(seed),
 	}
 
 	err := heistChecks(heist, heist.Organizer)
@@ -130,6 +152,17 @@ func (h *Heist) AddCrewMember(member *HeistMember) error {
 	return nil
 }
 
+// RemoveCrewMember removes a member who couldn't pay to join the heist from the crew.
+func (h *Heist) RemoveCrewMember(member *HeistMember) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.Crew = slices.DeleteFunc(h.Crew, func(m *HeistMember) bool {
+		return m == member
+	})
+	slog.Debug("member removed from heist", slog.String("guildID", member.GuildID), slog.String("memberID", member.MemberID))
+}
+
 // Start runs the heist and returns the results of the heist.
 func (h *Heist) Start() (*HeistResult, error) {
 	h.mutex.Lock()
@@ -141,7 +174,9 @@ func (h *Heist) Start() (*HeistResult, error) {
 	}
 
 	h.State = InProgress
-	target := getTarget(h.config.Targets, len(h.Crew))
+	h.save()
+	organizerPerks := GetPerks(h.config.Theme, h.Organizer.CriminalLevel)
+	target := getTarget(h.config.Targets, len(h.Crew), organizerPerks.TargetTier)
 
 	results := &HeistResult{
 		AllResults:  make([]*HeistMemberResult, 0, len(h.Crew)),
@@ -150,6 +185,7 @@ func (h *Heist) Start() (*HeistResult, error) {
 		Dead:        make([]*HeistMemberResult, 0, len(h.Crew)),
 		heist:       h,
 		Target:      target,
+		Seed:        h.Seed,
 	}
 
 	successRate := calculateSuccessRate(h, target)
@@ -157,8 +193,9 @@ func (h *Heist) Start() (*HeistResult, error) {
 		guildMember := crewMember.guildMember
 		heistMember := getHeistMember(guildMember.GuildID, guildMember.MemberID)
 
-		chance := rand.Float64()
-		if chance <= successRate {
+		perks := GetPerks(h.config.Theme, heistMember.CriminalLevel)
+		chance := h.rng.Float64()
+		if chance <= successRate+perks.SuccessBonus/100.0 {
 			goodResult := h.getGoodResult()
 			bonus, msg := h.getBonusAmount(goodResult)
 
@@ -197,6 +234,7 @@ func (h *Heist) Start() (*HeistResult, error) {
 
 	slog.Info("heist results",
 		slog.String("guildID", h.GuildID),
+		slog.Int64("seed", h.Seed),
 		slog.Int("escaped", len(results.Escaped)),
 		slog.Int("apprehended", len(results.Apprehended)),
 		slog.Int("died", len(results.Dead)),
@@ -219,7 +257,7 @@ func (h *Heist) getGoodResult() *HeistMessage {
 	if len(h.goodMessages) == 0 {
 		h.goodMessages = append(h.goodMessages, h.config.Theme.EscapedMessages...)
 	}
-	index := rand.IntN(len(h.goodMessages))
+	index := h.rng.IntN(len(h.goodMessages))
 	result := h.goodMessages[index]
 
 	h.goodMessages = append(h.goodMessages[:index], h.goodMessages[index+1:]...)
@@ -234,7 +272,7 @@ func (h *Heist) getBadResult() *HeistMessage {
 		h.badMessages = append(h.badMessages, h.config.Theme.ApprehendedMessages...)
 		h.badMessages = append(h.badMessages, h.config.Theme.DiedMessages...)
 	}
-	index := rand.IntN(len(h.badMessages))
+	index := h.rng.IntN(len(h.badMessages))
 	result := h.badMessages[index]
 
 	h.badMessages = append(h.badMessages[:index], h.badMessages[index+1:]...)
@@ -246,12 +284,13 @@ func (h *Heist) getBadResult() *HeistMessage {
 // It returns the bonus amount and the updated message to reflect the new bonus amount. If there is no boost in
 // effect, it simply returns the original bonus amount and message.
 func (h *Heist) getBonusAmount(goodMessage *HeistMessage) (int, string) {
-	if !h.config.BoostEnabled || h.config.BoostPercentage <= 0 {
+	boostPercentage := h.config.CurrentBoostPercentage()
+	if boostPercentage <= 0 {
 		return goodMessage.BonusAmount, goodMessage.Message
 	}
 
 	msg := goodMessage.Message
-	multiplier := 1.0 + (h.config.BoostPercentage / 100.0)
+	multiplier := 1.0 + (boostPercentage / 100.0)
 	bonus := int(float64(goodMessage.BonusAmount) * multiplier)
 
 	// Update the message to reflect the new bonus amount
@@ -278,15 +317,12 @@ func (h *Heist) End() {
 		slog.Debug("heist cancelled", slog.String("guildID", h.GuildID))
 	} else {
 		h.State = Completed
-
-		alertTimesLock.Lock()
-		alertTimes[h.GuildID] = time.Now().Add(h.config.PoliceAlert)
-		alertTimesLock.Unlock()
-
+		setPoliceAlert(h.GuildID, time.Now().Add(h.config.PoliceAlert))
 		slog.Debug("heist ended", slog.String("guildID", h.GuildID))
 	}
 
 	h.removeCurrentHeist()
+	deleteSavedHeist(h.GuildID)
 
 	memberIDs := make([]string, 0, len(h.Crew))
 	for _, member := range h.Crew {
@@ -303,12 +339,41 @@ func (h *Heist) Cancel() {
 
 	h.State = Cancelled
 	h.removeCurrentHeist()
+	deleteSavedHeist(h.GuildID)
 
 	for _, member := range h.Crew {
 		member.heist = nil
 	}
 }
 
+// save saves the state of the heist, so the heist may be cleaned up if the bot is restarted
+// before the heist ends. The caller must hold the heist's mutex.
+func (h *Heist) save() {
+	saved := &SavedHeist{
+		GuildID:     h.GuildID,
+		OrganizerID: h.Organizer.MemberID,
+		CrewIDs:     make([]string, 0, len(h.Crew)),
+		HeistCost:   h.config.HeistCost,
+		StartTime:   h.StartTime,
+		State:       h.State,
+		Seed:        h.Seed,
+	}
+	if h.interaction != nil {
+		saved.ChannelID = h.interaction.ChannelID
+	}
+	for _, member := range h.Crew {
+		saved.CrewIDs = append(saved.CrewIDs, member.MemberID)
+	}
+	writeSavedHeist(saved)
+}
+
+// Save saves the state of the heist.
+func (h *Heist) Save() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.save()
+}
+
 // removeCurrentHeist deletes the current heist from the currentHeists map.
 func (h *Heist) removeCurrentHeist() {
 	heistLock.Lock()
@@ -316,6 +381,60 @@ func (h *Heist) removeCurrentHeist() {
 	heistLock.Unlock()
 }
 
+// restoreHeists cancels any heist that was being planned or was in progress when the bot was
+// stopped. The cost of the heist is returned to each member of the crew, and a message is sent
+// to the channel in which the heist was planned. A completed heist has already paid its crew,
+// so it is not refunded. This is called when the bot starts.
+func restoreHeists() {
+	for _, saved := range readSavedHeists() {
+		if saved.State == Planning || saved.State == InProgress {
+			cancelSavedHeist(saved)
+		}
+		deleteSavedHeist(saved.GuildID)
+	}
+}
+
+// cancelSavedHeist refunds the cost of an interrupted heist to each member of the crew.
+func cancelSavedHeist(saved *SavedHeist) {
+	for _, memberID := range saved.CrewIDs {
+		account := bank.GetAccount(saved.GuildID, memberID)
+		if err := account.Deposit(saved.HeistCost); err != nil {
+			slog.Error("failed to refund heist cost",
+				slog.String("guildID", saved.GuildID),
+				slog.String("memberID", memberID),
+				slog.Int("amount", saved.HeistCost),
+				slog.Any("error", err),
+			)
+		}
+	}
+	slog.Info("cancelled interrupted heist",
+		slog.String("guildID", saved.GuildID),
+		slog.String("state", string(saved.State)),
+		slog.Int("crew", len(saved.CrewIDs)),
+		slog.Int("refund", saved.HeistCost),
+	)
+
+	if saved.ChannelID == "" || bot == nil {
+		return
+	}
+	theme := GetTheme(saved.GuildID, HEIST_THEME)
+	heistName, crewName := "heist", "crew"
+	if theme != nil {
+		heistName, crewName = theme.Heist, theme.Crew
+	}
+	p := message.NewPrinter(language.AmericanEnglish)
+	msg := disgomsg.NewMessage(
+		disgomsg.WithContent(p.Sprintf("The %s was interrupted and has been cancelled. The cost of %d credits has been returned to each member of the %s.", heistName, saved.HeistCost, crewName)),
+	)
+	if _, err := msg.Send(bot.Session, saved.ChannelID); err != nil {
+		slog.Error("failed to send heist cancellation message",
+			slog.String("guildID", saved.GuildID),
+			slog.String("channelID", saved.ChannelID),
+			slog.Any("error", err),
+		)
+	}
+}
+
 // heistChecks returns an error, with the appropriate message if a heist cannot be started.
 func heistChecks(h *Heist, member *HeistMember) error {
 	if h.State != Planning {
@@ -338,10 +457,11 @@ func heistChecks(h *Heist, member *HeistMember) error {
 		return ErrNotEnoughCredits{h.config.HeistCost}
 	}
 
-	alertTimesLock.Lock()
-	alertTime := alertTimes[h.GuildID]
-	alertTimesLock.Unlock()
+	if maxCrew := h.maxCrewSize(); len(h.Crew) >= maxCrew {
+		return ErrCrewFull{h.config.Theme.Crew, maxCrew}
+	}
 
+	alertTime := getPoliceAlert(h.GuildID)
 	if alertTime.After(time.Now()) {
 		remainingTime := time.Until(alertTime)
 		return ErrPoliceOnAlert{h.config.Theme.Police, remainingTime}
@@ -362,6 +482,17 @@ func heistChecks(h *Heist, member *HeistMember) error {
 	return nil
 }
 
+// maxCrewSize returns the largest crew that may join the heist. The crew is only limited when the
+// theme sets a maximum crew size, in which case any extra crew slots the organizer has unlocked are
+// added to it.
+func (h *Heist) maxCrewSize() int {
+	if h.config.Theme == nil || h.config.Theme.MaxCrew <= 0 {
+		return math.MaxInt
+	}
+	perks := GetPerks(h.config.Theme, h.Organizer.CriminalLevel)
+	return h.config.Theme.MaxCrew + perks.ExtraCrew
+}
+
 // calculateSuccessRate returns the likelihood of a successful raid for each
 // member of the heist crew.
 func calculateSuccessRate(heist *Heist, target *Target) float64 {
@@ -403,8 +534,8 @@ func calculateCredits(results *HeistResult) {
 	stolenPerSurvivor := int(math.Round(float64(results.Target.Vault) * 0.75 / float64(numSurvived)))
 
 	config := results.heist.config
-	if config.BoostEnabled && config.BoostPercentage > 0 {
-		multiplier := 1.0 + (config.BoostPercentage / 100.0)
+	if boostPercentage := config.CurrentBoostPercentage(); boostPercentage > 0 {
+		multiplier := 1.0 + (boostPercentage / 100.0)
 		stolenPerSurvivor = int(float64(stolenPerSurvivor) * multiplier)
 	}
 	totalStolen := min(numSurvived*stolenPerSurvivor, results.Target.Vault)
@@ -456,3 +587,9 @@ func (hmr *HeistMemberResult) String() string {
 		hmr.BonusCredits,
 	)
 }
+
+// newHeistRand returns the random number generator for a heist. All random numbers used to decide the
+// outcome of the heist are drawn from it, so the outcome may be reproduced from the seed.
+func newHeistRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
@@ -0,0 +1,16 @@
+package heist
+
+import (
+	"testing"
+)
+
+func TestRemoveCrewMember(t *testing.T) {
+	organizer := &HeistMember{MemberID: "1"}
+	member := &HeistMember{MemberID: "2"}
+	h := &Heist{GuildID: "123", Organizer: organizer, Crew: []*HeistMember{organizer, member}}
+
+	h.RemoveCrewMember(member)
+	if len(h.Crew) != 1 || h.Crew[0] != organizer {
+		t.Errorf("RemoveCrewMember() left crew %v, want only the organizer", h.Crew)
+	}
+}
@@ -89,11 +89,13 @@ func (member *HeistMember) Apprehended() {
 		return
 	}
 
+	perks := GetPerks(member.heist.config.Theme, member.CriminalLevel)
 	bailCost := member.heist.config.BailBase
 	if member.Status == OOB {
 		bailCost *= 3
 	}
-	member.Sentence = time.Duration(int64(member.heist.config.SentenceBase) * int64(member.JailCounter+1))
+	bailCost = perks.ApplyBailDiscount(bailCost)
+	member.Sentence = perks.ApplySentenceReduction(time.Duration(int64(member.heist.config.SentenceBase) * int64(member.JailCounter+1)))
 	member.JailTimer = time.Now().Add(member.Sentence)
 	member.Status = Apprehended
 	member.JailCounter++
@@ -0,0 +1,172 @@
+package heist
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var (
+	criminalLevels = []CriminalLevel{Greenhorn, Renegade, Veteran, Commander, WarChief, Legend, Immortal}
+)
+
+// LevelPerk is a perk that is unlocked once a member reaches a criminal level. Perks are defined
+// by the theme, and the perks for every level a member has reached are combined.
+type LevelPerk struct {
+	Level             CriminalLevel `json:"level" bson:"level"`
+	BailDiscount      float64       `json:"bail_discount,omitempty" bson:"bail_discount,omitempty"`
+	SentenceReduction float64       `json:"sentence_reduction,omitempty" bson:"sentence_reduction,omitempty"`
+	SuccessBonus      float64       `json:"success_bonus,omitempty" bson:"success_bonus,omitempty"`
+	TargetTier        int           `json:"target_tier,omitempty" bson:"target_tier,omitempty"`
+	ExtraCrew         int           `json:"extra_crew,omitempty" bson:"extra_crew,omitempty"`
+}
+
+// Perks are the combined perks that are active for a member.
+type Perks struct {
+	BailDiscount      float64
+	SentenceReduction float64
+	SuccessBonus      float64
+	TargetTier        int
+	ExtraCrew         int
+}
+
+// GetPerks returns the perks unlocked by the given criminal level. Bail discounts and sentence
+// reductions are capped at 100%.
+func GetPerks(theme *Theme, level CriminalLevel) *Perks {
+	perks := &Perks{}
+	if theme == nil {
+		return perks
+	}
+
+	for _, perk := range theme.Perks {
+		if perk == nil || perk.Level > level {
+			continue
+		}
+		perks.BailDiscount += perk.BailDiscount
+		perks.SentenceReduction += perk.SentenceReduction
+		perks.SuccessBonus += perk.SuccessBonus
+		perks.TargetTier = max(perks.TargetTier, perk.TargetTier)
+		perks.ExtraCrew += perk.ExtraCrew
+	}
+	perks.BailDiscount = min(perks.BailDiscount, 100)
+	perks.SentenceReduction = min(perks.SentenceReduction, 100)
+
+	return perks
+}
+
+// ApplyBailDiscount returns the bail cost after the discount has been applied.
+func (perks *Perks) ApplyBailDiscount(bailCost int) int {
+	return int(float64(bailCost) * (100 - perks.BailDiscount) / 100)
+}
+
+// ApplySentenceReduction returns the sentence after the reduction has been applied.
+func (perks *Perks) ApplySentenceReduction(sentence time.Duration) time.Duration {
+	return time.Duration(float64(sentence) * (100 - perks.SentenceReduction) / 100)
+}
+
+// Descriptions returns a description of each of the active perks.
+func (perks *Perks) Descriptions(theme *Theme) []string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	descriptions := make([]string, 0, 5)
+	if perks.BailDiscount > 0 {
+		descriptions = append(descriptions, p.Sprintf("%.0f%% off %s", perks.BailDiscount, theme.Bail))
+	}
+	if perks.SentenceReduction > 0 {
+		descriptions = append(descriptions, p.Sprintf("%.0f%% shorter %s", perks.SentenceReduction, theme.Sentence))
+	}
+	if perks.SuccessBonus > 0 {
+		descriptions = append(descriptions, p.Sprintf("+%.1f%% chance to escape", perks.SuccessBonus))
+	}
+	if perks.TargetTier > 0 {
+		descriptions = append(descriptions, p.Sprintf("tier %d targets when planning a %s", perks.TargetTier, theme.Heist))
+	}
+	if perks.ExtraCrew > 0 && theme.MaxCrew > 0 {
+		descriptions = append(descriptions, p.Sprintf("+%d %s slots when planning a %s", perks.ExtraCrew, theme.Crew, theme.Heist))
+	}
+
+	return descriptions
+}
+
+// NextLevel returns the next criminal level after the given one. If the level is already the
+// highest one, then false is returned.
+func (level CriminalLevel) NextLevel() (CriminalLevel, bool) {
+	for _, next := range criminalLevels {
+		if next > level {
+			return next, true
+		}
+	}
+	return level, false
+}
+
+// nextPerk returns the next perk a member at the given level will unlock, or nil if there are no more perks.
+func nextPerk(theme *Theme, level CriminalLevel) *LevelPerk {
+	var next *LevelPerk
+	for _, perk := range theme.Perks {
+		if perk == nil || perk.Level <= level {
+			continue
+		}
+		if next == nil || perk.Level < next.Level {
+			next = perk
+		}
+	}
+	return next
+}
+
+// validatePerks returns the problems with the perks defined for a theme.
+func validatePerks(perks []*LevelPerk) []string {
+	problems := make([]string, 0)
+	for i, perk := range perks {
+		path := fmt.Sprintf("theme.perks[%d]", i)
+		if perk == nil {
+			problems = append(problems, path+": missing")
+			continue
+		}
+		if perk.Level < 0 {
+			problems = append(problems, fmt.Sprintf("%s.level: must not be negative, got %d", path, perk.Level))
+		}
+		percentages := []struct {
+			name  string
+			value float64
+		}{
+			{"bail_discount", perk.BailDiscount},
+			{"sentence_reduction", perk.SentenceReduction},
+			{"success_bonus", perk.SuccessBonus},
+		}
+		for _, percentage := range percentages {
+			if percentage.value < 0 || percentage.value > 100 {
+				problems = append(problems, fmt.Sprintf("%s.%s: must be between 0 and 100, got %.2f", path, percentage.name, percentage.value))
+			}
+		}
+		if perk.TargetTier < 0 {
+			problems = append(problems, fmt.Sprintf("%s.target_tier: must not be negative, got %d", path, perk.TargetTier))
+		}
+		if perk.ExtraCrew < 0 {
+			problems = append(problems, fmt.Sprintf("%s.extra_crew: must not be negative, got %d", path, perk.ExtraCrew))
+		}
+	}
+
+	return problems
+}
+
+// String returns a string representation of the LevelPerk.
+func (perk *LevelPerk) String() string {
+	return fmt.Sprintf("LevelPerk{Level=%d, BailDiscount=%.2f, SentenceReduction=%.2f, SuccessBonus=%.2f, TargetTier=%d, ExtraCrew=%d}",
+		perk.Level,
+		perk.BailDiscount,
+		perk.SentenceReduction,
+		perk.SuccessBonus,
+		perk.TargetTier,
+		perk.ExtraCrew,
+	)
+}
+
+// joinPerks joins the perk descriptions into a single line, returning "None" if there are no perks.
+func joinPerks(descriptions []string) string {
+	if len(descriptions) == 0 {
+		return "None"
+	}
+	return strings.Join(descriptions, "\n")
+}
@@ -0,0 +1,117 @@
+package heist
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGetPerks(t *testing.T) {
+	theme := &Theme{
+		Perks: []*LevelPerk{
+			{Level: Veteran, BailDiscount: 60},
+			{Level: Commander, SentenceReduction: 25, TargetTier: 1},
+			{Level: Legend, BailDiscount: 60, SuccessBonus: 2, TargetTier: 2, ExtraCrew: 1},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		level CriminalLevel
+		want  Perks
+	}{
+		{name: "no perks", level: Renegade, want: Perks{}},
+		{name: "single perk", level: Veteran, want: Perks{BailDiscount: 60}},
+		{name: "combined perks", level: WarChief, want: Perks{BailDiscount: 60, SentenceReduction: 25, TargetTier: 1}},
+		{name: "capped discount", level: Immortal, want: Perks{BailDiscount: 100, SentenceReduction: 25, SuccessBonus: 2, TargetTier: 2, ExtraCrew: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetPerks(theme, tt.level)
+			if *got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestApplyPerks(t *testing.T) {
+	perks := &Perks{BailDiscount: 25, SentenceReduction: 50}
+	if bail := perks.ApplyBailDiscount(1000); bail != 750 {
+		t.Errorf("expected bail of 750, got %d", bail)
+	}
+	if sentence := perks.ApplySentenceReduction(time.Hour); sentence != 30*time.Minute {
+		t.Errorf("expected sentence of 30m, got %s", sentence)
+	}
+}
+
+func TestGetTargetTier(t *testing.T) {
+	targets := []*Target{
+		{Name: "small", CrewSize: 2},
+		{Name: "medium", CrewSize: 5},
+		{Name: "large", CrewSize: 10, Tier: 1},
+	}
+
+	tests := []struct {
+		name     string
+		crewSize int
+		tier     int
+		want     string
+	}{
+		{name: "fits", crewSize: 4, tier: 0, want: "medium"},
+		{name: "locked tier", crewSize: 8, tier: 0, want: "medium"},
+		{name: "unlocked tier", crewSize: 8, tier: 1, want: "large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := getTarget(targets, tt.crewSize, tt.tier)
+			if target == nil || target.Name != tt.want {
+				t.Errorf("expected target %s, got %v", tt.want, target)
+			}
+		})
+	}
+
+	// When every target is locked, the lowest tier is used.
+	locked := []*Target{{Name: "vault", CrewSize: 3, Tier: 2}}
+	if target := getTarget(locked, 2, 0); target == nil || target.Name != "vault" {
+		t.Errorf("expected target vault, got %v", target)
+	}
+}
+
+func TestNextLevel(t *testing.T) {
+	if next, ok := CriminalLevel(3).NextLevel(); !ok || next != Veteran {
+		t.Errorf("expected %s, got %s", Veteran, next)
+	}
+	if _, ok := CriminalLevel(120).NextLevel(); ok {
+		t.Error("expected no level after Immortal")
+	}
+}
+
+func TestMaxCrewSize(t *testing.T) {
+	perks := []*LevelPerk{{Level: Legend, ExtraCrew: 1}}
+
+	tests := []struct {
+		name    string
+		maxCrew int
+		level   CriminalLevel
+		want    int
+	}{
+		{name: "no limit", maxCrew: 0, level: Immortal, want: math.MaxInt},
+		{name: "limit", maxCrew: 5, level: Greenhorn, want: 5},
+		{name: "extra crew slot", maxCrew: 5, level: Legend, want: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heist := &Heist{
+				config:    &Config{Theme: &Theme{MaxCrew: tt.maxCrew, Perks: perks}},
+				Organizer: &HeistMember{CriminalLevel: tt.level},
+			}
+			if got := heist.maxCrewSize(); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ const (
 
 var (
 	plugin      *Plugin
+	bot         *discord.Bot
 	db          *mongo.MongoDB
 	status      = discord.PluginRunning
 	HEIST_THEME string
@@ -57,12 +58,19 @@ func (plugin *Plugin) Status() discord.PluginStatus {
 
 // Initialize saves the Discord bot to be used by the banking system
 func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
+	bot = b
 	db = d
-	go vaultUpdater()
 	HEIST_THEME = os.Getenv("DISCORD_HEIST_THEME")
 	if HEIST_THEME == "" {
 		HEIST_THEME = DEFAULT_THEME
 	}
+
+	// Messages can't be sent until the bot has connected to Discord.
+	bot.Session.AddHandlerOnce(func(s *discordgo.Session, r *discordgo.Ready) {
+		restorePoliceAlerts()
+		restoreBoostSchedules()
+		restoreHeists()
+	})
 }
 
 // GetCommands returns the commands for the banking system
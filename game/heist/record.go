@@ -0,0 +1,52 @@
+package heist
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// HeistRecord is the saved outcome of a completed heist. The seed used to decide the outcome is
+// saved with it, so the outcome may be reproduced if the results of the heist are disputed.
+type HeistRecord struct {
+	ID          bson.ObjectID        `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID     string               `json:"guild_id" bson:"guild_id"`
+	Seed        int64                `json:"seed" bson:"seed"`
+	StartTime   time.Time            `json:"start_time" bson:"start_time"`
+	Target      string               `json:"target" bson:"target"`
+	TotalStolen int                  `json:"total_stolen" bson:"total_stolen"`
+	Results     []*HeistMemberRecord `json:"results" bson:"results"`
+}
+
+// HeistMemberRecord is the result for a member of the crew in a saved heist.
+type HeistMemberRecord struct {
+	MemberID      string       `json:"member_id" bson:"member_id"`
+	Status        MemberStatus `json:"status" bson:"status"`
+	StolenCredits int          `json:"stolen_credits" bson:"stolen_credits"`
+	BonusCredits  int          `json:"bonus_credits" bson:"bonus_credits"`
+}
+
+// newHeistRecord returns the record of a heist that has completed.
+func newHeistRecord(h *Heist, results *HeistResult) *HeistRecord {
+	record := &HeistRecord{
+		ID:          bson.NewObjectID(),
+		GuildID:     h.GuildID,
+		Seed:        results.Seed,
+		StartTime:   h.StartTime,
+		TotalStolen: results.TotalStolen,
+		Results:     make([]*HeistMemberRecord, 0, len(results.AllResults)),
+	}
+	if results.Target != nil {
+		record.Target = results.Target.Name
+	}
+	for _, result := range results.AllResults {
+		record.Results = append(record.Results, &HeistMemberRecord{
+			MemberID:      result.Player.MemberID,
+			Status:        result.Status,
+			StolenCredits: result.StolenCredits,
+			BonusCredits:  result.BonusCredits,
+		})
+	}
+
+	return record
+}
@@ -0,0 +1,37 @@
+package heist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewHeistRecord(t *testing.T) {
+	start := time.Now()
+	h := &Heist{GuildID: "123", Seed: 42, StartTime: start}
+	results := &HeistResult{
+		AllResults: []*HeistMemberResult{
+			{Player: &HeistMember{MemberID: "1"}, Status: Free, StolenCredits: 500, BonusCredits: 50},
+			{Player: &HeistMember{MemberID: "2"}, Status: Apprehended},
+		},
+		Target:      &Target{Name: "Bank"},
+		TotalStolen: 550,
+		Seed:        42,
+	}
+
+	record := newHeistRecord(h, results)
+	if record.Seed != 42 {
+		t.Errorf("newHeistRecord().Seed = %d, want 42", record.Seed)
+	}
+	if record.Target != "Bank" || record.TotalStolen != 550 || !record.StartTime.Equal(start) {
+		t.Errorf("newHeistRecord() = %+v, want the target, amount stolen and start time of the heist", record)
+	}
+	if len(record.Results) != 2 {
+		t.Fatalf("len(newHeistRecord().Results) = %d, want 2", len(record.Results))
+	}
+	if got := record.Results[0]; got.MemberID != "1" || got.Status != Free || got.StolenCredits != 500 || got.BonusCredits != 50 {
+		t.Errorf("newHeistRecord().Results[0] = %+v, want the escaped member's payout", got)
+	}
+	if got := record.Results[1]; got.MemberID != "2" || got.Status != Apprehended {
+		t.Errorf("newHeistRecord().Results[1] = %+v, want the apprehended member", got)
+	}
+}
@@ -12,17 +12,23 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+const (
+	VaultRecoveryInterval = 1 * time.Minute
+)
+
 // Target is a target of a heist.
 type Target struct {
-	ID       bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID  string        `json:"guild_id" bson:"guild_id"`
-	Theme    string        `json:"theme" bson:"theme"`
-	Name     string        `json:"target_id" bson:"target_id"`
-	CrewSize int           `json:"crew" bson:"crew"`
-	Success  float64       `json:"success" bson:"success"`
-	Vault    int           `json:"vault" bson:"vault"`
-	VaultMax int           `json:"vault_max" bson:"vault_max"`
-	IsAtMax  bool          `json:"is_at_max" bson:"is_at_max"`
+	ID           bson.ObjectID `json:"_id,omitzero" bson:"_id,omitempty"`
+	GuildID      string        `json:"guild_id,omitempty" bson:"guild_id"`
+	Theme        string        `json:"theme,omitempty" bson:"theme"`
+	Name         string        `json:"target_id" bson:"target_id"`
+	CrewSize     int           `json:"crew" bson:"crew"`
+	Success      float64       `json:"success" bson:"success"`
+	Vault        int           `json:"vault" bson:"vault"`
+	VaultMax     int           `json:"vault_max" bson:"vault_max"`
+	IsAtMax      bool          `json:"is_at_max" bson:"is_at_max"`
+	VaultUpdated time.Time     `json:"vault_updated,omitzero" bson:"vault_updated"`
+	Tier         int           `json:"tier,omitempty" bson:"tier"`
 }
 
 // GetTargets returns the list of targets for the server
@@ -51,6 +57,10 @@ func (t *Target) StealFromVault(amount int) {
 
 	originalVaultAmount := t.Vault
 
+	// A full vault doesn't recover, so start recovering from the time of the theft.
+	if t.IsAtMax {
+		t.VaultUpdated = time.Now()
+	}
 	t.Vault -= amount
 	if t.Vault < 0 {
 		t.Vault = 0
@@ -77,8 +87,9 @@ func getAllTargets(filter bson.M) []*Target {
 
 // getTarget returns the target with the smallest maximum crew size that exceeds the number of
 // crew members. If no target matches the criteria, then the target with the maximum crew size
-// is used.
-func getTarget(targets []*Target, crewSize int) *Target {
+// is used. Targets above the given tier are skipped.
+func getTarget(targets []*Target, crewSize int, tier int) *Target {
+	targets = availableTargets(targets, tier)
 	if len(targets) == 0 {
 		slog.Error("no heist targets available",
 			slog.Int("crewSize", crewSize),
@@ -104,6 +115,26 @@ func getTarget(targets []*Target, crewSize int) *Target {
 	return target
 }
 
+// availableTargets returns the targets at or below the given tier. If none of the targets are
+// available, then the targets in the lowest tier are returned.
+func availableTargets(targets []*Target, tier int) []*Target {
+	available := make([]*Target, 0, len(targets))
+	for _, target := range targets {
+		if target.Tier <= tier {
+			available = append(available, target)
+		}
+	}
+	if len(available) > 0 || len(targets) == 0 {
+		return available
+	}
+
+	lowestTier := targets[0].Tier
+	for _, target := range targets {
+		lowestTier = min(lowestTier, target.Tier)
+	}
+	return availableTargets(targets, lowestTier)
+}
+
 // readTargetsFromFile returns the default targets for a server.
 // If the file is not found or cannot be decoded, the default targets are used.
 func readTargetsFromFile(guildID string, theme string) []*Target {
@@ -134,6 +165,7 @@ func readTargetsFromFile(guildID string, theme string) []*Target {
 		target.Theme = theme
 		target.Vault = target.VaultMax
 		target.IsAtMax = true
+		target.VaultUpdated = time.Now()
 	}
 
 	slog.Debug("create new targets",
@@ -152,6 +184,7 @@ func ResetVaultsToMaximumValue(guildID string) {
 	for _, target := range targets {
 		target.Vault = target.VaultMax
 		target.IsAtMax = true
+		target.VaultUpdated = time.Now()
 		writeTarget(target)
 		slog.Info("reset vault to maximum",
 			slog.String("guildID", guildID),
@@ -161,45 +194,78 @@ func ResetVaultsToMaximumValue(guildID string) {
 	}
 }
 
-// vaultUpdater updates the vault balance for any target whose vault is not at the maximum value
-func vaultUpdater() {
-	// Get all vaults not at the max value
-	filter := bson.M{"is_at_max": false}
+// recoveryRate returns the vault recovery rate in force at the given time, along with the time at
+// which the rate next changes. A zero time means the rate doesn't change.
+type recoveryRate func(at time.Time) (rate float64, until time.Time)
 
-	// Update the vaults once a minute forever
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	var config *Config
-	for range ticker.C {
-		for _, target := range getAllTargets(filter) {
-			if config == nil || config.GuildID != target.GuildID {
-				config = GetConfig(target.GuildID)
-			}
-			var recoverPercent float64
-			if config.BoostEnabled {
-				recoverPercent = config.BoostVaultRecovery
-			} else {
-				recoverPercent = config.BaseVaultRecovery
-			}
-			recoverAmount := int(float64(target.VaultMax) * recoverPercent)
-			newVaultAmount := min(target.Vault+recoverAmount, target.VaultMax)
-			slog.Debug("vault updater",
+// recoverVaults brings the vault balance for each target up to date, based on the time since the
+// vault was last updated and the recovery rates in force during that time. The recovered balances
+// are only saved when a target is next written, such as when its vault is robbed or the guild's
+// recovery rates change.
+func recoverVaults(targets []*Target, rateAt recoveryRate) {
+	now := time.Now()
+	for _, target := range targets {
+		oldVaultAmount := target.Vault
+		if target.recoverVault(rateAt, now) {
+			slog.Debug("vault recovered",
 				slog.String("guildID", target.GuildID),
 				slog.String("target", target.Name),
-				slog.Int("old", target.Vault),
-				slog.Int("new", newVaultAmount),
+				slog.Int("old", oldVaultAmount),
+				slog.Int("new", target.Vault),
 				slog.Int("max", target.VaultMax),
 			)
-			target.Vault = newVaultAmount
-			if target.Vault == target.VaultMax {
-				target.IsAtMax = true
-			}
-			writeTarget(target)
 		}
 	}
 }
 
+// recoverVault adds the amount recovered by the vault since it was last updated. The vault
+// recovers a percentage of its maximum value for each full recovery interval that has elapsed,
+// up to the maximum value, using the rate in force when the interval started. Any partial
+// interval is carried forward to the next update. It returns true if the target was changed.
+func (t *Target) recoverVault(rateAt recoveryRate, now time.Time) bool {
+	if t.IsAtMax {
+		return false
+	}
+	if t.Vault >= t.VaultMax {
+		t.Vault = t.VaultMax
+		t.IsAtMax = true
+		t.VaultUpdated = now
+		return true
+	}
+
+	// Targets saved before the time of the last update was tracked start recovering now.
+	if t.VaultUpdated.IsZero() {
+		t.VaultUpdated = now
+		return true
+	}
+
+	changed := false
+	for {
+		intervals := int64(now.Sub(t.VaultUpdated) / VaultRecoveryInterval)
+		if intervals <= 0 {
+			return changed
+		}
+		changed = true
+
+		// Recover the intervals that start before the rate changes, then carry on at the new rate.
+		rate, until := rateAt(t.VaultUpdated)
+		if !until.IsZero() {
+			intervals = min(intervals, int64((until.Sub(t.VaultUpdated)+VaultRecoveryInterval-1)/VaultRecoveryInterval))
+		}
+		recoverAmount := int64(float64(t.VaultMax) * rate)
+		missing := int64(t.VaultMax - t.Vault)
+		if recoverAmount > 0 && intervals >= (missing+recoverAmount-1)/recoverAmount {
+			t.Vault = t.VaultMax
+			t.IsAtMax = true
+			t.VaultUpdated = now
+			return true
+		}
+
+		t.Vault += int(intervals * recoverAmount)
+		t.VaultUpdated = t.VaultUpdated.Add(time.Duration(intervals) * VaultRecoveryInterval)
+	}
+}
+
 // String returns a string representation of the Target.
 func (t *Target) String() string {
 	return fmt.Sprintf("Target{ID=%s, GuildID=%s, TargetID=%s, CrewSize=%d, Success=%.2f, Vault=%d, VaultMax=%d}",
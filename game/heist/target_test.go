@@ -0,0 +1,127 @@
+package heist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecoverVault(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		target      Target
+		rate        float64
+		wantChanged bool
+		wantVault   int
+		wantAtMax   bool
+		wantUpdated time.Time
+	}{
+		{
+			name:        "at max",
+			target:      Target{Vault: 1000, VaultMax: 1000, IsAtMax: true, VaultUpdated: now.Add(-time.Hour)},
+			rate:        0.04,
+			wantChanged: false,
+			wantVault:   1000,
+			wantAtMax:   true,
+			wantUpdated: now.Add(-time.Hour),
+		},
+		{
+			name:        "partial interval",
+			target:      Target{Vault: 500, VaultMax: 1000, VaultUpdated: now.Add(-30 * time.Second)},
+			rate:        0.04,
+			wantChanged: false,
+			wantVault:   500,
+			wantUpdated: now.Add(-30 * time.Second),
+		},
+		{
+			name:        "carries partial interval forward",
+			target:      Target{Vault: 500, VaultMax: 1000, VaultUpdated: now.Add(-150 * time.Second)},
+			rate:        0.04,
+			wantChanged: true,
+			wantVault:   580,
+			wantUpdated: now.Add(-30 * time.Second),
+		},
+		{
+			name:        "recovers to max",
+			target:      Target{Vault: 500, VaultMax: 1000, VaultUpdated: now.Add(-time.Hour)},
+			rate:        0.04,
+			wantChanged: true,
+			wantVault:   1000,
+			wantAtMax:   true,
+			wantUpdated: now,
+		},
+		{
+			name:        "untracked update time",
+			target:      Target{Vault: 500, VaultMax: 1000},
+			rate:        0.04,
+			wantChanged: true,
+			wantVault:   500,
+			wantUpdated: now,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target
+			changed := target.recoverVault(func(time.Time) (float64, time.Time) {
+				return tt.rate, time.Time{}
+			}, now)
+			if changed != tt.wantChanged {
+				t.Errorf("expected changed=%t, got %t", tt.wantChanged, changed)
+			}
+			if target.Vault != tt.wantVault {
+				t.Errorf("expected vault %d, got %d", tt.wantVault, target.Vault)
+			}
+			if target.IsAtMax != tt.wantAtMax {
+				t.Errorf("expected IsAtMax=%t, got %t", tt.wantAtMax, target.IsAtMax)
+			}
+			if !target.VaultUpdated.Equal(tt.wantUpdated) {
+				t.Errorf("expected vault updated at %s, got %s", tt.wantUpdated, target.VaultUpdated)
+			}
+		})
+	}
+}
+
+func TestRecoverVaultWithBoost(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		boost     *BoostSchedule
+		updated   time.Time
+		wantVault int
+	}{
+		{
+			name:      "boost started and ended since the last update",
+			boost:     &BoostSchedule{Name: "boost", Start: now.Add(-40 * time.Minute), Duration: 10 * time.Minute, Repeat: RepeatNone},
+			updated:   now.Add(-60 * time.Minute),
+			wantVault: 20*10 + 10*20 + 30*10,
+		},
+		{
+			name:      "boost started just before the update",
+			boost:     &BoostSchedule{Name: "boost", Start: now.Add(-30 * time.Second), Duration: time.Hour, Repeat: RepeatNone},
+			updated:   now.Add(-5 * time.Minute),
+			wantVault: 5 * 10,
+		},
+		{
+			name:      "boost active for the whole time",
+			boost:     &BoostSchedule{Name: "boost", Start: now.Add(-time.Hour), Duration: 2 * time.Hour, Repeat: RepeatNone},
+			updated:   now.Add(-5 * time.Minute),
+			wantVault: 5 * 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{BaseVaultRecovery: 0.01, BoostVaultRecovery: 0.02, BoostSchedules: []*BoostSchedule{tt.boost}}
+			target := Target{Vault: 0, VaultMax: 1000, VaultUpdated: tt.updated}
+			if !target.recoverVault(config.vaultRecoveryRateAt, now) {
+				t.Fatal("expected the vault to recover")
+			}
+			if target.Vault != tt.wantVault {
+				t.Errorf("expected vault %d, got %d", tt.wantVault, target.Vault)
+			}
+		})
+	}
+}
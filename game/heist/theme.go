@@ -1,20 +1,32 @@
 package heist
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rbrabson/goblin/discord"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+var (
+	themes     = make(map[string]*Theme)
+	themesLock = sync.Mutex{}
+)
+
 // A Theme is a set of messages that provide a "flavor" for a heist
 type Theme struct {
-	ID                  bson.ObjectID   `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID             string          `json:"guild_id" bson:"guild_id"`
+	ID                  bson.ObjectID   `json:"_id,omitzero" bson:"_id,omitempty"`
+	GuildID             string          `json:"guild_id,omitempty" bson:"guild_id"`
 	Name                string          `json:"name" bson:"name"`
 	EscapedMessages     []*HeistMessage `json:"escaped_messages" bson:"escaped_messages"`
 	ApprehendedMessages []*HeistMessage `json:"apprehended_messages" bson:"apprehended_messages"`
@@ -27,6 +39,16 @@ type Theme struct {
 	Sentence            string          `json:"sentence" bson:"sentence"`
 	Heist               string          `json:"heist" bson:"heist"`
 	Vault               string          `json:"vault" bson:"vault"`
+	MaxCrew             int             `json:"max_crew,omitempty" bson:"max_crew,omitempty"`
+	Perks               []*LevelPerk    `json:"perks" bson:"perks"`
+}
+
+// ThemeFile is the document used to export and import a theme, along with the targets
+// used by the theme. The theme and targets use the same format as the files in the
+// `heist/themes` and `heist/targets` configuration directories.
+type ThemeFile struct {
+	Theme   *Theme    `json:"theme"`
+	Targets []*Target `json:"targets"`
 }
 
 // A HeistMessage is a message for a successful heist outcome
@@ -65,10 +87,22 @@ func GetThemes(guildID string) []*Theme {
 	return themes
 }
 
-// GetTheme returns the theme for a guild
+// GetTheme returns the theme for a guild. Themes are cached once they have been read.
 func GetTheme(guildID string, themeName string) *Theme {
+	themesLock.Lock()
+	defer themesLock.Unlock()
+
+	key := themeKey(guildID, themeName)
+	if theme := themes[key]; theme != nil {
+		return theme
+	}
+
 	theme, err := readTheme(guildID, themeName)
 	if err == nil && theme != nil {
+		if theme.Perks == nil {
+			addDefaultPerks(theme)
+		}
+		themes[key] = theme
 		return theme
 	}
 	slog.Warn("unable to read theme",
@@ -84,6 +118,7 @@ func GetTheme(guildID string, themeName string) *Theme {
 	}
 
 	writeTheme(theme)
+	themes[key] = theme
 	slog.Debug("created default theme",
 		slog.String("guildID", guildID),
 		slog.String("theme", theme.Name),
@@ -92,6 +127,33 @@ func GetTheme(guildID string, themeName string) *Theme {
 	return theme
 }
 
+// cacheTheme replaces the cached theme for the guild with one that has just been saved.
+func cacheTheme(theme *Theme) {
+	themesLock.Lock()
+	defer themesLock.Unlock()
+
+	themes[themeKey(theme.GuildID, theme.Name)] = theme
+}
+
+// themeKey returns the key used to cache a guild's theme.
+func themeKey(guildID string, themeName string) string {
+	return guildID + ":" + themeName
+}
+
+// addDefaultPerks gives a theme saved before perks were added the perks in its theme file. The
+// theme is saved with its perks, even if there are none, so this is only done once.
+func addDefaultPerks(theme *Theme) {
+	if defaultTheme := readThemeFromFile(theme.GuildID, theme.Name); defaultTheme != nil {
+		theme.Perks = defaultTheme.Perks
+	}
+	writeTheme(theme)
+	slog.Debug("added default perks to theme",
+		slog.String("guildID", theme.GuildID),
+		slog.String("theme", theme.Name),
+		slog.Int("perks", len(theme.Perks)),
+	)
+}
+
 // readThemeFromFile returns the default theme for a guild. If the theme can't be read
 // from the configuration file or can't be decoded, nil is returned.
 func readThemeFromFile(guildID string, themeName string) *Theme {
@@ -128,6 +190,359 @@ func readThemeFromFile(guildID string, themeName string) *Theme {
 	return theme
 }
 
+// ExportTheme returns the JSON document for the theme and targets currently used by the guild.
+func ExportTheme(guildID string) ([]byte, error) {
+	config := GetConfig(guildID)
+	theme := config.Theme
+	if theme == nil {
+		return nil, ErrThemeNotFound
+	}
+
+	// Strip the fields that are specific to the guild, so the file may be imported into any guild.
+	exported := *theme
+	exported.ID = bson.NilObjectID
+	exported.GuildID = ""
+	themeFile := &ThemeFile{
+		Theme:   &exported,
+		Targets: make([]*Target, 0),
+	}
+	for _, target := range config.Targets {
+		exportedTarget := *target
+		exportedTarget.ID = bson.NilObjectID
+		exportedTarget.GuildID = ""
+		exportedTarget.Theme = ""
+		themeFile.Targets = append(themeFile.Targets, &exportedTarget)
+	}
+
+	return json.MarshalIndent(themeFile, "", "  ")
+}
+
+// ImportTheme validates the JSON document and, if it is valid, replaces the theme and targets
+// currently used by the guild with those in the document. If the document is not valid, the
+// returned ErrInvalidTheme contains each of the problems that were found, along with the line
+// on which each was found. If the new targets or theme can't be saved, the guild's previous
+// targets are restored and the previous theme remains in use.
+func ImportTheme(guildID string, data []byte) (*ThemeFile, error) {
+	themeFile, err := parseThemeFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	theme := themeFile.Theme
+	theme.GuildID = guildID
+	theme.Name = HEIST_THEME
+	theme.ID = bson.NilObjectID
+	if current, _ := readTheme(guildID, theme.Name); current != nil {
+		theme.ID = current.ID
+	}
+	now := time.Now()
+	for _, target := range themeFile.Targets {
+		target.ID = bson.NilObjectID
+		target.GuildID = guildID
+		target.Theme = theme.Name
+		target.IsAtMax = target.Vault == target.VaultMax
+		target.VaultUpdated = now
+	}
+
+	previous, err := readTargets(guildID, theme.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := replaceTargets(guildID, theme.Name, themeFile.Targets); err != nil {
+		restoreTargets(guildID, theme.Name, previous)
+		return nil, err
+	}
+	if err := writeTheme(theme); err != nil {
+		restoreTargets(guildID, theme.Name, previous)
+		return nil, err
+	}
+	cacheTheme(theme)
+
+	slog.Info("imported heist theme",
+		slog.String("guildID", guildID),
+		slog.String("theme", theme.Name),
+		slog.Int("targets", len(themeFile.Targets)),
+	)
+
+	return themeFile, nil
+}
+
+// replaceTargets replaces the targets for the guild and theme with the given targets.
+func replaceTargets(guildID string, theme string, targets []*Target) error {
+	if err := deleteTargets(guildID, theme); err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := writeTarget(target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreTargets puts back the targets the guild had before a failed import.
+func restoreTargets(guildID string, theme string, targets []*Target) {
+	if err := replaceTargets(guildID, theme, targets); err != nil {
+		slog.Error("failed to restore the heist targets after a failed import",
+			slog.String("guildID", guildID),
+			slog.String("theme", theme),
+			slog.Any("error", err),
+		)
+		return
+	}
+	slog.Warn("restored the heist targets after a failed import",
+		slog.String("guildID", guildID),
+		slog.String("theme", theme),
+		slog.Int("targets", len(targets)),
+	)
+}
+
+// parseThemeFile decodes the JSON document and validates the theme and targets it contains.
+func parseThemeFile(data []byte) (*ThemeFile, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var themeFile ThemeFile
+	if err := decoder.Decode(&themeFile); err != nil {
+		return nil, ErrInvalidTheme{Problems: []string{describeJSONError(data, err)}}
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, ErrInvalidTheme{Problems: []string{"unexpected data after the end of the theme"}}
+	}
+
+	// A target without a vault starts with a full vault, as in the `config/heist` target files. A vault
+	// that is given, including an empty one, is imported as is.
+	var vaults struct {
+		Targets []struct {
+			Vault *int `json:"vault"`
+		} `json:"targets"`
+	}
+	if err := json.Unmarshal(data, &vaults); err == nil && len(vaults.Targets) == len(themeFile.Targets) {
+		for i, target := range themeFile.Targets {
+			if target != nil && vaults.Targets[i].Vault == nil {
+				target.Vault = target.VaultMax
+			}
+		}
+	}
+
+	problems := themeFile.validate()
+	if len(problems) > 0 {
+		lines := entryLines(data)
+		for i, problem := range problems {
+			if line, ok := problemLine(problem, lines); ok {
+				problems[i] = fmt.Sprintf("line %d: %s", line, problem)
+			}
+		}
+		return nil, ErrInvalidTheme{Problems: problems}
+	}
+
+	return &themeFile, nil
+}
+
+// validate returns a list of the problems found in the theme file. An empty list is returned
+// if the theme file is valid.
+func (tf *ThemeFile) validate() []string {
+	problems := make([]string, 0)
+
+	theme := tf.Theme
+	if theme == nil {
+		problems = append(problems, "theme: missing")
+	} else {
+		fields := []struct {
+			name  string
+			value string
+		}{
+			{"bail", theme.Bail},
+			{"crew", theme.Crew},
+			{"heist", theme.Heist},
+			{"jail", theme.Jail},
+			{"oob", theme.OOB},
+			{"police", theme.Police},
+			{"sentence", theme.Sentence},
+			{"vault", theme.Vault},
+		}
+		for _, field := range fields {
+			if strings.TrimSpace(field.value) == "" {
+				problems = append(problems, fmt.Sprintf("theme.%s: must not be empty", field.name))
+			}
+		}
+
+		problems = append(problems, validateMessages("escaped_messages", theme.EscapedMessages, Escaped, Free)...)
+		problems = append(problems, validateMessages("apprehended_messages", theme.ApprehendedMessages, Apprehended)...)
+		problems = append(problems, validateMessages("died_messages", theme.DiedMessages, Dead)...)
+		if len(theme.EscapedMessages) == 0 {
+			problems = append(problems, "theme.escaped_messages: at least one message is required")
+		}
+		if len(theme.ApprehendedMessages)+len(theme.DiedMessages) == 0 {
+			problems = append(problems, "theme: at least one apprehended or died message is required")
+		}
+		if theme.MaxCrew < 0 {
+			problems = append(problems, fmt.Sprintf("theme.max_crew: must not be negative, got %d", theme.MaxCrew))
+		}
+		problems = append(problems, validatePerks(theme.Perks)...)
+	}
+
+	if len(tf.Targets) == 0 {
+		problems = append(problems, "targets: at least one target is required")
+	}
+	names := make(map[string]bool, len(tf.Targets))
+	for i, target := range tf.Targets {
+		path := fmt.Sprintf("targets[%d]", i)
+		if target == nil {
+			problems = append(problems, path+": missing")
+			continue
+		}
+		switch {
+		case strings.TrimSpace(target.Name) == "":
+			problems = append(problems, path+".target_id: must not be empty")
+		case names[target.Name]:
+			problems = append(problems, fmt.Sprintf("%s.target_id: duplicate target %q", path, target.Name))
+		}
+		names[target.Name] = true
+		if target.CrewSize <= 0 {
+			problems = append(problems, fmt.Sprintf("%s.crew: must be greater than zero, got %d", path, target.CrewSize))
+		}
+		if target.Success < 0 || target.Success > 100 {
+			problems = append(problems, fmt.Sprintf("%s.success: must be between 0 and 100, got %.2f", path, target.Success))
+		}
+		if target.VaultMax < 0 {
+			problems = append(problems, fmt.Sprintf("%s.vault_max: must not be negative, got %d", path, target.VaultMax))
+		}
+		if target.Vault < 0 {
+			problems = append(problems, fmt.Sprintf("%s.vault: must not be negative, got %d", path, target.Vault))
+		}
+		if target.Tier < 0 {
+			problems = append(problems, fmt.Sprintf("%s.tier: must not be negative, got %d", path, target.Tier))
+		}
+		if target.Vault > target.VaultMax {
+			problems = append(problems, fmt.Sprintf("%s.vault: must not exceed vault_max, got %d", path, target.Vault))
+		}
+	}
+
+	return problems
+}
+
+// validateMessages returns the problems found in a list of heist messages. Each message
+// must have one of the allowed results.
+func validateMessages(name string, messages []*HeistMessage, allowed ...MemberStatus) []string {
+	problems := make([]string, 0)
+	for i, msg := range messages {
+		path := fmt.Sprintf("theme.%s[%d]", name, i)
+		if msg == nil {
+			problems = append(problems, path+": missing")
+			continue
+		}
+		if strings.Count(msg.Message, "%s") != 1 {
+			problems = append(problems, path+".message: must contain exactly one %s for the member name")
+		}
+		if msg.BonusAmount < 0 {
+			problems = append(problems, fmt.Sprintf("%s.bonus_amount: must not be negative, got %d", path, msg.BonusAmount))
+		}
+		if !slices.Contains(allowed, msg.Result) {
+			problems = append(problems, fmt.Sprintf("%s.result: %q is not valid, expected %s", path, msg.Result, joinStatuses(allowed)))
+		}
+	}
+	return problems
+}
+
+// joinStatuses returns a human-readable list of member statuses.
+func joinStatuses(statuses []MemberStatus) string {
+	quoted := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		quoted = append(quoted, fmt.Sprintf("%q", status))
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// describeJSONError converts an error from decoding a JSON document into a message that
+// includes the line and column at which the error was found.
+func describeJSONError(data []byte, err error) string {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err.Error()
+	}
+
+	offset = min(offset, int64(len(data)))
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := offset - int64(bytes.LastIndexByte(data[:offset], '\n'))
+	return fmt.Sprintf("line %d, column %d: %s", line, column, err.Error())
+}
+
+// entryLines returns the line on which each value in the JSON document starts, keyed by the
+// path used in validation problems, such as "theme.escaped_messages[0]" or "targets[2].crew".
+func entryLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		offset := decoder.InputOffset()
+		for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+			offset++
+		}
+		if path != "" {
+			lines[path] = 1 + bytes.Count(data[:offset], []byte("\n"))
+		}
+
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'):
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				name := fmt.Sprint(key)
+				if path != "" {
+					name = path + "." + name
+				}
+				if err := walk(name); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+		case json.Delim('['):
+			for i := 0; decoder.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = decoder.Token()
+		}
+		return err
+	}
+	_ = walk("")
+
+	return lines
+}
+
+// problemLine returns the line for a validation problem. The problem's path is shortened until
+// it names a value in the document, so a missing field is reported on the line of its entry.
+func problemLine(problem string, lines map[string]int) (int, bool) {
+	path, _, _ := strings.Cut(problem, ": ")
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line, true
+		}
+		idx := strings.LastIndexAny(path, ".[")
+		if idx < 0 {
+			break
+		}
+		path = path[:idx]
+	}
+	return 0, false
+}
+
 // String returns a string representation of the Theme.
 func (theme *Theme) String() string {
 	if theme == nil {
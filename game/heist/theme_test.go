@@ -0,0 +1,144 @@
+package heist
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseThemeFile(t *testing.T) {
+	themeData, err := os.ReadFile("../../config/heist/themes/clash.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetData, err := os.ReadFile("../../config/heist/targets/clash.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var themeFile ThemeFile
+	if err := json.Unmarshal(themeData, &themeFile.Theme); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(targetData, &themeFile.Targets); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&themeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := parseThemeFile(data)
+	if err != nil {
+		t.Errorf("expected the default theme to be valid, got %v", err)
+		return
+	}
+	if len(parsed.Targets) != len(themeFile.Targets) {
+		t.Errorf("expected %d targets, got %d", len(themeFile.Targets), len(parsed.Targets))
+	}
+}
+
+func TestParseThemeFileInvalid(t *testing.T) {
+	data := `{
+  "theme": {
+    "escaped_messages": [{"message": "%s got away", "result": "Dead"}],
+    "apprehended_messages": [{"message": "caught", "bonus_amount": -5, "result": "Apprehended"}],
+    "jail": "jail", "oob": "oob", "police": "police", "bail": "bail",
+    "crew": "crew", "sentence": "sentence", "heist": "heist"
+  },
+  "targets": [
+    {"target_id": "Bank", "crew": 2, "success": 20, "vault_max": -1},
+    {"target_id": "Bank", "crew": 0, "success": 120, "vault": -10, "vault_max": 100}
+  ]
+}`
+
+	_, err := parseThemeFile([]byte(data))
+	var invalid ErrInvalidTheme
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected ErrInvalidTheme, got %v", err)
+	}
+
+	expected := []string{
+		"line 2: theme.vault: must not be empty",
+		"line 3: theme.escaped_messages[0].result",
+		"line 4: theme.apprehended_messages[0].message",
+		"line 4: theme.apprehended_messages[0].bonus_amount",
+		"line 9: targets[0].vault_max",
+		"line 10: targets[1].target_id: duplicate",
+		"line 10: targets[1].crew",
+		"line 10: targets[1].success",
+		"line 10: targets[1].vault: must not be negative",
+	}
+	for _, want := range expected {
+		found := false
+		for _, problem := range invalid.Problems {
+			if strings.HasPrefix(problem, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected a problem starting with %q, got %v", want, invalid.Problems)
+		}
+	}
+}
+
+func TestParseThemeFileSyntaxError(t *testing.T) {
+	data := "{\n  \"theme\": {\n    \"jail\": \"jail\",,\n  }\n}"
+
+	_, err := parseThemeFile([]byte(data))
+	var invalid ErrInvalidTheme
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected ErrInvalidTheme, got %v", err)
+	}
+	if len(invalid.Problems) != 1 || !strings.HasPrefix(invalid.Problems[0], "line 3,") {
+		t.Errorf("expected a problem on line 3, got %v", invalid.Problems)
+	}
+}
+
+func TestParseThemeFileVaults(t *testing.T) {
+	data := `{
+  "theme": {
+    "escaped_messages": [{"message": "%s got away", "result": "Free"}],
+    "apprehended_messages": [{"message": "%s was caught", "result": "Apprehended"}],
+    "jail": "jail", "oob": "oob", "police": "police", "bail": "bail",
+    "crew": "crew", "sentence": "sentence", "heist": "heist", "vault": "vault"
+  },
+  "targets": [
+    {"target_id": "Full", "crew": 2, "success": 20, "vault_max": 100},
+    {"target_id": "Empty", "crew": 2, "success": 20, "vault": 0, "vault_max": 100},
+    {"target_id": "Partial", "crew": 2, "success": 20, "vault": 40, "vault_max": 100}
+  ]
+}`
+
+	themeFile, err := parseThemeFile([]byte(data))
+	if err != nil {
+		t.Fatalf("expected the theme to be valid, got %v", err)
+	}
+	want := []int{100, 0, 40}
+	for i, target := range themeFile.Targets {
+		if target.Vault != want[i] {
+			t.Errorf("expected the %s vault to be %d, got %d", target.Name, want[i], target.Vault)
+		}
+	}
+}
+
+func TestThemeWithoutPerks(t *testing.T) {
+	// A theme saved without perks must read back with no perks, rather than as a theme saved before
+	// perks were added.
+	data, err := bson.Marshal(&Theme{Name: "test", Perks: make([]*LevelPerk, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var theme Theme
+	if err := bson.Unmarshal(data, &theme); err != nil {
+		t.Fatal(err)
+	}
+	if theme.Perks == nil {
+		t.Error("expected the empty perks to be saved with the theme")
+	}
+}
@@ -2,10 +2,12 @@ package race
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/rbrabson/goblin/discord"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -18,10 +20,28 @@ type Avatar struct {
 	Theme         string        `json:"theme" bson:"theme"`
 	Emoji         string        `json:"emoji" bson:"emoji"`
 	MovementSpeed string        `json:"movement_speed" bson:"movement_speed"`
+	Training      int           `json:"-" bson:"-"`
 }
 
-// getRaceAvatars returns the list of chracters that may be assigned to a member during a race.
-func getRaceAvatars(guildID string, themeName string) []*Avatar {
+// avatarFile is the file that defines the avatars and movement profiles for a theme. Older avatar files
+// are only a list of avatars, and use the default movement profiles.
+type avatarFile struct {
+	MovementProfiles []*MovementProfile `json:"movement_profiles"`
+	Avatars          []*Avatar          `json:"avatars"`
+}
+
+// getRaceAvatars returns the list of chracters that may be assigned to a member during a race. The
+// characters are shuffled using the given random number generator.
+func getRaceAvatars(guildID string, themeName string, r *rand.Rand) []*Avatar {
+	avatars := getAvatars(guildID, themeName)
+	r.Shuffle(len(avatars), func(i, j int) {
+		avatars[i], avatars[j] = avatars[j], avatars[i]
+	})
+	return avatars
+}
+
+// getAvatars returns the characters for the theme in the guild.
+func getAvatars(guildID string, themeName string) []*Avatar {
 	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "theme", Value: themeName}}
 	avatars, err := readAllRacers(filter)
 	if err != nil {
@@ -34,10 +54,6 @@ func getRaceAvatars(guildID string, themeName string) []*Avatar {
 		return readRaceAvatarsFromFile(guildID, themeName)
 	}
 
-	rand.Shuffle(len(avatars), func(i, j int) {
-		avatars[i], avatars[j] = avatars[j], avatars[i]
-	})
-
 	slog.Debug("read racers",
 		slog.String("guildID", guildID),
 		slog.String("theme", themeName),
@@ -46,31 +62,20 @@ func getRaceAvatars(guildID string, themeName string) []*Avatar {
 	return avatars
 }
 
-// readRaceAvatarsFromFile reads the list of characters for the theme and guild from the database. If the list
-// does not exist, then an error is returned.
+// readRaceAvatarsFromFile reads the list of characters for the theme from the theme's avatar file, and saves
+// them for the guild in the database.
 func readRaceAvatarsFromFile(guildID string, themeName string) []*Avatar {
-	configFileName := filepath.Join(discord.ConfigDir, "race", "avatars", themeName+".json")
-	bytes, err := os.ReadFile(configFileName)
+	file, err := readAvatarFile(themeName)
 	if err != nil {
 		slog.Error("failed to read default race avatars",
 			slog.String("guildID", guildID),
 			slog.String("theme", themeName),
-			slog.String("file", configFileName),
 			slog.Any("error", err),
 		)
+		return nil
 	}
 
-	var avatars []*Avatar
-	err = json.Unmarshal(bytes, &avatars)
-	if err != nil {
-		slog.Error("failed to unmarshal default race avatars",
-			slog.String("guildID", guildID),
-			slog.String("theme", themeName),
-			slog.String("file", configFileName),
-			slog.String("data", string(bytes)),
-			slog.Any("error", err))
-	}
-
+	avatars := file.Avatars
 	for _, avatar := range avatars {
 		avatar.GuildID = guildID
 		avatar.Theme = themeName
@@ -86,48 +91,36 @@ func readRaceAvatarsFromFile(guildID string, themeName string) []*Avatar {
 	return avatars
 }
 
-// calculateMovement calculates the distance a racer moves on a given turn
-func (avatar *Avatar) calculateMovement(currentTurn int) int {
-	config := GetConfig(avatar.GuildID)
-	source := rand.NewPCG(rand.Uint64(), rand.Uint64())
-	r := rand.New(source)
-	switch avatar.MovementSpeed {
-	case "veryfast":
-		return r.IntN(8) * 2
-	case "fast":
-		return r.IntN(5) * 3
-	case "slow":
-		return (r.IntN(3) + 1) * 3
-	case "steady":
-		return 2 * 3
-	case "abberant":
-		chance := r.IntN(100)
-		if chance >= 70 {
-			return 5 * 3
-		}
-		return r.IntN(3) * 3
-	case "predator":
-		if currentTurn%2 != 0 {
-			return 0
-		} else {
-			return (r.IntN(4) + 2) * 3
-		}
-	case "special", "babydragon":
-		fallthrough
-	default:
-		switch currentTurn {
-		case 1:
-			return 7 * 3
-		case 2:
-			return 7 * 3
-		default:
-			movement := (r.IntN(3) * 3)
-			if rand.IntN(100) < config.BabyDragonBuffPercent {
-				movement += 1
-			}
-			return movement
-		}
+// readAvatarFile reads the avatars and movement profiles for the theme from the configuration directory.
+func readAvatarFile(themeName string) (*avatarFile, error) {
+	configFileName := filepath.Join(discord.ConfigDir, "race", "avatars", themeName+".json")
+	bytes, err := os.ReadFile(configFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var file avatarFile
+	if trimmed := strings.TrimSpace(string(bytes)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(bytes, &file.Avatars)
+	} else {
+		err = json.Unmarshal(bytes, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", configFileName, err)
+	}
+	if len(file.MovementProfiles) == 0 {
+		file.MovementProfiles = defaultMovementProfiles
 	}
+
+	return &file, nil
+}
+
+// calculateMovement calculates the distance a racer moves on a given turn, using the movement profile for
+// the racer's movement speed. All random numbers are drawn from the race's random number generator, so
+// the movement may be replayed.
+func (avatar *Avatar) calculateMovement(currentTurn int, r *rand.Rand, profiles MovementProfiles) int {
+	movement := profiles.get(avatar.MovementSpeed).movement(currentTurn, r)
+	return movement + trainingBonus(avatar.Training, movement, r)
 }
 
 // String returns a string representation of the race avatar.
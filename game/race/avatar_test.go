@@ -20,13 +20,13 @@ func init() {
 }
 
 func TestGetRacers(t *testing.T) {
-	racers := getRaceAvatars("123", "clash")
+	racers := getRaceAvatars("123", "clash", newRaceRand(1, avatarStream))
 	if len(racers) == 0 {
 		t.Error("expected racers to be created")
 		return
 	}
 
-	racers = getRaceAvatars("123", "clash")
+	racers = getRaceAvatars("123", "clash", newRaceRand(1, avatarStream))
 	if (len(racers)) == 0 {
 		t.Error("expected racers to be found")
 	}
@@ -40,20 +40,22 @@ func TestGetRacers(t *testing.T) {
 }
 
 func TestCalculateMovement(t *testing.T) {
-	racers := getRaceAvatars("123", "clash")
+	racers := getRaceAvatars("123", "clash", newRaceRand(1, avatarStream))
 	if len(racers) == 0 {
 		t.Error("expected racers to be created")
 		return
 	}
 	racer := racers[0]
+	r := newRaceRand(1, simulationStream)
+	profiles := getMovementProfiles("clash")
 
-	movement := racer.calculateMovement(1)
+	movement := racer.calculateMovement(1, r, profiles)
 	slog.Debug("movement", slog.Int("movement", movement))
 
-	movement = racer.calculateMovement(2)
+	movement = racer.calculateMovement(2, r, profiles)
 	slog.Debug("movement", slog.Int("movement", movement))
 
-	movement = racer.calculateMovement(3)
+	movement = racer.calculateMovement(3, r, profiles)
 	slog.Debug("movement", slog.Int("movement", movement))
 
 	filter := bson.M{"guild_id": "123", "theme": "clash"}
@@ -0,0 +1,253 @@
+package race
+
+import (
+	"github.com/rbrabson/goblin/internal/form"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// BetType is the type of bet placed on a race.
+type BetType string
+
+const (
+	BetWin      BetType = "win"      // Racer finishes first
+	BetPlace    BetType = "place"    // Racer finishes first or second
+	BetShow     BetType = "show"     // Racer finishes first, second or third
+	BetExacta   BetType = "exacta"   // Racers finish first and second, in order
+	BetTrifecta BetType = "trifecta" // Racers finish first, second and third, in order
+)
+
+var (
+	exoticBetTypes = []BetType{BetPlace, BetShow, BetExacta, BetTrifecta}
+)
+
+// BetPool is a pool of stakes used when pari-mutuel betting is enabled. Each type of bet has its
+// own pool. All stakes of that type go into the pool, the house cut is removed, and the remainder
+// is split among the winning tickets in proportion to their stakes.
+type BetPool struct {
+	BetType  BetType                  // Type of bet in the pool
+	Total    int                      // Total of all stakes placed in the pool
+	HouseCut int                      // Amount of the pool kept by the house
+	Net      int                      // Amount of the pool paid out to the winning tickets
+	Stakes   map[*RaceParticipant]int // Total stake placed on each racer, by the first racer picked
+}
+
+// newBetPool returns the betting pool for the bets of the given type.
+func newBetPool(betters []*RaceBetter, betType BetType, houseCutPercent float64) *BetPool {
+	pool := &BetPool{
+		BetType: betType,
+		Stakes:  make(map[*RaceParticipant]int, len(betters)),
+	}
+	for _, better := range betters {
+		if better.BetType != betType {
+			continue
+		}
+		pool.Total += better.Amount
+		pool.Stakes[better.Racer] += better.Amount
+	}
+	pool.HouseCut = int(float64(pool.Total) * houseCutPercent / 100)
+	pool.Net = pool.Total - pool.HouseCut
+
+	return pool
+}
+
+// newBetPools returns the betting pool for each type of bet.
+func newBetPools(betters []*RaceBetter, houseCutPercent float64) []*BetPool {
+	pools := make([]*BetPool, 0, len(exoticBetTypes)+1)
+	for _, betType := range append([]BetType{BetWin}, exoticBetTypes...) {
+		pools = append(pools, newBetPool(betters, betType, houseCutPercent))
+	}
+	return pools
+}
+
+// betPoolTotals returns the total of all stakes placed in the betting pools, along with the total
+// amount kept by the house.
+func betPoolTotals(betters []*RaceBetter, houseCutPercent float64) (int, int) {
+	total, houseCut := 0, 0
+	for _, pool := range newBetPools(betters, houseCutPercent) {
+		total += pool.Total
+		houseCut += pool.HouseCut
+	}
+	return total, houseCut
+}
+
+// Odds returns the current payout per credit staked on the racer. If no bets have been
+// placed on the racer, then false is returned.
+func (pool *BetPool) Odds(racer *RaceParticipant) (float64, bool) {
+	stake := pool.Stakes[racer]
+	if stake == 0 {
+		return 0, false
+	}
+	return float64(pool.Net) / float64(stake), true
+}
+
+// Payout returns the amount paid for a winning ticket with the given stake on the racer.
+func (pool *BetPool) Payout(racer *RaceParticipant, stake int) int {
+	total := pool.Stakes[racer]
+	if total == 0 {
+		return 0
+	}
+	return int(int64(pool.Net) * int64(stake) / int64(total))
+}
+
+// betPool returns the pool for the current bets to win the race.
+func (r *Race) betPool() *BetPool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return newBetPool(r.Betters, BetWin, r.config.HouseCut)
+}
+
+// payPariMutuelBets splits each betting pool among the winning tickets in the pool, in proportion
+// to their stakes. If no ticket in a pool won, then the house keeps the pool.
+func payPariMutuelBets(race *Race) {
+	for _, pool := range newBetPools(race.Betters, race.config.HouseCut) {
+		winningStake := 0
+		for _, better := range race.Betters {
+			if better.BetType == pool.BetType && pool.BetType.Wins(better.Picks, race.RaceResult) {
+				winningStake += better.Amount
+			}
+		}
+		if winningStake == 0 {
+			continue
+		}
+		for _, better := range race.Betters {
+			if better.BetType == pool.BetType && pool.BetType.Wins(better.Picks, race.RaceResult) {
+				better.Winnings = int(int64(pool.Net) * int64(better.Amount) / int64(winningStake))
+			}
+		}
+	}
+}
+
+// payExoticBets pays the place, show, exacta and trifecta bets that won when pari-mutuel betting isn't
+// enabled. Each of these bets pays the stake times the number of possible outcomes for the bet, times
+// the payout multiplier for the bet type.
+func payExoticBets(race *Race) {
+	for _, better := range race.Betters {
+		if better.BetType == BetWin || !better.BetType.Wins(better.Picks, race.RaceResult) {
+			continue
+		}
+		multiplier := race.config.PayoutMultiplier(better.BetType)
+		outcomes := better.BetType.Outcomes(len(race.Racers))
+		better.Winnings = int(float64(better.Amount) * outcomes * multiplier)
+	}
+}
+
+// Picks returns the number of racers that must be picked for the bet type.
+func (b BetType) Picks() int {
+	switch b {
+	case BetExacta:
+		return 2
+	case BetTrifecta:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// MinRacers returns the number of racers needed before the bet type is offered. Place and show
+// bets need more racers than there are paying positions, otherwise they could never lose.
+func (b BetType) MinRacers() int {
+	switch b {
+	case BetPlace:
+		return 3
+	case BetShow:
+		return 4
+	case BetTrifecta:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Outcomes returns the fair odds for the bet type in a race with the given number of racers. This
+// is the number of equally likely finishing orders divided by the number of them that win the bet.
+func (b BetType) Outcomes(numRacers int) float64 {
+	n := float64(numRacers)
+	switch b {
+	case BetPlace:
+		return n / 2
+	case BetShow:
+		return n / 3
+	case BetExacta:
+		return n * (n - 1)
+	case BetTrifecta:
+		return n * (n - 1) * (n - 2)
+	default:
+		return n
+	}
+}
+
+// Wins returns true if the picks for the bet type match the results of the race.
+func (b BetType) Wins(picks []*RaceParticipant, result *RaceResult) bool {
+	if result == nil || len(picks) < b.Picks() {
+		return false
+	}
+	finishers := []*RaceParticipant{nil, nil, nil}
+	for i, r := range []*RaceParticipantResult{result.Win, result.Place, result.Show} {
+		if r != nil {
+			finishers[i] = r.Participant
+		}
+	}
+
+	switch b {
+	case BetWin:
+		return picks[0] == finishers[0]
+	case BetPlace:
+		return picks[0] == finishers[0] || picks[0] == finishers[1]
+	case BetShow:
+		return picks[0] == finishers[0] || picks[0] == finishers[1] || picks[0] == finishers[2]
+	case BetExacta:
+		return picks[0] == finishers[0] && picks[1] == finishers[1]
+	case BetTrifecta:
+		return picks[0] == finishers[0] && picks[1] == finishers[1] && picks[2] == finishers[2]
+	default:
+		return false
+	}
+}
+
+// Description returns a description of the bet type, as shown to members.
+func (b BetType) Description() string {
+	switch b {
+	case BetWin:
+		return "Pick the winner"
+	case BetPlace:
+		return "Pick a racer to finish first or second"
+	case BetShow:
+		return "Pick a racer to finish in the top three"
+	case BetExacta:
+		return "Pick the first and second place racers, in order"
+	case BetTrifecta:
+		return "Pick the first three racers, in order"
+	default:
+		return ""
+	}
+}
+
+// String returns the name of the bet type.
+func (b BetType) String() string {
+	return cases.Title(language.AmericanEnglish).String(string(b))
+}
+
+// parseBetType returns the bet type with the given name.
+func parseBetType(name string) (BetType, bool) {
+	betType := BetType(name)
+	switch betType {
+	case BetWin, BetPlace, BetShow, BetExacta, BetTrifecta:
+		return betType, true
+	default:
+		return "", false
+	}
+}
+
+// parseStake parses the stake entered by a member, ensuring it is within the configured limits.
+func parseStake(value string, config *Config) (int, error) {
+	stake, err := form.ParseAmount(value, "credits")
+	if err != nil {
+		return 0, err
+	}
+	if stake < config.MinBetAmount || stake > config.MaxBetAmount {
+		return 0, ErrInvalidStake{config.MinBetAmount, config.MaxBetAmount}
+	}
+	return stake, nil
+}
@@ -0,0 +1,170 @@
+package race
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBetPool(t *testing.T) {
+	racer1 := &RaceParticipant{}
+	racer2 := &RaceParticipant{}
+	racer3 := &RaceParticipant{}
+	betters := []*RaceBetter{
+		{Racer: racer1, BetType: BetWin, Amount: 100},
+		{Racer: racer1, BetType: BetWin, Amount: 300},
+		{Racer: racer2, BetType: BetWin, Amount: 600},
+		{Racer: racer3, BetType: BetPlace, Picks: []*RaceParticipant{racer3}, Amount: 500},
+	}
+
+	pool := newBetPool(betters, BetWin, 10)
+	if pool.Total != 1000 {
+		t.Errorf("expected a pool of 1000, got %d", pool.Total)
+	}
+	if pool.HouseCut != 100 {
+		t.Errorf("expected a house cut of 100, got %d", pool.HouseCut)
+	}
+	if pool.Net != 900 {
+		t.Errorf("expected a net pool of 900, got %d", pool.Net)
+	}
+
+	odds, ok := pool.Odds(racer1)
+	if !ok || odds != 2.25 {
+		t.Errorf("expected odds of 2.25, got %.2f", odds)
+	}
+	if _, ok := pool.Odds(racer3); ok {
+		t.Error("expected no odds for a racer without bets")
+	}
+
+	if payout := pool.Payout(racer1, 100); payout != 225 {
+		t.Errorf("expected a payout of 225, got %d", payout)
+	}
+	if payout := pool.Payout(racer1, 300); payout != 675 {
+		t.Errorf("expected a payout of 675, got %d", payout)
+	}
+	if payout := pool.Payout(racer3, 100); payout != 0 {
+		t.Errorf("expected no payout, got %d", payout)
+	}
+}
+
+func TestPayPariMutuelBets(t *testing.T) {
+	first := &RaceParticipant{}
+	second := &RaceParticipant{}
+	third := &RaceParticipant{}
+	fourth := &RaceParticipant{}
+	race := &Race{
+		Racers: []*RaceParticipant{first, second, third, fourth},
+		Betters: []*RaceBetter{
+			{Racer: first, BetType: BetWin, Picks: []*RaceParticipant{first}, Amount: 200},
+			{Racer: second, BetType: BetWin, Picks: []*RaceParticipant{second}, Amount: 200},
+			{Racer: first, BetType: BetPlace, Picks: []*RaceParticipant{first}, Amount: 100},
+			{Racer: second, BetType: BetPlace, Picks: []*RaceParticipant{second}, Amount: 300},
+			{Racer: third, BetType: BetPlace, Picks: []*RaceParticipant{third}, Amount: 400},
+			{Racer: second, BetType: BetExacta, Picks: []*RaceParticipant{second, first}, Amount: 100},
+		},
+		RaceResult: &RaceResult{
+			Win:   &RaceParticipantResult{Participant: first},
+			Place: &RaceParticipantResult{Participant: second},
+			Show:  &RaceParticipantResult{Participant: third},
+		},
+		config: &Config{PariMutuel: true, HouseCut: 10, PayoutMultipliers: map[BetType]float64{BetPlace: 5}},
+	}
+
+	payPariMutuelBets(race)
+	// The win pool of 400 and the place pool of 800 are each paid to the winning tickets, less the house
+	// cut. No one won the exacta, so the house keeps that pool.
+	expected := []int{360, 0, 180, 540, 0, 0}
+	for i, want := range expected {
+		if got := race.Betters[i].Winnings; got != want {
+			t.Errorf("expected %s bet %d to win %d, got %d", race.Betters[i].BetType, i, want, got)
+		}
+	}
+
+	total, houseCut := betPoolTotals(race.Betters, race.config.HouseCut)
+	if total != 1300 || houseCut != 130 {
+		t.Errorf("expected pools totalling 1300 with a house cut of 130, got %d and %d", total, houseCut)
+	}
+}
+
+func TestBetTypeWins(t *testing.T) {
+	first := &RaceParticipant{}
+	second := &RaceParticipant{}
+	third := &RaceParticipant{}
+	fourth := &RaceParticipant{}
+	result := &RaceResult{
+		Win:   &RaceParticipantResult{Participant: first},
+		Place: &RaceParticipantResult{Participant: second},
+		Show:  &RaceParticipantResult{Participant: third},
+	}
+
+	tests := []struct {
+		name    string
+		betType BetType
+		picks   []*RaceParticipant
+		want    bool
+	}{
+		{name: "win", betType: BetWin, picks: []*RaceParticipant{first}, want: true},
+		{name: "win loses", betType: BetWin, picks: []*RaceParticipant{second}, want: false},
+		{name: "place", betType: BetPlace, picks: []*RaceParticipant{second}, want: true},
+		{name: "place loses", betType: BetPlace, picks: []*RaceParticipant{third}, want: false},
+		{name: "show", betType: BetShow, picks: []*RaceParticipant{third}, want: true},
+		{name: "show loses", betType: BetShow, picks: []*RaceParticipant{fourth}, want: false},
+		{name: "exacta", betType: BetExacta, picks: []*RaceParticipant{first, second}, want: true},
+		{name: "exacta out of order", betType: BetExacta, picks: []*RaceParticipant{second, first}, want: false},
+		{name: "trifecta", betType: BetTrifecta, picks: []*RaceParticipant{first, second, third}, want: true},
+		{name: "trifecta out of order", betType: BetTrifecta, picks: []*RaceParticipant{first, third, second}, want: false},
+		{name: "missing picks", betType: BetTrifecta, picks: []*RaceParticipant{first, second}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.betType.Wins(tt.picks, result); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPayExoticBets(t *testing.T) {
+	first := &RaceParticipant{}
+	second := &RaceParticipant{}
+	third := &RaceParticipant{}
+	fourth := &RaceParticipant{}
+	race := &Race{
+		Racers: []*RaceParticipant{first, second, third, fourth},
+		Betters: []*RaceBetter{
+			{Racer: second, BetType: BetPlace, Picks: []*RaceParticipant{second}, Amount: 100},
+			{Racer: first, BetType: BetExacta, Picks: []*RaceParticipant{first, second}, Amount: 100},
+			{Racer: first, BetType: BetTrifecta, Picks: []*RaceParticipant{first, third, second}, Amount: 100},
+			{Racer: first, BetType: BetWin, Picks: []*RaceParticipant{first}, Amount: 100},
+		},
+		RaceResult: &RaceResult{
+			Win:   &RaceParticipantResult{Participant: first},
+			Place: &RaceParticipantResult{Participant: second},
+			Show:  &RaceParticipantResult{Participant: third},
+		},
+		config: &Config{PayoutMultipliers: map[BetType]float64{BetExacta: 0.5}},
+	}
+
+	payExoticBets(race)
+	expected := []int{200, 600, 0, 0}
+	for i, want := range expected {
+		if got := race.Betters[i].Winnings; got != want {
+			t.Errorf("expected %s bet to win %d, got %d", race.Betters[i].BetType, want, got)
+		}
+	}
+}
+
+func TestParseStake(t *testing.T) {
+	config := &Config{MinBetAmount: 100, MaxBetAmount: 1000}
+
+	if stake, err := parseStake(" 1,000 ", config); err != nil || stake != 1000 {
+		t.Errorf("expected a stake of 1000, got %d (%v)", stake, err)
+	}
+	var invalid ErrInvalidStake
+	if _, err := parseStake("50", config); !errors.As(err, &invalid) {
+		t.Errorf("expected ErrInvalidStake, got %v", err)
+	}
+	if _, err := parseStake("lots", config); err == nil {
+		t.Error("expected an error for a stake that is not a number")
+	}
+}
@@ -1,9 +1,14 @@
 package race
 
 import (
+	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,18 +17,58 @@ import (
 	"github.com/rbrabson/disgomsg"
 	"github.com/rbrabson/goblin/discord"
 	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/form"
 	"github.com/rbrabson/goblin/internal/format"
+	"github.com/rbrabson/goblin/internal/history"
 	"github.com/rbrabson/goblin/internal/unicode"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
 
+const (
+	stakeModalPrefix   = "race_stake:"
+	stakeInputID       = "stake"
+	betTypeMenuID      = "race_bet_type"
+	betPickMenuPrefix  = "race_bet_pick:"
+	betConfirmButtonID = "race_bet_confirm"
+	exoticStakeModalID = "race_exotic_stake"
+	maxFieldLength     = 1024
+)
+
 var (
+	minBetValue = 1.0
+	minHouseCut = 0.0
+	maxHouseCut = 100.0
+
+	minPayoutMultiplier = 0.0
+
+	minBuffPercent = 0.0
+	maxBuffPercent = 100.0
+	minBuffHours   = 1.0
+	maxBuffHours   = 168.0
+
+	defaultSimulatedRaces = 1000
+	minSimulatedRaces     = 100.0
+	maxSimulatedRaces     = 10000.0
+
+	minTournamentHeats      = 1.0
+	maxTournamentHeats      = 50.0
+	minTournamentInterval   = 1.0
+	minTournamentQualifiers = 2.0
+	minTournamentPrize      = 0.0
+
 	betButtons     = make(map[string]map[string]*raceButton) // guild -> label -> button
 	betButtonMutex = sync.Mutex{}
 
 	componentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"join_race": joinRace,
+		"join_race":             joinRace,
+		betTypeMenuID:           selectBetType,
+		betPickMenuPrefix + "0": selectBetPick,
+		betPickMenuPrefix + "1": selectBetPick,
+		betPickMenuPrefix + "2": selectBetPick,
+		betConfirmButtonID:      confirmExoticBet,
+		exoticStakeModalID:      stakeOnExoticBet,
 	}
 
 	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
@@ -46,6 +91,99 @@ var (
 					Description: "Returns the race stats for the player.",
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 				},
+				{
+					Name:        "history",
+					Description: "Shows your most recent races and bets.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						history.CountOption("races"),
+					},
+				},
+				{
+					Name:        "stable",
+					Description: "Buys, trains and chooses the racers you own.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "list",
+							Description: "Shows the racers you own and their records.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "shop",
+							Description: "Shows the racers you can buy.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "buy",
+							Description: "Buys a racer and adds it to your stable.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "racer",
+									Description: "The racer to buy, as shown in the shop.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name to give your racer.",
+									Required:    true,
+									MaxLength:   maxRacerNameLength,
+								},
+							},
+						},
+						{
+							Name:        "train",
+							Description: "Trains one of your racers so it runs faster.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of your racer.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "use",
+							Description: "Chooses the racer you race with.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of your racer.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "tournament",
+					Description: "Enters or follows the race tournament.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "join",
+							Description: "Enters you into the race tournament.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "leave",
+							Description: "Removes you from the race tournament before it starts.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "standings",
+							Description: "Shows the schedule and standings for the race tournament.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -55,6 +193,304 @@ var (
 			Name:        "race-admin",
 			Description: "Race game admin commands.",
 			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "config",
+					Description: "Configures the race game.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "info",
+							Description: "Returns the race configuration for the server.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "betting",
+							Description: "Configures how bets on races are placed and paid.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "mode",
+									Description: "Whether bets are a fixed amount or go into a pari-mutuel pool.",
+									Required:    false,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Fixed", Value: "fixed"},
+										{Name: "Pari-mutuel", Value: "pari-mutuel"},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min",
+									Description: "The smallest stake allowed for a pari-mutuel bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max",
+									Description: "The largest stake allowed for a pari-mutuel bet.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        "house-cut",
+									Description: "The percentage of the pari-mutuel pool kept by the house.",
+									Required:    false,
+									MinValue:    &minHouseCut,
+									MaxValue:    maxHouseCut,
+								},
+							},
+						},
+						{
+							Name:        "display",
+							Description: "Sets how races are shown as they are run.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "mode",
+									Description: "Whether to update a text track each leg or post an animated GIF of the race.",
+									Required:    true,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Text", Value: RenderText},
+										{Name: "Animated GIF", Value: RenderGIF},
+									},
+								},
+							},
+						},
+						{
+							Name:        "payouts",
+							Description: "Sets the multipliers applied to the fair odds for place, show, exacta and trifecta bets.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        string(BetPlace),
+									Description: "The payout multiplier for place bets.",
+									Required:    false,
+									MinValue:    &minPayoutMultiplier,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        string(BetShow),
+									Description: "The payout multiplier for show bets.",
+									Required:    false,
+									MinValue:    &minPayoutMultiplier,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        string(BetExacta),
+									Description: "The payout multiplier for exacta bets.",
+									Required:    false,
+									MinValue:    &minPayoutMultiplier,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        string(BetTrifecta),
+									Description: "The payout multiplier for trifecta bets.",
+									Required:    false,
+									MinValue:    &minPayoutMultiplier,
+								},
+							},
+						},
+						{
+							Name:        "stables",
+							Description: "Configures whether members race with racers they own, and what racers cost.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "assignment",
+									Description: "Whether members race with their own racer or a random one.",
+									Required:    false,
+									Choices: []*discordgo.ApplicationCommandOptionChoice{
+										{Name: "Owned", Value: RacerAssignmentOwned},
+										{Name: "Random", Value: RacerAssignmentRandom},
+									},
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "price",
+									Description: "The cost of buying a racer.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "training-cost",
+									Description: "The cost of the first level of training. Each level costs this much more than the last.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max-training",
+									Description: "The highest level to which a racer may be trained.",
+									Required:    false,
+									MinValue:    &minBetValue,
+									MaxValue:    maxTrainingLevel,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "stable-size",
+									Description: "The most racers a member may own.",
+									Required:    false,
+									MinValue:    &minBetValue,
+								},
+							},
+						},
+						{
+							Name:        "buff",
+							Description: "Temporarily sets the chance that a baby dragon moves an extra space each turn.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "percent",
+									Description: "The chance, as a percentage, that a baby dragon moves an extra space.",
+									Required:    true,
+									MinValue:    &minBuffPercent,
+									MaxValue:    maxBuffPercent,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "hours",
+									Description: "The number of hours the buff lasts.",
+									Required:    true,
+									MinValue:    &minBuffHours,
+									MaxValue:    maxBuffHours,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "history",
+					Description: "Shows a race, the races for a member, or the most recent races.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "id",
+							Description: "The ID of the race, shown with the race results.",
+							Required:    false,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionUser,
+							Name:        "user",
+							Description: "The member or member ID.",
+							Required:    false,
+						},
+						history.CountOption("races"),
+					},
+				},
+				{
+					Name:        "profiles",
+					Description: "Validates the movement profiles for the race theme and simulates races to report their win rates.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionInteger,
+							Name:        "races",
+							Description: "The number of races to simulate.",
+							Required:    false,
+							MinValue:    &minSimulatedRaces,
+							MaxValue:    maxSimulatedRaces,
+						},
+					},
+				},
+				{
+					Name:        "replay",
+					Description: "Replays a past race to verify its results.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "id",
+							Description: "The ID of the race, shown with the race results.",
+							Required:    true,
+						},
+					},
+				},
+				{
+					Name:        "tournament",
+					Description: "Schedules or cancels a race tournament.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "create",
+							Description: "Schedules a race tournament with a series of heats and a final.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of the tournament, such as \"Weekend Cup\".",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "start",
+									Description: "The time the first heat runs, in the format YYYY-MM-DD HH:MM.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "heats",
+									Description: "The number of heats run before the final.",
+									Required:    true,
+									MinValue:    &minTournamentHeats,
+									MaxValue:    maxTournamentHeats,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "interval",
+									Description: "The time between heats, in minutes.",
+									Required:    true,
+									MinValue:    &minTournamentInterval,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "qualifiers",
+									Description: "The number of racers with the most points who qualify for the final.",
+									Required:    true,
+									MinValue:    &minTournamentQualifiers,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "prize",
+									Description: "The amount paid to the champion.",
+									Required:    true,
+									MinValue:    &minTournamentPrize,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionChannel,
+									Name:        "channel",
+									Description: "The channel where results are posted. Defaults to this channel.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "points",
+									Description: "Points for each finishing position in a heat, such as 10,7,5,3,2,1.",
+									Required:    false,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "timezone",
+									Description: "The time zone for the start time, such as America/New_York. Defaults to UTC.",
+									Required:    false,
+								},
+							},
+						},
+						{
+							Name:        "cancel",
+							Description: "Cancels the race tournament.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+					},
+				},
 				{
 					Name:        "reset",
 					Description: "Resets a hung race.",
@@ -83,8 +519,18 @@ func raceAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	options := i.ApplicationCommandData().Options
 	switch options[0].Name {
+	case "config":
+		raceConfig(s, i)
+	case "history":
+		adminRaceHistory(s, i)
+	case "profiles":
+		validateProfiles(s, i)
+	case "replay":
+		replayRace(s, i)
 	case "reset":
 		resetRace(s, i)
+	case "tournament":
+		adminRaceTournament(s, i)
 	default:
 		slog.Error("unknown command", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("command", options[0].Name))
 		disgomsg.NewResponse(disgomsg.WithContent("Command is unknown")).SendEphemeral(s, i.Interaction)
@@ -103,12 +549,384 @@ func race(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		startRace(s, i)
 	case "stats":
 		raceStats(s, i)
+	case "history":
+		raceHistory(s, i)
+	case "stable":
+		raceStable(s, i)
+	case "tournament":
+		raceTournament(s, i)
 	default:
 		disgomsg.NewResponse(disgomsg.WithContent("Command is unknown")).SendEphemeral(s, i.Interaction)
 		slog.Error("unknown command", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("command", options[0].Name))
 	}
 }
 
+// raceConfig routes the configuration commands to the proper handlers.
+func raceConfig(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "betting":
+		configBetting(s, i)
+	case "buff":
+		configBuff(s, i)
+	case "display":
+		configDisplay(s, i)
+	case "info":
+		configInfo(s, i)
+	case "payouts":
+		configPayouts(s, i)
+	case "stables":
+		configStables(s, i)
+	default:
+		slog.Error("unknown command", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("command", options[0].Name))
+		disgomsg.NewResponse(disgomsg.WithContent("Command is unknown")).SendEphemeral(s, i.Interaction)
+	}
+}
+
+// configBetting sets the betting mode, the limits on the stake, and the house cut for pari-mutuel betting.
+func configBetting(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	minBet, maxBet := config.MinBetAmount, config.MaxBetAmount
+	houseCut := config.HouseCut
+	pariMutuel := config.PariMutuel
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "mode":
+			pariMutuel = option.StringValue() == "pari-mutuel"
+		case "min":
+			minBet = int(option.IntValue())
+		case "max":
+			maxBet = int(option.IntValue())
+		case "house-cut":
+			houseCut = option.FloatValue()
+		}
+	}
+
+	if minBet <= 0 || maxBet < minBet {
+		disgomsg.NewResponse(disgomsg.WithContent("The minimum bet must be greater than zero and no larger than the maximum bet.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if houseCut < 0 || houseCut >= 100 {
+		disgomsg.NewResponse(disgomsg.WithContent("The house cut must be at least 0% and less than 100%.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	config.PariMutuel = pariMutuel
+	config.MinBetAmount = minBet
+	config.MaxBetAmount = maxBet
+	config.HouseCut = houseCut
+	writeConfig(config)
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	var content string
+	if pariMutuel {
+		content = p.Sprintf("Pari-mutuel betting is enabled, with bets of %d to %d credits and a house cut of %.2f%%", minBet, maxBet, houseCut)
+	} else {
+		content = p.Sprintf("Fixed betting is enabled, with bets of %d credits", config.BetAmount)
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(content)).Send(s, i.Interaction)
+}
+
+// configPayouts sets the payout multipliers for place, show, exacta and trifecta bets.
+func configPayouts(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	if config.PayoutMultipliers == nil {
+		config.PayoutMultipliers = make(map[BetType]float64)
+	}
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		if betType, ok := parseBetType(option.Name); ok {
+			config.PayoutMultipliers[betType] = option.FloatValue()
+		}
+	}
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent("Payout multipliers set to "+formatPayoutMultipliers(config))).Send(s, i.Interaction)
+}
+
+// configBuff sets the chance that a baby dragon moves an extra space each turn for the given number of
+// hours. Races that start after the buff expires use the theme's baby dragon profile again.
+func configBuff(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	var hours int64
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "percent":
+			config.BabyDragonBuffPercent = int(option.IntValue())
+		case "hours":
+			hours = option.IntValue()
+		}
+	}
+	config.BabyDragonBuffExpires = time.Now().Add(time.Duration(hours) * time.Hour)
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent("Baby dragon buff set to "+formatBabyDragonBuff(config))).Send(s, i.Interaction)
+}
+
+// formatBabyDragonBuff returns a description of the baby dragon buff and when it expires.
+func formatBabyDragonBuff(config *Config) string {
+	buffPercent, ok := config.babyDragonBuff(time.Now())
+	if !ok {
+		return "none"
+	}
+	return fmt.Sprintf("%d%% until <t:%d:f>", buffPercent, config.BabyDragonBuffExpires.Unix())
+}
+
+// configDisplay sets whether races are shown as a text track that is updated each leg, or as an animated GIF.
+func configDisplay(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	config.RenderMode = i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent("Races will be shown as "+formatRenderMode(config))).Send(s, i.Interaction)
+}
+
+// formatRenderMode returns a description of how races are shown.
+func formatRenderMode(config *Config) string {
+	if config.RenderMode == RenderGIF {
+		return "an animated GIF"
+	}
+	return "a text track"
+}
+
+// formatPayoutMultipliers returns the payout multipliers for place, show, exacta and trifecta bets.
+// The multipliers aren't used when pari-mutuel betting is enabled.
+func formatPayoutMultipliers(config *Config) string {
+	if config.PariMutuel {
+		return "each type of bet is paid from its own pool"
+	}
+	p := message.NewPrinter(language.AmericanEnglish)
+	multipliers := make([]string, 0, len(exoticBetTypes))
+	for _, betType := range exoticBetTypes {
+		multipliers = append(multipliers, p.Sprintf("%s %.2fx", betType, config.PayoutMultiplier(betType)))
+	}
+	return strings.Join(multipliers, ", ")
+}
+
+// configInfo returns the race configuration for the server.
+func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	p := message.NewPrinter(language.AmericanEnglish)
+
+	mode := "fixed"
+	if config.PariMutuel {
+		mode = "pari-mutuel"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "betting",
+				Value:  mode,
+				Inline: true,
+			},
+			{
+				Name:   "bet",
+				Value:  p.Sprintf("%d", config.BetAmount),
+				Inline: true,
+			},
+			{
+				Name:   "min bet",
+				Value:  p.Sprintf("%d", config.MinBetAmount),
+				Inline: true,
+			},
+			{
+				Name:   "max bet",
+				Value:  p.Sprintf("%d", config.MaxBetAmount),
+				Inline: true,
+			},
+			{
+				Name:   "house cut",
+				Value:  p.Sprintf("%.2f%%", config.HouseCut),
+				Inline: true,
+			},
+			{
+				Name:   "payouts",
+				Value:  formatPayoutMultipliers(config),
+				Inline: false,
+			},
+			{
+				Name:   "display",
+				Value:  formatRenderMode(config),
+				Inline: true,
+			},
+			{
+				Name:   "baby dragon buff",
+				Value:  formatBabyDragonBuff(config),
+				Inline: true,
+			},
+			{
+				Name:   "stables",
+				Value:  formatStableConfig(config),
+				Inline: false,
+			},
+			{
+				Name:   "racers",
+				Value:  p.Sprintf("%d to %d", config.MinNumRacers, config.MaxNumRacers),
+				Inline: true,
+			},
+			{
+				Name:   "prize",
+				Value:  p.Sprintf("%d to %d", config.MinPrizeAmount, config.MaxPrizeAmount),
+				Inline: true,
+			},
+			{
+				Name:   "wait",
+				Value:  p.Sprintf("%.f", config.WaitBetweenRaces.Seconds()),
+				Inline: true,
+			},
+		},
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "Race Configuration",
+			Embeds:  []*discordgo.MessageEmbed{embed},
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// validateProfiles validates the movement profiles in the theme's avatar file and simulates races using them,
+// reporting the win rate for each profile. The file is read again, so changes may be checked before the bot
+// is restarted to use them.
+func validateProfiles(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	config := GetConfig(i.GuildID)
+
+	numRaces := defaultSimulatedRaces
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		if option.Name == "races" {
+			numRaces = int(option.IntValue())
+		}
+	}
+
+	file, err := readAvatarFile(config.Theme)
+	if err != nil {
+		slog.Error("failed to read the avatar file", slog.String("guildID", i.GuildID), slog.String("theme", config.Theme), slog.Any("error", err))
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Unable to read the avatars for the %s theme", config.Theme))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	avatars, err := readAllRacers(bson.D{{Key: "guild_id", Value: i.GuildID}, {Key: "theme", Value: config.Theme}})
+	if err != nil {
+		avatars = file.Avatars
+	}
+
+	description := "No problems were found."
+	if problems := validateMovementProfiles(file.MovementProfiles, avatars); len(problems) > 0 {
+		description = unicode.Truncate(strings.Join(problems, "\n"), 4000)
+	}
+
+	trackLength := len([]rune(config.Track))
+	stats := simulateProfiles(file.MovementProfiles, numRaces, config.MaxNumRacers, trackLength, rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())))
+	slices.SortFunc(stats, func(a, b *ProfileStats) int {
+		return cmp.Compare(b.WinRate(), a.WinRate())
+	})
+	var sb strings.Builder
+	for _, profileStats := range stats {
+		sb.WriteString(p.Sprintf("%s: %.1f%% (%d of %d)\n", profileStats.Name, profileStats.WinRate(), profileStats.Wins, profileStats.Entries))
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("No valid profiles")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       p.Sprintf("Movement Profiles for %s", config.Theme),
+		Description: description,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   p.Sprintf("Win rates over %d races of %d racers", numRaces, config.MaxNumRacers),
+				Value:  sb.String(),
+				Inline: false,
+			},
+		},
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// replayRace runs a past race again using its saved seed, and verifies the results match those of the race.
+func replayRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	options := i.ApplicationCommandData().Options[0].Options
+	id := strings.TrimSpace(options[0].StringValue())
+
+	raceID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("%q is not a valid race ID", id))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	record := readRaceRecord(i.GuildID, raceID)
+	if record == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Race %s was not found", id))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	replayed := record.replay()
+	verified := record.verify(replayed)
+	if verified != nil {
+		slog.Warn("race replay does not match the race results",
+			slog.String("guildID", i.GuildID),
+			slog.String("raceID", id),
+			slog.Int64("seed", record.Seed),
+			slog.Any("error", verified),
+		)
+	}
+
+	var sb strings.Builder
+	for n, finisher := range replayed.Finishers {
+		racer := record.getRacerRecord(finisher.MemberID)
+		if racer == nil {
+			continue
+		}
+		sb.WriteString(p.Sprintf("%d. %s %s %.2fs", n+1, racer.Emoji, racer.Name, finisher.RaceTime))
+		if finisher.Winnings > 0 {
+			sb.WriteString(p.Sprintf(" (prize: %d)", finisher.Winnings))
+		}
+		sb.WriteString("\n")
+	}
+
+	result := "The replay matches the results of the race."
+	if verified != nil {
+		result = p.Sprintf("The replay does not match the results of the race: %s", verified)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       p.Sprintf("Race %s", id),
+		Description: result,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Seed",
+				Value:  p.Sprintf("%d", record.Seed),
+				Inline: true,
+			},
+			{
+				Name:   "Started",
+				Value:  p.Sprintf("<t:%d:f>", record.StartTime.Unix()),
+				Inline: true,
+			},
+			{
+				Name:   "Replay",
+				Value:  sb.String(),
+				Inline: false,
+			},
+		},
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
 // resetRace resets a hung race.
 func resetRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	ResetRace(i.GuildID)
@@ -159,7 +977,11 @@ func startRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	raceMessage(s, race, "started")
 	slog.Info("race starting", slog.String("guildID", i.GuildID), slog.Int("racers", len(race.Racers)), slog.Int("betsPlaced", len(race.Betters)))
 	race.runRace(len([]rune(race.config.Track)))
-	sendRaceLegs(s, race)
+	if race.config.RenderMode == RenderGIF {
+		sendRaceAnimation(s, race)
+	} else {
+		sendRaceLegs(s, race)
+	}
 
 	race.setState(RaceFinished)
 	raceMessage(s, race, "ended")
@@ -320,7 +1142,8 @@ func raceStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 }
 
-// betOnRace processes a bet placed by a member on the race.
+// betOnRace processes a bet placed by a member on the race. When pari-mutuel betting is enabled, the
+// member is asked for the amount to stake on the racer before the bet is placed.
 func betOnRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	race := GetCurrentRace(i.GuildID)
 	if race == nil {
@@ -330,44 +1153,362 @@ func betOnRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	// Check to see if the member can place a bet
-	err := raceBetChecks(race, i.Member.User.ID)
+	err := raceBetChecks(race, i.Member.User.ID, BetWin)
 	if err != nil {
 		slog.Error("unable to place bet", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
 		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
 		return
 	}
 
-	participant := race.getRaceParticipant(i.Member.User.ID)
-	var betMember *RaceMember
-	if participant != nil && participant.Member != nil {
-		betMember = participant.Member
-	} else {
-		guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
-		betMember = getRaceMember(i.GuildID, guildMember)
+	customID := i.Interaction.MessageComponentData().CustomID
+	raceParticipant := getCurrentRaceParticipant(race, customID)
+	if raceParticipant == nil {
+		slog.Error("race participant not found", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("customID", customID))
+		disgomsg.NewResponse(disgomsg.WithContent("Race participant not found")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	if !race.config.PariMutuel {
+		placeRaceBet(s, i, race, BetWin, []*RaceParticipant{raceParticipant}, race.config.BetAmount)
+		return
 	}
 
-	raceParticipant := getCurrentRaceParticipant(race, i.Interaction.MessageComponentData().CustomID)
+	p := message.NewPrinter(language.AmericanEnglish)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: stakeModalPrefix + customID,
+			Title:    unicode.Truncate(p.Sprintf("Bet on %s", raceParticipant.Member.guildMember.Name), 45),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:    stakeInputID,
+						Label:       p.Sprintf("Stake (%d to %d credits)", race.config.MinBetAmount, race.config.MaxBetAmount),
+						Style:       discordgo.TextInputShort,
+						Placeholder: p.Sprintf("%d", race.config.MinBetAmount),
+						Required:    true,
+						MaxLength:   12,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the stake form", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+	}
+}
+
+// stakeOnRace processes the stake entered by a member for a pari-mutuel bet on the race.
+func stakeOnRace(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	race := GetCurrentRace(i.GuildID)
+	if race == nil {
+		slog.Warn("no race is planned", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID))
+		disgomsg.NewResponse(disgomsg.WithContent("No race is planned")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	data := i.ModalSubmitData()
+	raceParticipant := getCurrentRaceParticipant(race, strings.TrimPrefix(data.CustomID, stakeModalPrefix))
 	if raceParticipant == nil {
-		slog.Error("race participant not found", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("customID", i.Interaction.MessageComponentData().CustomID))
+		slog.Error("race participant not found", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("customID", data.CustomID))
 		disgomsg.NewResponse(disgomsg.WithContent("Race participant not found")).SendEphemeral(s, i.Interaction)
 		return
 	}
-	better := getRaceBetter(betMember, raceParticipant)
-	if err = placeBet(race, better); err != nil {
+
+	stake, err := parseStake(form.TextInputValue(data.Components, stakeInputID), race.config)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	placeRaceBet(s, i, race, BetWin, []*RaceParticipant{raceParticipant}, stake)
+}
+
+// selectBetType starts a place, show, exacta or trifecta bet. The member is sent a form used to pick
+// the racers for the bet.
+func selectBetType(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	race := GetCurrentRace(i.GuildID)
+	if race == nil {
+		slog.Warn("no race is planned", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID))
+		disgomsg.NewResponse(disgomsg.WithContent("No race is planned")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	values := i.MessageComponentData().Values
+	if len(values) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent("Choose a type of bet")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	betType, ok := parseBetType(values[0])
+	if !ok {
+		slog.Error("unknown bet type", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("betType", values[0]))
+		disgomsg.NewResponse(disgomsg.WithContent("Unknown type of bet")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	if err := raceBetChecks(race, i.Member.User.ID, betType); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	race.startPendingBet(i.Member.User.ID, betType)
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	odds := betType.Outcomes(len(race.Racers)) * race.config.PayoutMultiplier(betType)
+	content := p.Sprintf("**%s**: %s. A winning bet pays %.2f times the stake.", betType, betType.Description(), odds)
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: createBetPickMenus(race, betType),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the bet form", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+	}
+}
+
+// selectBetPick records the racer picked by the member for one of the positions in their bet.
+func selectBetPick(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	race := GetCurrentRace(i.GuildID)
+	if race == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No race is planned")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	data := i.MessageComponentData()
+	index, err := strconv.Atoi(strings.TrimPrefix(data.CustomID, betPickMenuPrefix))
+	if err != nil || len(data.Values) == 0 {
+		slog.Error("invalid bet pick", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("customID", data.CustomID))
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to pick the racer")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	racer := race.getRaceParticipant(data.Values[0])
+	if racer == nil || !race.setPendingBetPick(i.Member.User.ID, index, racer) {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrBetNotStarted.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+}
+
+// confirmExoticBet places the place, show, exacta or trifecta bet once the member has picked the
+// racers. When pari-mutuel betting is enabled, the member is first asked for the amount to stake.
+func confirmExoticBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	race := GetCurrentRace(i.GuildID)
+	if race == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No race is planned")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	bet, err := getCompletedPendingBet(race, i.Member.User.ID)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	if !race.config.PariMutuel {
+		placeRaceBet(s, i, race, bet.BetType, bet.Picks, race.config.BetAmount)
+		return
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: exoticStakeModalID,
+			Title:    p.Sprintf("%s bet", bet.BetType),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.TextInput{
+						CustomID:    stakeInputID,
+						Label:       p.Sprintf("Stake (%d to %d credits)", race.config.MinBetAmount, race.config.MaxBetAmount),
+						Style:       discordgo.TextInputShort,
+						Placeholder: p.Sprintf("%d", race.config.MinBetAmount),
+						Required:    true,
+						MaxLength:   12,
+					},
+				}},
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the stake form", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+	}
+}
+
+// stakeOnExoticBet processes the stake entered by a member for a pari-mutuel place, show, exacta or trifecta bet.
+func stakeOnExoticBet(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	race := GetCurrentRace(i.GuildID)
+	if race == nil {
+		disgomsg.NewResponse(disgomsg.WithContent("No race is planned")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	bet, err := getCompletedPendingBet(race, i.Member.User.ID)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	stake, err := parseStake(form.TextInputValue(i.ModalSubmitData().Components, stakeInputID), race.config)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	placeRaceBet(s, i, race, bet.BetType, bet.Picks, stake)
+}
+
+// getCompletedPendingBet returns the bet the member is picking racers for, ensuring that a different
+// racer has been picked for each position.
+func getCompletedPendingBet(race *Race, memberID string) (*pendingBet, error) {
+	bet := race.getPendingBet(memberID)
+	if bet == nil {
+		return nil, ErrBetNotStarted
+	}
+	for i, pick := range bet.Picks {
+		if pick == nil {
+			return nil, ErrIncompletePicks
+		}
+		if slices.Contains(bet.Picks[:i], pick) {
+			return nil, ErrDuplicatePicks
+		}
+	}
+	return bet, nil
+}
+
+// createBetTypeMenu returns the menu used to choose a place, show, exacta or trifecta bet. Only the
+// types of bets that are available for the number of racers are included. If no types of bets are
+// available, then nil is returned.
+func createBetTypeMenu(race *Race) *discordgo.ActionsRow {
+	options := make([]discordgo.SelectMenuOption, 0, len(exoticBetTypes))
+	for _, betType := range exoticBetTypes {
+		if len(race.Racers) < betType.MinRacers() {
+			continue
+		}
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       betType.String(),
+			Value:       string(betType),
+			Description: betType.Description(),
+		})
+	}
+	if len(options) == 0 {
+		return nil
+	}
+
+	return &discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.SelectMenu{
+			CustomID:    betTypeMenuID,
+			Placeholder: "Place, show, exacta or trifecta bets",
+			Options:     options,
+		},
+	}}
+}
+
+// createBetPickMenus returns the menus used to pick the racers for a bet, followed by the button
+// used to place the bet.
+func createBetPickMenus(race *Race, betType BetType) []discordgo.MessageComponent {
+	race.mutex.Lock()
+	options := make([]discordgo.SelectMenuOption, 0, len(race.Racers))
+	for _, racer := range race.Racers {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: unicode.Truncate(racer.Member.guildMember.Name, 100),
+			Value: racer.Member.MemberID,
+		})
+	}
+	race.mutex.Unlock()
+
+	placeholders := []string{"First place", "Second place", "Third place"}
+	if betType.Picks() == 1 {
+		placeholders = []string{"Racer"}
+	}
+
+	components := make([]discordgo.MessageComponent, 0, betType.Picks()+1)
+	for index := range betType.Picks() {
+		components = append(components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    betPickMenuPrefix + strconv.Itoa(index),
+				Placeholder: placeholders[index],
+				Options:     options,
+			},
+		}})
+	}
+	components = append(components, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Place Bet",
+			Style:    discordgo.SuccessButton,
+			CustomID: betConfirmButtonID,
+		},
+	}})
+
+	return components
+}
+
+// placeRaceBet places a bet of the given amount and type by the member on the racers they picked.
+func placeRaceBet(s *discordgo.Session, i *discordgo.InteractionCreate, race *Race, betType BetType, picks []*RaceParticipant, amount int) {
+	// The race may have started while the member was choosing their stake
+	if err := raceBetChecks(race, i.Member.User.ID, betType); err != nil {
+		slog.Error("unable to place bet", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	participant := race.getRaceParticipant(i.Member.User.ID)
+	betMember := race.getBetMember(i.Member.User.ID)
+	switch {
+	case betMember != nil:
+	case participant != nil && participant.Member != nil:
+		betMember = participant.Member
+	default:
+		guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
+		betMember = getRaceMember(i.GuildID, guildMember)
+	}
+
+	better := getRaceBetter(betMember, betType, picks, amount)
+	if err := placeBet(race, better); err != nil {
 		slog.Error("unable to place bet", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.Any("error", err))
 		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Unable to place a bet, Error: %s", err.Error()))).SendEphemeral(s, i.Interaction)
 		return
 	}
+	race.removePendingBet(i.Member.User.ID)
 
+	names := getPickNames(picks)
 	p := message.NewPrinter(language.AmericanEnglish)
-	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You have placed a %d credit bet on %s", race.config.BetAmount, raceParticipant.Member.guildMember.Name))).SendEphemeral(s, i.Interaction)
+	if betType == BetWin {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You have placed a %d credit bet on %s", amount, names))).SendEphemeral(s, i.Interaction)
+	} else {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You have placed a %d credit %s bet on %s", amount, strings.ToLower(betType.String()), names))).SendEphemeral(s, i.Interaction)
+	}
+
+	slog.Info("race bet placed", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("betType", string(betType)), slog.String("racers", names), slog.Int("amount", amount))
+
+	// Update the odds shown on the bet buttons
+	if race.config.PariMutuel {
+		raceMessage(s, race, "betting")
+	}
+}
 
-	slog.Info("race bet placed", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("racer", raceParticipant.Member.guildMember.Name))
+// getPickNames returns the names of the racers picked for a bet, in finishing order.
+func getPickNames(picks []*RaceParticipant) string {
+	names := make([]string, 0, len(picks))
+	for _, pick := range picks {
+		names = append(names, pick.Member.guildMember.Name)
+	}
+	return strings.Join(names, ", ")
 }
 
 // createBetButtons returns the buttons for the racers, which may be used to
 // bet on the various racers.
 func createBetButtons(race *Race) []discordgo.ActionsRow {
+	var pool *BetPool
+	if race.config.PariMutuel {
+		pool = race.betPool()
+	}
+
 	buttonsPerRow := 5
 	rows := make([]discordgo.ActionsRow, 0, len(race.Racers)/buttonsPerRow)
 
@@ -380,7 +1521,7 @@ func createBetButtons(race *Race) []discordgo.ActionsRow {
 			index := i + racersIncludedInButtons
 			racer := race.Racers[index]
 			button := discordgo.Button{
-				Label:    race.Racers[index].Member.guildMember.Name,
+				Label:    betButtonLabel(racer, pool),
 				Style:    discordgo.PrimaryButton,
 				CustomID: createBetButton(racer).label,
 				Emoji:    nil,
@@ -396,6 +1537,20 @@ func createBetButtons(race *Race) []discordgo.ActionsRow {
 	return rows
 }
 
+// betButtonLabel returns the label for the bet button for a racer. When pari-mutuel betting is enabled,
+// the current odds for the racer are included in the label.
+func betButtonLabel(racer *RaceParticipant, pool *BetPool) string {
+	name := unicode.Truncate(racer.Member.guildMember.Name, 60)
+	if pool == nil {
+		return name
+	}
+	odds, ok := pool.Odds(racer)
+	if !ok {
+		return name + " (no bets)"
+	}
+	return fmt.Sprintf("%s (%.2fx)", name, odds)
+}
+
 // createBetButton creates and returns a new race button for the racer, as well as
 // registers the handlers for the button with Discord.
 func createBetButton(rp *RaceParticipant) *raceButton {
@@ -422,8 +1577,9 @@ func createBetButton(rp *RaceParticipant) *raceButton {
 	}
 	buttons[button.label] = button
 
-	// Register the component handler for the button
+	// Register the component handlers for the button and the form used to enter the stake
 	bot.AddComponentHandler(button.label, betOnRace)
+	bot.AddComponentHandler(stakeModalPrefix+button.label, stakeOnRace)
 
 	return button
 }
@@ -437,6 +1593,7 @@ func removeBetButtons(race *Race) {
 	buttons := betButtons[race.GuildID]
 	for key := range buttons {
 		bot.RemoveComponentHandler(key)
+		bot.RemoveComponentHandler(stakeModalPrefix + key)
 	}
 	betButtons[race.GuildID] = make(map[string]*raceButton)
 }
@@ -462,7 +1619,11 @@ func raceMessage(s *discordgo.Session, race *Race, action string) error {
 		msg = p.Sprintf(":triangular_flag_on_post: A race is starting! Click the button to join the race! :triangular_flag_on_post:\n\t\t\t\t\tThe race will begin in %s!", format.Duration(until))
 	case "betting":
 		until := time.Until(race.RaceStartTime.Add(race.config.WaitToStart + race.config.WaitForBets))
-		msg = p.Sprintf(":triangular_flag_on_post: The racers have been set - betting is now open! :triangular_flag_on_post:\n\t\tYou have %s to place a %d credit bet!", format.Duration(until), race.config.BetAmount)
+		if race.config.PariMutuel {
+			msg = p.Sprintf(":triangular_flag_on_post: The racers have been set - betting is now open! :triangular_flag_on_post:\n\t\tYou have %s to place a bet of %d to %d credits!", format.Duration(until), race.config.MinBetAmount, race.config.MaxBetAmount)
+		} else {
+			msg = p.Sprintf(":triangular_flag_on_post: The racers have been set - betting is now open! :triangular_flag_on_post:\n\t\tYou have %s to place a %d credit bet!", format.Duration(until), race.config.BetAmount)
+		}
 	case "started":
 		msg = ":checkered_flag: The race is now in progress! :checkered_flag:"
 	case "ended":
@@ -538,6 +1699,9 @@ func raceMessage(s *discordgo.Session, race *Race, action string) error {
 		for _, row := range rows {
 			components = append(components, row)
 		}
+		if menu := createBetTypeMenu(race); menu != nil && len(components) < 5 {
+			components = append(components, menu)
+		}
 		_, err = s.InteractionResponseEdit(race.interaction.Interaction, &discordgo.WebhookEdit{
 			Embeds:     &embeds,
 			Components: &components,
@@ -573,12 +1737,43 @@ func sendRaceLegs(s *discordgo.Session, race *Race) {
 	}
 }
 
+// sendRaceAnimation sends the race as an animated GIF, then waits for it to play so the results aren't
+// posted before members have watched the race. If the GIF can't be rendered, the race is sent as text.
+func sendRaceAnimation(s *discordgo.Session, race *Race) {
+	channelID := race.interaction.ChannelID
+	animation, duration, err := renderRaceGIF(race)
+	if err != nil {
+		slog.Error("failed to render the race", slog.String("guildID", race.GuildID), slog.Any("error", err))
+		sendRaceLegs(s, race)
+		return
+	}
+
+	_, err = s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Files: []*discordgo.File{
+			{
+				Name:        "race.gif",
+				ContentType: "image/gif",
+				Reader:      bytes.NewReader(animation),
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the race animation", slog.String("guildID", race.GuildID), slog.String("channelID", channelID), slog.Any("error", err))
+		return
+	}
+
+	time.Sleep(duration)
+}
+
 // getCurrentTrack returns the current position of all racers on the track
 func getCurrentTrack(raceLeg *RaceLeg, config *Config) string {
 	var track strings.Builder
 	for _, pos := range raceLeg.ParticipantPositions {
 		name := pos.RaceParticipant.Member.guildMember.Name
 		racer := pos.RaceParticipant.Racer
+		if pos.RaceParticipant.Stable != nil {
+			name = fmt.Sprintf("%s (%s)", name, pos.RaceParticipant.Stable.Name)
+		}
 
 		position := max(0, pos.Position)
 
@@ -633,6 +1828,12 @@ func sendRaceResults(s *discordgo.Session, channelID string, race *Race) {
 	for _, bet := range race.Betters {
 		if bet.Winnings > 0 {
 			memberName := bet.Member.guildMember.Name
+			switch {
+			case bet.BetType != BetWin:
+				memberName = p.Sprintf("%s: %d (%s)", memberName, bet.Winnings, bet.BetType)
+			case race.config.PariMutuel:
+				memberName = p.Sprintf("%s: %d", memberName, bet.Winnings)
+			}
 			betWinners = append(betWinners, memberName)
 		}
 	}
@@ -642,11 +1843,21 @@ func sendRaceResults(s *discordgo.Session, channelID string, race *Race) {
 	} else {
 		winners = "No one guessed the winner."
 	}
-	betEarnings := race.config.BetAmount * len(racers)
-	betResults := &discordgo.MessageEmbedField{
-		Name:   p.Sprintf("Bet earnings of %d", betEarnings),
-		Value:  winners,
-		Inline: false,
+	var betResults *discordgo.MessageEmbedField
+	if race.config.PariMutuel {
+		total, houseCut := betPoolTotals(race.Betters, race.config.HouseCut)
+		betResults = &discordgo.MessageEmbedField{
+			Name:   p.Sprintf("Betting pool of %d (house cut %d)", total, houseCut),
+			Value:  winners,
+			Inline: false,
+		}
+	} else {
+		betEarnings := race.config.BetAmount * len(racers)
+		betResults = &discordgo.MessageEmbedField{
+			Name:   p.Sprintf("Bet earnings of %d", betEarnings),
+			Value:  winners,
+			Inline: false,
+		}
 	}
 	raceResults = append(raceResults, betResults)
 	embeds := []*discordgo.MessageEmbed{
@@ -655,6 +1866,11 @@ func sendRaceResults(s *discordgo.Session, channelID string, race *Race) {
 			Fields: raceResults,
 		},
 	}
+	if !race.ID.IsZero() {
+		embeds[0].Footer = &discordgo.MessageEmbedFooter{
+			Text: "Race ID: " + race.ID.Hex(),
+		}
+	}
 	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
 		Embeds: embeds,
 	})
@@ -2,6 +2,7 @@ package race
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -13,26 +14,40 @@ import (
 
 const (
 	defaultBabyDragonBuffPercent = 50
+	defaultMaxBetMultiplier      = 10
+	defaultPayoutMultiplier      = 1.0
 )
 
 // Config represents the configuration for the race game.
 type Config struct {
-	ID                    bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	GuildID               string        `json:"guild_id" bson:"guild_id"`
-	BetAmount             int           `json:"bet_amount" bson:"bet_amount"`
-	Currency              string        `json:"currency" bson:"currency"`
-	MaxPrizeAmount        int           `json:"max_prize_amount" bson:"max_prize_amount"`
-	MaxNumRacers          int           `json:"max_num_racers" bson:"max_num_racers"`
-	MinNumRacers          int           `json:"min_num_racers" bson:"min_num_racers"`
-	MinPrizeAmount        int           `json:"min_price_amount" bson:"min_price_amount"`
-	Theme                 string        `json:"theme" bson:"theme"`
-	WaitBetweenRaces      time.Duration `json:"wait_beween_races" bson:"wait_between_races"`
-	WaitForBets           time.Duration `json:"wait_for_bets" bson:"wait_for_bets"`
-	WaitToStart           time.Duration `json:"wait_to_start" bson:"wait_to_start"`
-	StartingLine          string        `json:"starting_line" bson:"starting_line"`
-	Track                 string        `json:"track" bson:"track"`
-	EndingLine            string        `json:"ending_line" bson:"ending_line"`
-	BabyDragonBuffPercent int           `json:"babydragon_buff_percent" bson:"babydragon_buff_percent"`
+	ID                    bson.ObjectID       `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID               string              `json:"guild_id" bson:"guild_id"`
+	BetAmount             int                 `json:"bet_amount" bson:"bet_amount"`
+	Currency              string              `json:"currency" bson:"currency"`
+	MaxPrizeAmount        int                 `json:"max_prize_amount" bson:"max_prize_amount"`
+	MaxNumRacers          int                 `json:"max_num_racers" bson:"max_num_racers"`
+	MinNumRacers          int                 `json:"min_num_racers" bson:"min_num_racers"`
+	MinPrizeAmount        int                 `json:"min_price_amount" bson:"min_price_amount"`
+	Theme                 string              `json:"theme" bson:"theme"`
+	WaitBetweenRaces      time.Duration       `json:"wait_beween_races" bson:"wait_between_races"`
+	WaitForBets           time.Duration       `json:"wait_for_bets" bson:"wait_for_bets"`
+	WaitToStart           time.Duration       `json:"wait_to_start" bson:"wait_to_start"`
+	StartingLine          string              `json:"starting_line" bson:"starting_line"`
+	Track                 string              `json:"track" bson:"track"`
+	EndingLine            string              `json:"ending_line" bson:"ending_line"`
+	BabyDragonBuffPercent int                 `json:"babydragon_buff_percent,omitempty" bson:"babydragon_buff_percent,omitempty"`
+	BabyDragonBuffExpires time.Time           `json:"babydragon_buff_expires,omitempty" bson:"babydragon_buff_expires,omitempty"`
+	PariMutuel            bool                `json:"pari_mutuel" bson:"pari_mutuel"`
+	MinBetAmount          int                 `json:"min_bet_amount" bson:"min_bet_amount"`
+	MaxBetAmount          int                 `json:"max_bet_amount" bson:"max_bet_amount"`
+	HouseCut              float64             `json:"house_cut" bson:"house_cut"`
+	PayoutMultipliers     map[BetType]float64 `json:"payout_multipliers" bson:"payout_multipliers"`
+	RenderMode            string              `json:"render_mode,omitempty" bson:"render_mode,omitempty"`
+	RacerAssignment       string              `json:"racer_assignment,omitempty" bson:"racer_assignment,omitempty"`
+	RacerPrice            int                 `json:"racer_price,omitempty" bson:"racer_price,omitempty"`
+	TrainingCost          int                 `json:"training_cost,omitempty" bson:"training_cost,omitempty"`
+	MaxTraining           int                 `json:"max_training,omitempty" bson:"max_training,omitempty"`
+	MaxStableSize         int                 `json:"max_stable_size,omitempty" bson:"max_stable_size,omitempty"`
 }
 
 // GetConfig gets the race configuration for the guild. If the configuration does not
@@ -42,33 +57,51 @@ func GetConfig(guildID string) *Config {
 	if config == nil {
 		config = readConfigFromFile(guildID)
 	}
-	if config.BabyDragonBuffPercent == 0 {
-		config.BabyDragonBuffPercent = defaultBabyDragonBuffPercent
+	if config.MinBetAmount == 0 && config.MaxBetAmount == 0 {
+		config.MinBetAmount = config.BetAmount
+		config.MaxBetAmount = config.BetAmount * defaultMaxBetMultiplier
 		writeConfig(config)
-		slog.Debug("set baby dragon buff percent", slog.String("guildID", guildID), slog.Int("babydragon_buff_percent", config.BabyDragonBuffPercent))
+		slog.Debug("set bet limits", slog.String("guildID", guildID), slog.Int("min_bet_amount", config.MinBetAmount), slog.Int("max_bet_amount", config.MaxBetAmount))
 	}
 	return config
 }
 
+// babyDragonBuff returns the bonus chance of the baby dragon profile while a temporary buff is active.
+// Once the buff expires, the baby dragon moves as the theme's profile describes.
+func (config *Config) babyDragonBuff(now time.Time) (int, bool) {
+	if config.BabyDragonBuffExpires.IsZero() || !now.Before(config.BabyDragonBuffExpires) {
+		return 0, false
+	}
+	return config.BabyDragonBuffPercent, true
+}
+
+// movementProfiles returns the movement profiles for a race started at the given time, with the baby
+// dragon buff applied if one is active. The race keeps its own copy, so a buff that expires or changes
+// during the race doesn't affect it.
+func (config *Config) movementProfiles(profiles MovementProfiles, now time.Time) MovementProfiles {
+	if buffPercent, ok := config.babyDragonBuff(now); ok {
+		return profiles.withBuff(buffPercent)
+	}
+	return profiles
+}
+
+// PayoutMultiplier returns the multiplier applied to the fair odds of the bet type when paying a
+// winning place, show, exacta or trifecta bet.
+func (config *Config) PayoutMultiplier(betType BetType) float64 {
+	if multiplier, ok := config.PayoutMultipliers[betType]; ok {
+		return multiplier
+	}
+	return defaultPayoutMultiplier
+}
+
 // readConfigFromFile gets a new configuration for the guild. If the oconfiguration cannot be
 // read from the configuration file or decdoded, then a default configuration is
 // returned.
 func readConfigFromFile(guildID string) *Config {
-	configFileName := filepath.Join(discord.ConfigDir, "race", "config", raceTheme+".json")
-	bytes, err := os.ReadFile(configFileName)
+	config, err := ReadConfigFile(raceTheme)
 	if err != nil {
 		slog.Error("failed to read race config", slog.String("guildID", guildID), slog.String("theme", raceTheme), slog.Any("error", err))
-	}
-
-	config := &Config{}
-	err = json.Unmarshal(bytes, config)
-	if err != nil {
-		slog.Error("failed to unmarshal race config",
-			slog.String("guildID", guildID),
-			slog.String("theme", raceTheme),
-			slog.String("file", configFileName),
-			slog.Any("error", err),
-		)
+		config = &Config{}
 	}
 	config.GuildID = guildID
 
@@ -77,3 +110,19 @@ func readConfigFromFile(guildID string) *Config {
 
 	return config
 }
+
+// ReadConfigFile reads the default race configuration for the theme from the configuration directory.
+func ReadConfigFile(themeName string) (*Config, error) {
+	configFileName := filepath.Join(discord.ConfigDir, "race", "config", themeName+".json")
+	bytes, err := os.ReadFile(configFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(bytes, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", configFileName, err)
+	}
+
+	return config, nil
+}
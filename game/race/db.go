@@ -10,6 +10,11 @@ const (
 	RaceConfigCollection = "race_configs"
 	RaceMemberCollection = "race_members"
 	RacerCollection      = "race_avatars"
+	RaceRecordCollection = "race_records"
+	TournamentCollection = "race_tournaments"
+	StableCollection     = "race_stables"
+	SavedRaceCollection  = "race_saved"
+	CooldownCollection   = "race_cooldowns"
 )
 
 // readConfig loads the race configuration from the database. If it does not exist, then
@@ -96,3 +101,155 @@ func writeRacer(racer *Avatar) {
 		slog.Error("failed to write the racer to the database", slog.String("guildID", racer.GuildID), slog.Any("error", err))
 	}
 }
+
+// readRaceRecord loads the saved record of a race from the database. If it does not exist, then
+// a `nil` value is returned.
+func readRaceRecord(guildID string, raceID bson.ObjectID) *RaceRecord {
+	filter := bson.D{{Key: "_id", Value: raceID}, {Key: "guild_id", Value: guildID}}
+	var record RaceRecord
+	err := db.FindOne(RaceRecordCollection, filter, &record)
+	if err != nil {
+		slog.Debug("race record not found in the database", slog.String("guildID", guildID), slog.String("raceID", raceID.Hex()), slog.Any("error", err))
+		return nil
+	}
+
+	return &record
+}
+
+// writeRaceRecord stores the record of a race in the database.
+func writeRaceRecord(record *RaceRecord) {
+	filter := bson.D{{Key: "_id", Value: record.ID}}
+	if err := db.UpdateOrInsert(RaceRecordCollection, filter, record); err != nil {
+		slog.Error("failed to write the race record to the database", slog.String("guildID", record.GuildID), slog.String("raceID", record.ID.Hex()), slog.Any("error", err))
+	}
+}
+
+// readRaceRecords loads the most recent races for the guild that match the filter, newest first.
+func readRaceRecords(filter bson.D, limit int64) ([]*RaceRecord, error) {
+	var records []*RaceRecord
+	sort := bson.D{{Key: "start_time", Value: -1}}
+	if err := db.FindMany(RaceRecordCollection, filter, &records, sort, limit); err != nil {
+		slog.Debug("unable to read race records", slog.Any("filter", filter), slog.Any("error", err))
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// readActiveTournament loads the tournament that is scheduled or running in the guild. If there
+// isn't one, then a `nil` value is returned.
+func readActiveTournament(guildID string) *Tournament {
+	filter := bson.D{
+		{Key: "guild_id", Value: guildID},
+		{Key: "state", Value: bson.D{{Key: "$in", Value: bson.A{TournamentScheduled, TournamentHeats}}}},
+	}
+	var tournament Tournament
+	err := db.FindOne(TournamentCollection, filter, &tournament)
+	if err != nil {
+		slog.Debug("race tournament not found in the database", slog.String("guildID", guildID), slog.Any("error", err))
+		return nil
+	}
+
+	return &tournament
+}
+
+// readActiveTournaments loads the tournaments that are scheduled or running in all guilds.
+func readActiveTournaments() ([]*Tournament, error) {
+	var tournaments []*Tournament
+	filter := bson.D{{Key: "state", Value: bson.D{{Key: "$in", Value: bson.A{TournamentScheduled, TournamentHeats}}}}}
+	sort := bson.D{{Key: "start", Value: 1}}
+	if err := db.FindMany(TournamentCollection, filter, &tournaments, sort, 0); err != nil {
+		slog.Debug("unable to read race tournaments", slog.Any("error", err))
+		return nil, err
+	}
+
+	return tournaments, nil
+}
+
+// writeTournament creates or updates the tournament in the database.
+func writeTournament(tournament *Tournament) {
+	filter := bson.D{{Key: "_id", Value: tournament.ID}}
+	if err := db.UpdateOrInsert(TournamentCollection, filter, tournament); err != nil {
+		slog.Error("failed to write the race tournament to the database", slog.String("guildID", tournament.GuildID), slog.String("name", tournament.Name), slog.Any("error", err))
+	}
+}
+
+// readStableRacers loads the racers owned by the member for the theme, in the order they were bought.
+func readStableRacers(guildID string, memberID string, themeName string) ([]*StableRacer, error) {
+	var racers []*StableRacer
+	filter := bson.D{{Key: "guild_id", Value: guildID}, {Key: "member_id", Value: memberID}, {Key: "theme", Value: themeName}}
+	sort := bson.D{{Key: "purchased_at", Value: 1}}
+	if err := db.FindMany(StableCollection, filter, &racers, sort, 0); err != nil {
+		slog.Error("unable to read the stable", slog.String("guildID", guildID), slog.String("memberID", memberID), slog.Any("error", err))
+		return nil, err
+	}
+
+	return racers, nil
+}
+
+// writeStableRacer creates or updates the owned racer in the database.
+func writeStableRacer(racer *StableRacer) {
+	if racer.ID == bson.NilObjectID {
+		racer.ID = bson.NewObjectID()
+	}
+	filter := bson.D{{Key: "_id", Value: racer.ID}}
+	if err := db.UpdateOrInsert(StableCollection, filter, racer); err != nil {
+		slog.Error("failed to write the racer to the stable", slog.String("guildID", racer.GuildID), slog.String("memberID", racer.MemberID), slog.String("name", racer.Name), slog.Any("error", err))
+	}
+}
+
+// readSavedRaces loads the saved races for all guilds.
+func readSavedRaces() []*SavedRace {
+	var races []*SavedRace
+	if err := db.FindMany(SavedRaceCollection, bson.M{}, &races, bson.M{}, 0); err != nil {
+		slog.Error("unable to read saved races", slog.Any("error", err))
+		return nil
+	}
+
+	return races
+}
+
+// writeSavedRace creates or updates the saved race for a guild in the database.
+func writeSavedRace(race *SavedRace) {
+	filter := bson.M{"guild_id": race.GuildID}
+	if err := db.UpdateOrInsert(SavedRaceCollection, filter, race); err != nil {
+		slog.Error("failed to write the race to the database", slog.String("guildID", race.GuildID), slog.Any("error", err))
+	}
+}
+
+// deleteSavedRace removes the saved race for a guild from the database.
+func deleteSavedRace(guildID string) {
+	filter := bson.M{"guild_id": guildID}
+	if err := db.DeleteMany(SavedRaceCollection, filter); err != nil {
+		slog.Error("failed to delete the race from the database", slog.String("guildID", guildID), slog.Any("error", err))
+	}
+}
+
+// readRaceCooldown loads the time of the last race in the guild. If it does not exist, then
+// a `nil` value is returned.
+func readRaceCooldown(guildID string) *RaceCooldown {
+	filter := bson.M{"guild_id": guildID}
+	var cooldown RaceCooldown
+	if err := db.FindOne(CooldownCollection, filter, &cooldown); err != nil {
+		slog.Debug("race cooldown not found in the database", slog.String("guildID", guildID), slog.Any("error", err))
+		return nil
+	}
+
+	return &cooldown
+}
+
+// writeRaceCooldown creates or updates the time of the last race in the guild.
+func writeRaceCooldown(cooldown *RaceCooldown) {
+	filter := bson.M{"guild_id": cooldown.GuildID}
+	if err := db.UpdateOrInsert(CooldownCollection, filter, cooldown); err != nil {
+		slog.Error("failed to write the race cooldown to the database", slog.String("guildID", cooldown.GuildID), slog.Any("error", err))
+	}
+}
+
+// deleteRaceCooldown removes the time of the last race in the guild from the database.
+func deleteRaceCooldown(guildID string) {
+	filter := bson.M{"guild_id": guildID}
+	if err := db.DeleteMany(CooldownCollection, filter); err != nil {
+		slog.Error("failed to delete the race cooldown from the database", slog.String("guildID", guildID), slog.Any("error", err))
+	}
+}
@@ -10,7 +10,10 @@ import (
 )
 
 var (
-	ErrAlreadyBetOnRace      = errors.New("you have already bet on the race")
+	ErrAlreadyBetOnRace      = errors.New("you have already placed this type of bet on the race")
+	ErrBetNotStarted         = errors.New("choose a type of bet before picking racers")
+	ErrDuplicatePicks        = errors.New("each racer may only be picked once")
+	ErrIncompletePicks       = errors.New("pick a racer for each position before placing the bet")
 	ErrAlreadyJoinedRace     = errors.New("you have already joined the race")
 	ErrBettingHasOpened      = errors.New("betting has opened, so you can't join the race")
 	ErrBettingNotOpened      = errors.New("betting has not opened yet")
@@ -18,6 +21,19 @@ var (
 	ErrRaceAlreadyInProgress = errors.New("you can't start a new race as one is already in progress")
 	ErrRaceHasStarted        = errors.New("the race has already started")
 	ErrRaceAlreadyFull       = errors.New("the race is already full")
+
+	ErrAlreadyInTournament        = errors.New("you have already entered the tournament")
+	ErrNoTournament               = errors.New("there isn't a race tournament scheduled")
+	ErrNotInTournament            = errors.New("you haven't entered the tournament")
+	ErrTournamentAlreadyScheduled = errors.New("a race tournament is already scheduled")
+	ErrTournamentStarted          = errors.New("the tournament has already started")
+
+	ErrAvatarNotFound = errors.New("there isn't a racer with that name for sale")
+	ErrFullyTrained   = errors.New("your racer is already fully trained")
+	ErrRacerNameTaken = errors.New("you already own a racer with that name")
+	ErrRacerNotFound  = errors.New("you don't own a racer with that name")
+
+	ErrStableUnavailable = errors.New("your stable couldn't be read, so try again later")
 )
 
 // ErrRaceFull is the maximum number of race members have already joined the race.
@@ -31,6 +47,52 @@ func (e ErrRaceFull) Error() string {
 	return p.Sprintf("you can't join the race, as there are already %d entered into the race", e.MaxNumRacersAllowed)
 }
 
+// ErrStableFull is returned when a member already owns the most racers allowed.
+type ErrStableFull struct {
+	MaxStableSize int
+}
+
+// Error returns the error message for ErrStableFull.
+func (e ErrStableFull) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your stable is full, as you can own at most %d racers", e.MaxStableSize)
+}
+
+// ErrInvalidRacerName is returned when the name given to a racer is empty or too long.
+type ErrInvalidRacerName struct {
+	MaxLength int
+}
+
+// Error returns the error message for ErrInvalidRacerName.
+func (e ErrInvalidRacerName) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your racer's name must be between 1 and %d characters", e.MaxLength)
+}
+
+// ErrInvalidStake is returned when a pari-mutuel bet is outside the configured limits.
+type ErrInvalidStake struct {
+	MinBetAmount int
+	MaxBetAmount int
+}
+
+// Error returns the error message for ErrInvalidStake.
+func (e ErrInvalidStake) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("your bet must be between %d and %d credits", e.MinBetAmount, e.MaxBetAmount)
+}
+
+// ErrNotEnoughRacersForBet is returned when there are too few racers to offer a type of bet.
+type ErrNotEnoughRacersForBet struct {
+	BetType   BetType
+	MinRacers int
+}
+
+// Error returns the error message for ErrNotEnoughRacersForBet.
+func (e ErrNotEnoughRacersForBet) Error() string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	return p.Sprintf("%s bets need at least %d racers", e.BetType, e.MinRacers)
+}
+
 // ErrRacersAreResting is the racers are resting, so the user should try again in a certain amount of time.
 type ErrRacersAreResting struct {
 	waitTime time.Duration
@@ -0,0 +1,215 @@
+package race
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/internal/history"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// raceHistory shows the member the most recent races in which they raced or placed a bet.
+func raceHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	count := history.Count(i.ApplicationCommandData().Options[0].Options)
+	sendMemberHistory(s, i, i.Member.User.ID, count)
+}
+
+// adminRaceHistory shows an admin the details of a single race, the history for a member, or the
+// most recent races in the guild.
+func adminRaceHistory(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	var raceID, memberID string
+	for _, option := range options {
+		switch option.Name {
+		case "id":
+			raceID = strings.TrimSpace(option.StringValue())
+		case "user":
+			memberID = option.UserValue(nil).ID
+		}
+	}
+	count := history.Count(options)
+
+	switch {
+	case raceID != "":
+		sendRaceDetails(s, i, raceID)
+	case memberID != "":
+		sendMemberHistory(s, i, memberID, count)
+	default:
+		sendRecentRaces(s, i, count)
+	}
+}
+
+// sendMemberHistory sends the most recent races in which the member raced or placed a bet.
+func sendMemberHistory(s *discordgo.Session, i *discordgo.InteractionCreate, memberID string, count int) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	filter := bson.D{
+		{Key: "guild_id", Value: i.GuildID},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "racers.member_id", Value: memberID}},
+			bson.D{{Key: "bets.member_id", Value: memberID}},
+		}},
+	}
+	records, err := readRaceRecords(filter, int64(count))
+	if err != nil {
+		slog.Error("failed to read the race history", slog.String("guildID", i.GuildID), slog.String("memberID", memberID), slog.Any("error", err))
+	}
+	if len(records) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("<@%s> has not raced or bet on a race", memberID))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(records))
+	for _, record := range records {
+		value := p.Sprintf("<t:%d:f>\n%s", record.StartTime.Unix(), formatMemberRace(record, memberID))
+		fields = append(fields, history.Field(p.Sprintf("Race %s", record.ID.Hex()), value))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Race History",
+		Description: p.Sprintf("The last %d races for <@%s>", len(records), memberID),
+		Fields:      fields,
+	}
+
+	history.Send(s, i, "race", embed)
+}
+
+// sendRecentRaces sends a summary of the most recent races in the guild.
+func sendRecentRaces(s *discordgo.Session, i *discordgo.InteractionCreate, count int) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	records, err := readRaceRecords(bson.D{{Key: "guild_id", Value: i.GuildID}}, int64(count))
+	if err != nil {
+		slog.Error("failed to read the race history", slog.String("guildID", i.GuildID), slog.Any("error", err))
+	}
+	if len(records) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent("No races have been run")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(records))
+	for _, record := range records {
+		winner := "none"
+		if len(record.Finishers) > 0 {
+			winner = formatRacer(record, record.Finishers[0].MemberID)
+		}
+		value := p.Sprintf("<t:%d:f>\nWinner: %s\n%d racers, %d bets", record.StartTime.Unix(), winner, len(record.Racers), len(record.Bets))
+		fields = append(fields, history.Field(p.Sprintf("Race %s", record.ID.Hex()), value))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Race History",
+		Description: p.Sprintf("The last %d races", len(records)),
+		Fields:      fields,
+	}
+
+	history.Send(s, i, "race", embed)
+}
+
+// sendRaceDetails sends the finishing order and all bets for a single race.
+func sendRaceDetails(s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	raceID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("%q is not a valid race ID", id))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	record := readRaceRecord(i.GuildID, raceID)
+	if record == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Race %s was not found", id))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	var finishers strings.Builder
+	for n, finisher := range record.Finishers {
+		finishers.WriteString(p.Sprintf("%d. %s %.2fs", n+1, formatRacer(record, finisher.MemberID), finisher.RaceTime))
+		if finisher.Winnings > 0 {
+			finishers.WriteString(p.Sprintf(", prize %d", finisher.Winnings))
+		}
+		finishers.WriteString("\n")
+	}
+
+	var bets strings.Builder
+	for _, bet := range record.Bets {
+		bets.WriteString(p.Sprintf("%s: %s\n", bet.Name, formatBet(record, bet)))
+	}
+	if bets.Len() == 0 {
+		bets.WriteString("No bets were placed")
+	}
+
+	betsName := "Bets"
+	if record.PariMutuel {
+		betsName = p.Sprintf("Bets (pool of %d, house cut %d)", record.BetPool, record.HouseCut)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       p.Sprintf("Race %s", record.ID.Hex()),
+		Description: p.Sprintf("<t:%d:f>", record.StartTime.Unix()),
+		Fields: []*discordgo.MessageEmbedField{
+			history.Field("Finish", finishers.String()),
+			history.Field(betsName, bets.String()),
+		},
+	}
+
+	history.Send(s, i, "race", embed)
+}
+
+// formatMemberRace returns how the member finished in the race and the results of their bets.
+func formatMemberRace(record *RaceRecord, memberID string) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	lines := make([]string, 0, 1+len(record.Bets))
+	for n, finisher := range record.Finishers {
+		if finisher.MemberID != memberID {
+			continue
+		}
+		line := p.Sprintf("Raced as %s, finished %s of %d in %.2fs", formatRacer(record, memberID), ordinal(n+1), len(record.Finishers), finisher.RaceTime)
+		if finisher.Winnings > 0 {
+			line += p.Sprintf(", prize %d", finisher.Winnings)
+		}
+		lines = append(lines, line)
+	}
+	for _, bet := range record.Bets {
+		if bet.MemberID == memberID {
+			lines = append(lines, "Bet "+formatBet(record, bet))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatBet returns the stake, type, picks and result of a bet.
+func formatBet(record *RaceRecord, bet *BetRecord) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	picks := make([]string, 0, len(bet.Picks))
+	for _, pick := range bet.Picks {
+		picks = append(picks, formatRacer(record, pick))
+	}
+	result := "lost"
+	if bet.Winnings > 0 {
+		result = p.Sprintf("paid %d", bet.Winnings)
+	}
+	return p.Sprintf("%d on %s (%s): %s", bet.Amount, bet.BetType, strings.Join(picks, ", "), result)
+}
+
+// formatRacer returns the avatar and name of a racer in the race.
+func formatRacer(record *RaceRecord, memberID string) string {
+	racer := record.getRacerRecord(memberID)
+	if racer == nil {
+		return fmt.Sprintf("<@%s>", memberID)
+	}
+	return racer.Emoji + " " + racer.Name
+}
+
+// ordinal returns the ordinal form of a finishing position, such as "1st" or "12th".
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
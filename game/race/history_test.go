@@ -0,0 +1,41 @@
+package race
+
+import (
+	"testing"
+)
+
+func TestOrdinal(t *testing.T) {
+	tests := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd"}
+	for n, want := range tests {
+		if got := ordinal(n); got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+}
+
+func TestFormatMemberRace(t *testing.T) {
+	record := &RaceRecord{
+		Racers: []*RacerRecord{
+			{MemberID: "1", Name: "alice", Emoji: "a"},
+			{MemberID: "2", Name: "bob", Emoji: "b"},
+		},
+		Finishers: []*FinisherRecord{
+			{MemberID: "2", RaceTime: 9.5, Winnings: 1000},
+			{MemberID: "1", RaceTime: 10.25, Winnings: 750},
+		},
+		Bets: []*BetRecord{
+			{MemberID: "1", Name: "alice", BetType: BetWin, Picks: []string{"1"}, Amount: 100},
+			{MemberID: "3", Name: "carol", BetType: BetExacta, Picks: []string{"2", "1"}, Amount: 200, Winnings: 1800},
+		},
+	}
+
+	want := "Raced as a alice, finished 2nd of 2 in 10.25s, prize 750\nBet 100 on Win (a alice): lost"
+	if got := formatMemberRace(record, "1"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	want = "Bet 200 on Exacta (b bob, a alice): paid 1,800"
+	if got := formatMemberRace(record, "3"); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
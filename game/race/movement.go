@@ -0,0 +1,348 @@
+package race
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+)
+
+var (
+	movementProfiles     = make(map[string]MovementProfiles)
+	movementProfilesLock = sync.Mutex{}
+)
+
+const (
+	babyDragonProfileName = "babydragon"
+)
+
+// defaultMovementProfile is used for racers whose movement speed does not match a profile in the theme.
+// Racers with an unknown speed have always moved like a baby dragon.
+var defaultMovementProfile = &MovementProfile{
+	Name:    babyDragonProfileName,
+	Aliases: []string{"special"},
+	Rules: []*MovementRule{
+		{Turns: []int{1, 2}, Distribution: []int{21}},
+		{Distribution: []int{0, 3, 6}, BonusChance: defaultBabyDragonBuffPercent, BonusMovement: 1},
+	},
+}
+
+// defaultMovementProfiles are the movement speeds used by themes whose avatar file doesn't define any
+// movement profiles, such as avatar files that are only a list of avatars.
+var defaultMovementProfiles = []*MovementProfile{
+	{Name: "veryfast", Rules: []*MovementRule{{Distribution: []int{0, 2, 4, 6, 8, 10, 12, 14}}}},
+	{Name: "fast", Rules: []*MovementRule{{Distribution: []int{0, 3, 6, 9, 12}}}},
+	{Name: "slow", Rules: []*MovementRule{{Distribution: []int{3, 6, 9}}}},
+	{Name: "steady", Rules: []*MovementRule{{Distribution: []int{6}}}},
+	{Name: "abberant", Rules: []*MovementRule{{Distribution: []int{0, 3, 6}, BurstChance: 30, BurstMovement: 15}}},
+	{Name: "predator", Rules: []*MovementRule{{Every: 2, Offset: 1, Distribution: []int{0}}, {Distribution: []int{6, 9, 12, 15}}}},
+	defaultMovementProfile,
+}
+
+// MovementProfile describes how far a racer with a given movement speed moves on each turn of a race.
+// The first rule that applies to the turn is used to calculate the movement.
+type MovementProfile struct {
+	Name    string          `json:"name" bson:"name"`
+	Aliases []string        `json:"aliases,omitempty" bson:"aliases,omitempty"`
+	Rules   []*MovementRule `json:"rules" bson:"rules"`
+}
+
+// MovementRule is the movement for the turns to which the rule applies. A rule applies to the listed
+// turns, to every Nth turn starting at the offset, or to all turns if neither is set. The movement is
+// picked at random from the distribution, so repeating a value makes it more likely. A burst replaces
+// the movement, and a bonus is added to it.
+type MovementRule struct {
+	Turns         []int `json:"turns,omitempty" bson:"turns,omitempty"`
+	Every         int   `json:"every,omitempty" bson:"every,omitempty"`
+	Offset        int   `json:"offset,omitempty" bson:"offset,omitempty"`
+	Distribution  []int `json:"distribution" bson:"distribution"`
+	BurstChance   int   `json:"burst_chance,omitempty" bson:"burst_chance,omitempty"`
+	BurstMovement int   `json:"burst_movement,omitempty" bson:"burst_movement,omitempty"`
+	BonusChance   int   `json:"bonus_chance,omitempty" bson:"bonus_chance,omitempty"`
+	BonusMovement int   `json:"bonus_movement,omitempty" bson:"bonus_movement,omitempty"`
+}
+
+// MovementProfiles are the movement profiles for a theme, indexed by their names and aliases.
+type MovementProfiles map[string]*MovementProfile
+
+// newMovementProfiles returns the movement profiles indexed by their names and aliases. Profiles that
+// are not valid are skipped, so racers using them fall back to the default movement.
+func newMovementProfiles(profiles []*MovementProfile) MovementProfiles {
+	indexed := make(MovementProfiles, len(profiles))
+	for _, profile := range profiles {
+		if problems := validateMovementProfile(profile); len(problems) > 0 {
+			slog.Error("skipping invalid movement profile", slog.Any("problems", problems))
+			continue
+		}
+		indexed[profile.Name] = profile
+		for _, alias := range profile.Aliases {
+			indexed[alias] = profile
+		}
+	}
+	return indexed
+}
+
+// getMovementProfiles returns the movement profiles for the theme, reading them from the theme's avatar
+// file the first time they are needed.
+func getMovementProfiles(themeName string) MovementProfiles {
+	movementProfilesLock.Lock()
+	defer movementProfilesLock.Unlock()
+
+	profiles, ok := movementProfiles[themeName]
+	if !ok {
+		file, err := readAvatarFile(themeName)
+		if err != nil {
+			slog.Error("failed to read movement profiles", slog.String("theme", themeName), slog.Any("error", err))
+			return MovementProfiles{}
+		}
+		profiles = newMovementProfiles(file.MovementProfiles)
+		movementProfiles[themeName] = profiles
+		slog.Debug("read movement profiles", slog.String("theme", themeName), slog.Int("count", len(file.MovementProfiles)))
+	}
+
+	return profiles
+}
+
+// get returns the profile for the movement speed. If the theme doesn't define it, then the racer moves
+// like a baby dragon, using the theme's profile if it has one or the default profile if it doesn't.
+func (profiles MovementProfiles) get(movementSpeed string) *MovementProfile {
+	if profile, ok := profiles[movementSpeed]; ok {
+		return profile
+	}
+	if profile, ok := profiles[babyDragonProfileName]; ok {
+		return profile
+	}
+	return defaultMovementProfile
+}
+
+// withBuff returns a copy of the profiles in which the bonus chance of the baby dragon profile is the
+// guild's buff percentage. The baby dragon profile is added if the theme doesn't define it, as it is
+// used by racers with an unknown movement speed.
+func (profiles MovementProfiles) withBuff(buffPercent int) MovementProfiles {
+	profile := profiles.get(babyDragonProfileName)
+	buffed := &MovementProfile{
+		Name:    profile.Name,
+		Aliases: profile.Aliases,
+		Rules:   make([]*MovementRule, 0, len(profile.Rules)),
+	}
+	for _, rule := range profile.Rules {
+		buffedRule := *rule
+		if buffedRule.BonusMovement > 0 {
+			buffedRule.BonusChance = buffPercent
+		}
+		buffed.Rules = append(buffed.Rules, &buffedRule)
+	}
+
+	result := maps.Clone(profiles)
+	if result == nil {
+		result = make(MovementProfiles, 1)
+	}
+	result[buffed.Name] = buffed
+	for _, alias := range buffed.Aliases {
+		if existing, ok := result[alias]; !ok || existing == profile {
+			result[alias] = buffed
+		}
+	}
+	return result
+}
+
+// used returns the distinct profiles used by the racers, so they can be saved with a race.
+func (profiles MovementProfiles) used(racers []*RaceParticipant) []*MovementProfile {
+	used := make([]*MovementProfile, 0, len(racers))
+	for _, racer := range racers {
+		profile := profiles.get(racer.Racer.MovementSpeed)
+		if !slices.Contains(used, profile) {
+			used = append(used, profile)
+		}
+	}
+	return used
+}
+
+// movement returns the distance moved on the given turn.
+func (profile *MovementProfile) movement(turn int, r *rand.Rand) int {
+	for _, rule := range profile.Rules {
+		if rule.appliesTo(turn) {
+			return rule.movement(r)
+		}
+	}
+	return 0
+}
+
+// appliesTo returns true if the rule is used to calculate the movement on the given turn.
+func (rule *MovementRule) appliesTo(turn int) bool {
+	switch {
+	case len(rule.Turns) > 0:
+		return slices.Contains(rule.Turns, turn)
+	case rule.Every > 0:
+		return turn%rule.Every == rule.Offset
+	default:
+		return true
+	}
+}
+
+// movement returns the distance moved by a racer using the rule. A random number is only drawn when the
+// outcome depends on it.
+func (rule *MovementRule) movement(r *rand.Rand) int {
+	if rule.BurstChance > 0 && r.IntN(100) < rule.BurstChance {
+		return rule.BurstMovement
+	}
+
+	movement := rule.Distribution[0]
+	if len(rule.Distribution) > 1 {
+		movement = rule.Distribution[r.IntN(len(rule.Distribution))]
+	}
+	if rule.BonusChance > 0 && r.IntN(100) < rule.BonusChance {
+		movement += rule.BonusMovement
+	}
+
+	return movement
+}
+
+// maxMovement returns the largest distance a racer using the rule can move in a turn.
+func (rule *MovementRule) maxMovement() int {
+	movement := slices.Max(rule.Distribution)
+	if rule.BonusChance > 0 {
+		movement += rule.BonusMovement
+	}
+	if rule.BurstChance > 0 {
+		movement = max(movement, rule.BurstMovement)
+	}
+	return movement
+}
+
+// validateMovementProfile returns the problems with a movement profile.
+func validateMovementProfile(profile *MovementProfile) []string {
+	problems := make([]string, 0)
+	if profile == nil {
+		return append(problems, "movement profile: missing")
+	}
+	path := fmt.Sprintf("movement_profiles[%s]", profile.Name)
+	if profile.Name == "" {
+		problems = append(problems, path+".name: must not be empty")
+	}
+	if len(profile.Rules) == 0 {
+		return append(problems, path+".rules: must have at least one rule")
+	}
+
+	moves := false
+	catchAll := false
+	for i, rule := range profile.Rules {
+		rulePath := fmt.Sprintf("%s.rules[%d]", path, i)
+		if rule == nil {
+			problems = append(problems, rulePath+": missing")
+			continue
+		}
+		if len(rule.Distribution) == 0 {
+			problems = append(problems, rulePath+".distribution: must have at least one value")
+			continue
+		}
+		if slices.Min(rule.Distribution) < 0 || rule.BurstMovement < 0 || rule.BonusMovement < 0 {
+			problems = append(problems, rulePath+": movement must not be negative")
+		}
+		if rule.Every < 0 || rule.Offset < 0 || (rule.Every > 0 && rule.Offset >= rule.Every) {
+			problems = append(problems, fmt.Sprintf("%s.offset: must be between 0 and %d, got %d", rulePath, max(rule.Every-1, 0), rule.Offset))
+		}
+		for _, chance := range []int{rule.BurstChance, rule.BonusChance} {
+			if chance < 0 || chance > 100 {
+				problems = append(problems, fmt.Sprintf("%s: chance must be between 0 and 100, got %d", rulePath, chance))
+			}
+		}
+		// Rules after the first one that applies to every turn are never used
+		if !catchAll && len(rule.Turns) == 0 && rule.Every == 0 {
+			catchAll = true
+			moves = rule.maxMovement() > 0
+		}
+	}
+	if !catchAll {
+		problems = append(problems, path+".rules: must have a rule that applies to every turn")
+	} else if !moves {
+		problems = append(problems, path+".rules: the rule that applies to every turn must be able to move the racer")
+	}
+
+	return problems
+}
+
+// validateMovementProfiles returns the problems with the movement profiles for a theme, including avatars
+// that use a movement speed that no profile defines.
+func validateMovementProfiles(profiles []*MovementProfile, avatars []*Avatar) []string {
+	problems := make([]string, 0)
+	names := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		problems = append(problems, validateMovementProfile(profile)...)
+		if profile == nil {
+			continue
+		}
+		for _, name := range append([]string{profile.Name}, profile.Aliases...) {
+			if names[name] {
+				problems = append(problems, fmt.Sprintf("movement_profiles[%s]: %q is defined more than once", profile.Name, name))
+			}
+			names[name] = true
+		}
+	}
+	for _, avatar := range avatars {
+		if !names[avatar.MovementSpeed] {
+			problems = append(problems, fmt.Sprintf("avatars[%s].movement_speed: no profile named %q", avatar.Emoji, avatar.MovementSpeed))
+		}
+	}
+
+	return problems
+}
+
+// ProfileStats are the results of simulated races for a movement profile.
+type ProfileStats struct {
+	Name    string // Name of the movement profile
+	Entries int    // Number of racers that used the profile
+	Wins    int    // Number of races won by a racer using the profile
+}
+
+// WinRate returns the percentage of racers using the profile that won their race.
+func (stats *ProfileStats) WinRate() float64 {
+	if stats.Entries == 0 {
+		return 0
+	}
+	return float64(stats.Wins) / float64(stats.Entries) * 100
+}
+
+// simulateProfiles runs the given number of races, each with racers using randomly chosen movement profiles,
+// and returns the number of racers and wins for each profile. Profiles that are not valid are skipped.
+func simulateProfiles(profiles []*MovementProfile, numRaces int, numRacers int, trackLength int, r *rand.Rand) []*ProfileStats {
+	profiles = slices.DeleteFunc(slices.Clone(profiles), func(profile *MovementProfile) bool {
+		return len(validateMovementProfile(profile)) > 0
+	})
+	stats := make([]*ProfileStats, 0, len(profiles))
+	byProfile := make(map[*MovementProfile]*ProfileStats, len(profiles))
+	for _, profile := range profiles {
+		profileStats := &ProfileStats{Name: profile.Name}
+		stats = append(stats, profileStats)
+		byProfile[profile] = profileStats
+	}
+	if len(profiles) == 0 || numRacers == 0 {
+		return stats
+	}
+
+	indexed := newMovementProfiles(profiles)
+	for range numRaces {
+		race := &Race{
+			Seed:       r.Int64(),
+			Racers:     make([]*RaceParticipant, 0, numRacers),
+			RaceResult: &RaceResult{},
+			config:     &Config{MinPrizeAmount: 0, MaxPrizeAmount: 1},
+			profiles:   indexed,
+		}
+		for i := range numRacers {
+			profile := profiles[r.IntN(len(profiles))]
+			race.Racers = append(race.Racers, &RaceParticipant{
+				Member: &RaceMember{MemberID: fmt.Sprintf("%d", i)},
+				Racer:  &Avatar{Emoji: profile.Name, MovementSpeed: profile.Name},
+			})
+			byProfile[profile].Entries++
+		}
+		race.simulate(trackLength)
+		winner := race.RaceResult.Win.Participant.Racer
+		byProfile[indexed.get(winner.MovementSpeed)].Wins++
+	}
+
+	return stats
+}
@@ -0,0 +1,189 @@
+package race
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rbrabson/goblin/discord"
+)
+
+func TestMovementProfile(t *testing.T) {
+	profile := &MovementProfile{
+		Name: "test",
+		Rules: []*MovementRule{
+			{Turns: []int{1}, Distribution: []int{21}},
+			{Every: 2, Offset: 0, Distribution: []int{0}},
+			{Distribution: []int{3}, BonusChance: 100, BonusMovement: 1},
+		},
+	}
+	burst := &MovementProfile{
+		Name:  "burst",
+		Rules: []*MovementRule{{Distribution: []int{3}, BurstChance: 100, BurstMovement: 15}},
+	}
+
+	tests := []struct {
+		name    string
+		profile *MovementProfile
+		turn    int
+		want    int
+	}{
+		{name: "listed turn", profile: profile, turn: 1, want: 21},
+		{name: "every other turn", profile: profile, turn: 2, want: 0},
+		{name: "bonus", profile: profile, turn: 3, want: 4},
+		{name: "burst", profile: burst, turn: 1, want: 15},
+	}
+
+	r := newRaceRand(1, simulationStream)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.movement(tt.turn, r); got != tt.want {
+				t.Errorf("expected movement of %d, got %d", tt.want, got)
+			}
+		})
+	}
+
+	profiles := newMovementProfiles([]*MovementProfile{{Name: "babydragon", Aliases: []string{"special"}, Rules: profile.Rules}})
+	if profiles.get("special") != profiles.get("babydragon") {
+		t.Error("expected the alias to use the same profile")
+	}
+	if profiles.get("unknown") != profiles.get("babydragon") {
+		t.Error("expected an unknown movement speed to use the baby dragon profile")
+	}
+	if newMovementProfiles(nil).get("unknown") != defaultMovementProfile {
+		t.Error("expected an unknown movement speed to use the default profile when the theme has no baby dragon")
+	}
+
+	buffed := profiles.withBuff(0)
+	if got := buffed.get("special").movement(3, r); got != 3 {
+		t.Errorf("expected a baby dragon without a buff to move 3, got %d", got)
+	}
+	if profiles.get("babydragon").Rules[2].BonusChance != 100 {
+		t.Error("expected the buff to leave the theme's profile unchanged")
+	}
+	if buffed := newMovementProfiles(nil).withBuff(100); buffed.get("unknown").Rules[1].BonusChance != 100 {
+		t.Error("expected the buff to apply to the default profile")
+	}
+}
+
+func TestBabyDragonBuff(t *testing.T) {
+	now := time.Now()
+	profiles := newMovementProfiles(nil)
+	tests := []struct {
+		name    string
+		expires time.Time
+		want    int
+	}{
+		{"no buff", time.Time{}, defaultBabyDragonBuffPercent},
+		{"active buff", now.Add(time.Hour), 100},
+		{"expired buff", now.Add(-time.Hour), defaultBabyDragonBuffPercent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{BabyDragonBuffPercent: 100, BabyDragonBuffExpires: tt.expires}
+			if got := config.movementProfiles(profiles, now).get(babyDragonProfileName).Rules[1].BonusChance; got != tt.want {
+				t.Errorf("expected a bonus chance of %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadLegacyAvatarFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "race", "avatars"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	legacy := `[{"emoji": "a", "movement_speed": "fast"}, {"emoji": "b", "movement_speed": "special"}]`
+	if err := os.WriteFile(filepath.Join(dir, "race", "avatars", "legacy.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	configDir := discord.ConfigDir
+	discord.ConfigDir = dir
+	defer func() { discord.ConfigDir = configDir }()
+
+	file, err := readAvatarFile("legacy")
+	if err != nil {
+		t.Fatalf("expected the list of avatars to be read, got %v", err)
+	}
+	if len(file.Avatars) != 2 {
+		t.Fatalf("expected 2 avatars, got %d", len(file.Avatars))
+	}
+	if problems := validateMovementProfiles(file.MovementProfiles, file.Avatars); len(problems) > 0 {
+		t.Errorf("expected the default movement profiles to cover the avatars, got %v", problems)
+	}
+}
+
+func TestValidateMovementProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []*MovementProfile
+		avatars  []*Avatar
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			profiles: []*MovementProfile{{Name: "steady", Rules: []*MovementRule{{Distribution: []int{6}}}}},
+			avatars:  []*Avatar{{Emoji: "a", MovementSpeed: "steady"}},
+		},
+		{
+			name:     "no rule for every turn",
+			profiles: []*MovementProfile{{Name: "steady", Rules: []*MovementRule{{Turns: []int{1}, Distribution: []int{6}}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "never moves",
+			profiles: []*MovementProfile{{Name: "stuck", Rules: []*MovementRule{{Distribution: []int{0}}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "bad chance",
+			profiles: []*MovementProfile{{Name: "lucky", Rules: []*MovementRule{{Distribution: []int{3}, BurstChance: 120}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate name",
+			profiles: []*MovementProfile{{Name: "a", Rules: []*MovementRule{{Distribution: []int{3}}}}, {Name: "b", Aliases: []string{"a"}, Rules: []*MovementRule{{Distribution: []int{3}}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown movement speed",
+			profiles: []*MovementProfile{{Name: "steady", Rules: []*MovementRule{{Distribution: []int{6}}}}},
+			avatars:  []*Avatar{{Emoji: "a", MovementSpeed: "fast"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := validateMovementProfiles(tt.profiles, tt.avatars)
+			if (len(problems) > 0) != tt.wantErr {
+				t.Errorf("expected problems=%t, got %v", tt.wantErr, problems)
+			}
+		})
+	}
+}
+
+func TestThemeMovementProfiles(t *testing.T) {
+	bytes, err := os.ReadFile("../../config/race/avatars/clash.json")
+	if err != nil {
+		t.Skip("avatar file is not available")
+	}
+	var file avatarFile
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		t.Fatal(err)
+	}
+	if problems := validateMovementProfiles(file.MovementProfiles, file.Avatars); len(problems) > 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	stats := simulateProfiles(file.MovementProfiles, 200, 10, 80, newRaceRand(1, simulationStream))
+	wins := 0
+	for _, profileStats := range stats {
+		wins += profileStats.Wins
+	}
+	if wins != 200 {
+		t.Errorf("expected 200 wins, got %d", wins)
+	}
+}
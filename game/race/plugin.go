@@ -64,6 +64,11 @@ func (plugin *Plugin) Initialize(b *discord.Bot, d *mongo.MongoDB) {
 	if raceTheme == "" {
 		raceTheme = defaultRaceTheme
 	}
+
+	bot.Session.AddHandlerOnce(func(s *discordgo.Session, r *discordgo.Ready) {
+		restoreRaces()
+		restoreTournaments()
+	})
 }
 
 // GetCommands returns the commands for the banking system
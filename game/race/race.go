@@ -5,11 +5,17 @@ import (
 	"log/slog"
 	"math/rand/v2"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/bank"
 	"github.com/rbrabson/goblin/stats"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
 )
 
 const (
@@ -19,6 +25,12 @@ const (
 	RaceFinished
 )
 
+const (
+	avatarStream     = 1 // Stream of random numbers used to assign avatars to the racers
+	simulationStream = 2 // Stream of random numbers used to run the race
+	heatStream       = 3 // First stream of random numbers used to draw tournament heats, one per heat
+)
+
 var (
 	lastRaceTimes = make(map[string]time.Time)
 	currentRaces  = make(map[string]*Race)
@@ -29,7 +41,10 @@ var (
 // It contains a list of racers who are particpaing in the race as well as
 // betters on the outcome of the race.
 type Race struct {
+	ID            bson.ObjectID                // ID of the saved record of the race
+	TournamentID  bson.ObjectID                // Tournament for which the race is a heat or final, if any
 	GuildID       string                       // Guild (server) on which the race is taking place
+	Seed          int64                        // Seed for all random numbers used in the race, so it may be replayed
 	Racers        []*RaceParticipant           // The list of participants who are racing
 	Betters       []*RaceBetter                // The list of members who are betting on the outcome of the race
 	RaceLegs      []*RaceLeg                   // The list of legs in the race
@@ -37,22 +52,65 @@ type Race struct {
 	RaceStartTime time.Time                    // The time at which the race is started (first created)
 	state         int                          // The state of the race
 	raceAvatars   []*Avatar                    // The avatars of the racers
+	rng           *rand.Rand                   // Random numbers used to assign avatars to the racers
+	profiles      MovementProfiles             // Movement profiles for the avatars in the race's theme
+	pendingBets   map[string]*pendingBet       // Exotic bets that members are still choosing, by member ID
 	interaction   *discordgo.InteractionCreate // Interaction used in sending message updates
 	config        *Config                      // Race configuration (avoids having to read from the database)
 	mutex         sync.Mutex                   // Lock used to synchronize access to the race
 }
 
+// SavedRace is the saved state of a race that is taking members or bets, or is being run. It is used
+// to refund the bets placed on a race that is interrupted by a restart of the bot.
+type SavedRace struct {
+	ID        bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID   string        `json:"guild_id" bson:"guild_id"`
+	ChannelID string        `json:"channel_id" bson:"channel_id"`
+	Seed      int64         `json:"seed" bson:"seed"`
+	State     int           `json:"state" bson:"state"`
+	StartTime time.Time     `json:"start_time" bson:"start_time"`
+	RacerIDs  []string      `json:"racer_ids" bson:"racer_ids"`
+	Bets      []*SavedBet   `json:"bets" bson:"bets"`
+}
+
+// SavedBet is a bet placed on a saved race.
+type SavedBet struct {
+	MemberID string  `json:"member_id" bson:"member_id"`
+	BetType  BetType `json:"bet_type" bson:"bet_type"`
+	Amount   int     `json:"amount" bson:"amount"`
+	Settled  bool    `json:"settled,omitempty" bson:"settled,omitempty"`
+}
+
+// RaceCooldown is the time the last race in a guild finished, so the racers keep resting
+// if the bot is restarted.
+type RaceCooldown struct {
+	ID           bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID      string        `json:"guild_id" bson:"guild_id"`
+	LastRaceTime time.Time     `json:"last_race_time" bson:"last_race_time"`
+}
+
 // RaceParticipant is a member who is racing. This includes the member and the racer assigned to them.
 type RaceParticipant struct {
-	Member *RaceMember // Member who is racing
-	Racer  *Avatar     // Racer assigned to the member
+	Member *RaceMember  // Member who is racing
+	Racer  *Avatar      // Racer assigned to the member
+	Stable *StableRacer // Racer owned by the member, if the member is racing with one
 }
 
 // RaceBetter is a member who is betting on the outcome of the race.
 type RaceBetter struct {
-	Member   *RaceMember      // Member who is betting on the outcome of the race
-	Racer    *RaceParticipant // Racer on which the member is betting
-	Winnings int              // Amount won by the better
+	Member   *RaceMember        // Member who is betting on the outcome of the race
+	Racer    *RaceParticipant   // Racer on which the member is betting (the first pick for exactas and trifectas)
+	BetType  BetType            // Type of bet placed by the member
+	Picks    []*RaceParticipant // Racers picked by the member, in finishing order
+	Amount   int                // Amount staked by the better
+	Winnings int                // Amount won by the better
+	Settled  bool               // Whether the bet has been paid or lost
+}
+
+// pendingBet is a place, show, exacta or trifecta bet for which the member is still picking racers.
+type pendingBet struct {
+	BetType BetType            // Type of bet being placed
+	Picks   []*RaceParticipant // Racers picked so far, in finishing order
 }
 
 // RaceResult is the final results of the race. This includes the winner, 2nd place, and 3rd place finishers, as
@@ -102,23 +160,33 @@ func CreateNewRace(guildID string) (*Race, error) {
 		return nil, err
 	}
 
-	config := GetConfig(guildID)
+	race := newRace(guildID, GetConfig(guildID), RaceWaitingForRacers)
+	currentRaces[guildID] = race
+
+	return race, nil
+}
+
+// newRace returns a race for the guild with a new seed and no racers or bets.
+func newRace(guildID string, config *Config, state int) *Race {
+	seed := rand.Int64()
+	rng := newRaceRand(seed, avatarStream)
 
-	race := &Race{
+	return &Race{
 		GuildID:       guildID,
+		Seed:          seed,
 		Racers:        make([]*RaceParticipant, 0, 10),
 		Betters:       make([]*RaceBetter, 0, 10),
 		RaceStartTime: time.Now(),
 		RaceResult:    &RaceResult{},
-		state:         RaceWaitingForRacers,
-		raceAvatars:   getRaceAvatars(guildID, config.Theme),
+		state:         state,
+		raceAvatars:   getRaceAvatars(guildID, config.Theme, rng),
+		rng:           rng,
+		profiles:      config.movementProfiles(getMovementProfiles(config.Theme), time.Now()),
+		pendingBets:   make(map[string]*pendingBet),
 		interaction:   nil,
 		config:        config,
 		mutex:         sync.Mutex{},
 	}
-	currentRaces[guildID] = race
-
-	return race, nil
 }
 
 // Set the race state
@@ -127,6 +195,7 @@ func (r *Race) setState(state int) {
 	defer r.mutex.Unlock()
 
 	r.state = state
+	r.save()
 }
 
 // addRaceParticiapnt returns a new race participant for a member in the race. The race
@@ -146,11 +215,14 @@ func (r *Race) addRaceParticipant(member *RaceMember) (*RaceParticipant, error)
 		return nil, errors.New("current race has changed")
 	}
 
+	avatar, stableRacer := getParticipantAvatar(r, member.MemberID)
 	participant := &RaceParticipant{
 		Member: member,
-		Racer:  getRaceAvatar(r),
+		Racer:  avatar,
+		Stable: stableRacer,
 	}
 	r.Racers = append(r.Racers, participant)
+	r.save()
 
 	return participant, nil
 }
@@ -169,22 +241,86 @@ func (r *Race) getRaceParticipant(memberID string) *RaceParticipant {
 	return nil
 }
 
-// getRaceBetter returns a new better for a race.
-func getRaceBetter(member *RaceMember, racer *RaceParticipant) *RaceBetter {
+// getRaceBetter returns a new better for a race. The picks are the racers chosen for the bet, in
+// finishing order.
+func getRaceBetter(member *RaceMember, betType BetType, picks []*RaceParticipant, amount int) *RaceBetter {
 	raceBetter := &RaceBetter{
-		Member: member,
-		Racer:  racer,
+		Member:  member,
+		Racer:   picks[0],
+		BetType: betType,
+		Picks:   picks,
+		Amount:  amount,
 	}
 
 	return raceBetter
 }
 
+// getBetMember returns the race member for a member who has already placed a bet on the race,
+// so multiple bets by the same member update the same stats. If the member has not placed a
+// bet, then nil is returned.
+func (r *Race) getBetMember(memberID string) *RaceMember {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, better := range r.Betters {
+		if better.Member.MemberID == memberID {
+			return better.Member
+		}
+	}
+	return nil
+}
+
+// startPendingBet starts a place, show, exacta or trifecta bet for the member.
+func (r *Race) startPendingBet(memberID string, betType BetType) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.pendingBets[memberID] = &pendingBet{
+		BetType: betType,
+		Picks:   make([]*RaceParticipant, betType.Picks()),
+	}
+}
+
+// setPendingBetPick sets the racer picked by the member to finish in the given position.
+func (r *Race) setPendingBetPick(memberID string, index int, racer *RaceParticipant) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	bet := r.pendingBets[memberID]
+	if bet == nil || index < 0 || index >= len(bet.Picks) {
+		return false
+	}
+	bet.Picks[index] = racer
+	return true
+}
+
+// getPendingBet returns the bet the member is still picking racers for, or nil if there isn't one.
+func (r *Race) getPendingBet(memberID string) *pendingBet {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	bet := r.pendingBets[memberID]
+	if bet == nil {
+		return nil
+	}
+	return &pendingBet{BetType: bet.BetType, Picks: append([]*RaceParticipant(nil), bet.Picks...)}
+}
+
+// removePendingBet removes the bet the member was picking racers for.
+func (r *Race) removePendingBet(memberID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.pendingBets, memberID)
+}
+
 // addBetter adds a better for the given race.
 func (r *Race) addBetter(better *RaceBetter) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
 	r.Betters = append(r.Betters, better)
+	r.save()
 	slog.Debug("add better to current race",
 		slog.String("guildID", r.GuildID),
 		slog.String("memberID", better.Member.MemberID),
@@ -199,6 +335,48 @@ func (r *Race) runRace(trackLength int) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	r.simulate(trackLength)
+
+	if len(r.Racers) <= 2 {
+		slog.Info("race finished",
+			slog.String("guildID", r.GuildID),
+			slog.Int64("seed", r.Seed),
+			slog.Int("numRacers", len(r.Racers)),
+			slog.String("first", r.RaceResult.Win.Participant.Member.guildMember.Name),
+			slog.String("second", r.RaceResult.Place.Participant.Member.guildMember.Name),
+		)
+	} else {
+		slog.Info("race finished",
+			slog.String("guildID", r.GuildID),
+			slog.Int64("seed", r.Seed),
+			slog.Int("numRacers", len(r.Racers)),
+			slog.String("first", r.RaceResult.Win.Participant.Member.guildMember.Name),
+			slog.String("second", r.RaceResult.Place.Participant.Member.guildMember.Name),
+			slog.String("third", r.RaceResult.Show.Participant.Member.guildMember.Name),
+		)
+	}
+	lastLeg := r.RaceLegs[len(r.RaceLegs)-1]
+	for i, position := range lastLeg.ParticipantPositions {
+		slog.Info("race result",
+			slog.String("guildID", r.GuildID),
+			slog.String("memberID", position.RaceParticipant.Member.MemberID),
+			slog.String("memberName", position.RaceParticipant.Member.guildMember.Name),
+			slog.Int("position", i+1),
+			slog.Float64("raceTime", position.Speed),
+		)
+	}
+
+	record := newRaceRecord(r, trackLength)
+	writeRaceRecord(record)
+	r.ID = record.ID
+}
+
+// simulate runs each leg of the race and calculates the winners. All random numbers are drawn
+// from a generator created from the race's seed, so running the simulation again with the same
+// racers and seed produces the same results.
+func (r *Race) simulate(trackLength int) {
+	rng := newRaceRand(r.Seed, simulationStream)
+
 	// Create the initial starting positions and add them to an initial race leg
 	raceLeg := &RaceLeg{
 		ParticipantPositions: make([]*RaceParticipantPosition, 0, len(r.Racers)),
@@ -227,7 +405,7 @@ func (r *Race) runRace(trackLength int) {
 		// Run the new race leg
 		stillRacing = false
 		for _, previousPosition := range previousLeg.ParticipantPositions {
-			newPosition := moveRacer(previousPosition, turn)
+			newPosition := moveRacer(previousPosition, turn, rng, r.profiles)
 			newRaceLeg.ParticipantPositions = append(newRaceLeg.ParticipantPositions, newPosition)
 			if !newPosition.Finished {
 				stillRacing = true
@@ -238,34 +416,7 @@ func (r *Race) runRace(trackLength int) {
 		previousLeg = newRaceLeg
 	}
 
-	calculateWinnings(r, previousLeg)
-
-	if len(r.Racers) <= 2 {
-		slog.Info("race finished",
-			slog.String("guildID", r.GuildID),
-			slog.Int("numRacers", len(r.Racers)),
-			slog.String("first", r.RaceResult.Win.Participant.Member.guildMember.Name),
-			slog.String("second", r.RaceResult.Place.Participant.Member.guildMember.Name),
-		)
-	} else {
-		slog.Info("race finished",
-			slog.String("guildID", r.GuildID),
-			slog.Int("numRacers", len(r.Racers)),
-			slog.String("first", r.RaceResult.Win.Participant.Member.guildMember.Name),
-			slog.String("second", r.RaceResult.Place.Participant.Member.guildMember.Name),
-			slog.String("third", r.RaceResult.Show.Participant.Member.guildMember.Name),
-		)
-	}
-	lastLeg := r.RaceLegs[len(r.RaceLegs)-1]
-	for i, position := range lastLeg.ParticipantPositions {
-		slog.Info("race result",
-			slog.String("guildID", r.GuildID),
-			slog.String("memberID", position.RaceParticipant.Member.MemberID),
-			slog.String("memberName", position.RaceParticipant.Member.guildMember.Name),
-			slog.Int("position", i+1),
-			slog.Float64("raceTime", position.Speed),
-		)
-	}
+	calculateWinnings(r, previousLeg, rng)
 }
 
 // End ends the current race.
@@ -278,36 +429,58 @@ func (r *Race) End() {
 	raceLock.Lock()
 	if len(r.Racers) >= r.config.MinNumRacers {
 		lastRaceTimes[r.GuildID] = time.Now()
+		writeRaceCooldown(&RaceCooldown{GuildID: r.GuildID, LastRaceTime: lastRaceTimes[r.GuildID]})
 	}
 	delete(currentRaces, r.GuildID)
 	raceLock.Unlock()
 
 	if r.RaceResult != nil && len(r.Racers) >= r.config.MinNumRacers {
-		slog.Debug("processing race results", slog.String("guildID", r.GuildID), slog.Int("numRacers", len(r.Racers)))
-		for _, racer := range r.Racers {
-			switch {
-			case r.RaceResult.Win != nil && racer.Member.MemberID == r.RaceResult.Win.Participant.Member.MemberID:
-				racer.Member.WinRace(r.RaceResult.Win.Winnings)
-			case r.RaceResult.Place != nil && racer.Member.MemberID == r.RaceResult.Place.Participant.Member.MemberID:
-				racer.Member.PlaceInRace(r.RaceResult.Place.Winnings)
-			case r.RaceResult.Show != nil && racer.Member.MemberID == r.RaceResult.Show.Participant.Member.MemberID:
-				racer.Member.ShowInRace(r.RaceResult.Show.Winnings)
-			default:
-				racer.Member.LoseRace()
-			}
+		r.recordResults()
+	}
+
+	// The saved race is only deleted once the results are paid. Bets that weren't paid are refunded if
+	// the bot is restarted before then.
+	if r.interaction != nil {
+		deleteSavedRace(r.GuildID)
+	}
+	r.updateGameStats()
+}
+
+// recordResults pays the prizes and winning bets, and records the results of the race for the racers,
+// the betters and any racers from the members' stables.
+func (r *Race) recordResults() {
+	slog.Debug("processing race results", slog.String("guildID", r.GuildID), slog.Int("numRacers", len(r.Racers)))
+	for _, racer := range r.Racers {
+		switch {
+		case r.RaceResult.Win != nil && racer.Member.MemberID == r.RaceResult.Win.Participant.Member.MemberID:
+			racer.Member.WinRace(r.RaceResult.Win.Winnings)
+		case r.RaceResult.Place != nil && racer.Member.MemberID == r.RaceResult.Place.Participant.Member.MemberID:
+			racer.Member.PlaceInRace(r.RaceResult.Place.Winnings)
+		case r.RaceResult.Show != nil && racer.Member.MemberID == r.RaceResult.Show.Participant.Member.MemberID:
+			racer.Member.ShowInRace(r.RaceResult.Show.Winnings)
+		default:
+			racer.Member.LoseRace()
 		}
+	}
 
-		slog.Debug("processing race bets", slog.String("guildID", r.GuildID), slog.Int("numBetters", len(r.Betters)))
-		// Pay the winning bets
-		for _, better := range r.Betters {
-			if better.Winnings != 0 {
-				better.Member.WinBet(better.Winnings)
-			} else {
-				better.Member.LoseBet()
-			}
+	slog.Debug("processing race bets", slog.String("guildID", r.GuildID), slog.Int("numBetters", len(r.Betters)))
+	// Pay the winning bets. Each bet is saved as settled once it is paid, so it isn't refunded if the
+	// bot is restarted before the rest are paid.
+	for _, better := range r.Betters {
+		if better.Winnings != 0 {
+			better.Member.WinBet(better.Winnings)
+		} else {
+			better.Member.LoseBet()
 		}
+		better.Settled = true
+		r.save()
 	}
 
+	updateStableRecords(r)
+}
+
+// updateGameStats records that the racers played the race game.
+func (r *Race) updateGameStats() {
 	memberIDs := make([]string, 0, len(r.Racers))
 	for _, racer := range r.Racers {
 		memberIDs = append(memberIDs, racer.Member.MemberID)
@@ -324,9 +497,121 @@ func ResetRace(guildID string) {
 
 	delete(currentRaces, guildID)
 	delete(lastRaceTimes, guildID)
+	deleteSavedRace(guildID)
+	deleteRaceCooldown(guildID)
 	slog.Info("reset race", slog.String("guildID", guildID))
 }
 
+// save saves the state of the race, so the bets may be refunded if the bot is restarted before the
+// race ends. Races that aren't started by a member, such as tournament races, have no channel to
+// report to and aren't saved. The caller must hold the race's mutex.
+func (r *Race) save() {
+	if r.interaction == nil {
+		return
+	}
+
+	saved := &SavedRace{
+		GuildID:   r.GuildID,
+		ChannelID: r.interaction.ChannelID,
+		Seed:      r.Seed,
+		State:     r.state,
+		StartTime: r.RaceStartTime,
+		RacerIDs:  make([]string, 0, len(r.Racers)),
+		Bets:      make([]*SavedBet, 0, len(r.Betters)),
+	}
+	for _, racer := range r.Racers {
+		saved.RacerIDs = append(saved.RacerIDs, racer.Member.MemberID)
+	}
+	for _, better := range r.Betters {
+		saved.Bets = append(saved.Bets, &SavedBet{
+			MemberID: better.Member.MemberID,
+			BetType:  better.BetType,
+			Amount:   better.Amount,
+			Settled:  better.Settled,
+		})
+	}
+	writeSavedRace(saved)
+}
+
+// getLastRaceTime returns the time the last race in the guild finished. The time is read from the
+// database the first time it is needed, so the cooldown survives a restart of the bot. The caller
+// must hold the raceLock.
+func getLastRaceTime(guildID string) time.Time {
+	lastRaceTime, ok := lastRaceTimes[guildID]
+	if !ok {
+		if cooldown := readRaceCooldown(guildID); cooldown != nil {
+			lastRaceTime = cooldown.LastRaceTime
+		}
+		lastRaceTimes[guildID] = lastRaceTime
+	}
+	return lastRaceTime
+}
+
+// restoreRaces cancels any race that was taking members or bets, or was being run, when the bot was
+// stopped. Each bet is returned to the member who placed it, and a message is sent to the channel in
+// which the race was started. This is called when the bot starts.
+func restoreRaces() {
+	for _, saved := range readSavedRaces() {
+		refundSavedRace(saved)
+		deleteSavedRace(saved.GuildID)
+	}
+}
+
+// refundSavedRace returns the bets placed on an interrupted race that weren't settled before it was
+// interrupted.
+func refundSavedRace(saved *SavedRace) {
+	refunds := make(map[string]int, len(saved.Bets))
+	memberIDs := make([]string, 0, len(saved.Bets))
+	for _, bet := range saved.Bets {
+		if bet.Settled {
+			continue
+		}
+		if _, ok := refunds[bet.MemberID]; !ok {
+			memberIDs = append(memberIDs, bet.MemberID)
+		}
+		refunds[bet.MemberID] += bet.Amount
+	}
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	var sb strings.Builder
+	for _, memberID := range memberIDs {
+		amount := refunds[memberID]
+		account := bank.GetAccount(saved.GuildID, memberID)
+		if err := account.Deposit(amount); err != nil {
+			slog.Error("failed to refund race bet",
+				slog.String("guildID", saved.GuildID),
+				slog.String("memberID", memberID),
+				slog.Int("amount", amount),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		sb.WriteString(p.Sprintf("- <@%s>: %d credits\n", memberID, amount))
+	}
+	slog.Info("cancelled interrupted race",
+		slog.String("guildID", saved.GuildID),
+		slog.Int("state", saved.State),
+		slog.Int("racers", len(saved.RacerIDs)),
+		slog.Int("bets", len(saved.Bets)),
+	)
+
+	if saved.ChannelID == "" || bot == nil {
+		return
+	}
+	content := "The race was interrupted and has been cancelled."
+	if sb.Len() > 0 {
+		content += " The following bets have been returned:\n" + sb.String()
+	}
+	msg := disgomsg.NewMessage(disgomsg.WithContent(content))
+	if _, err := msg.Send(bot.Session, saved.ChannelID); err != nil {
+		slog.Error("failed to send race cancellation message",
+			slog.String("guildID", saved.GuildID),
+			slog.String("channelID", saved.ChannelID),
+			slog.Any("error", err),
+		)
+	}
+}
+
 // IsFull checks to see if the race has already reached the maximum number of racers.
 func (r *Race) IsFull() bool {
 	raceLock.Lock()
@@ -350,7 +635,7 @@ func (r *Race) GetRacerNames() []string {
 // getRaceAvatar returns a random race avatar to be used by a race participant.
 func getRaceAvatar(race *Race) *Avatar {
 	if len(race.raceAvatars) == 0 {
-		race.raceAvatars = getRaceAvatars(race.GuildID, race.config.Theme)
+		race.raceAvatars = getRaceAvatars(race.GuildID, race.config.Theme, race.rng)
 	}
 
 	index := len(race.raceAvatars) - 1
@@ -361,7 +646,7 @@ func getRaceAvatar(race *Race) *Avatar {
 }
 
 // moveRacer returns the new race position for a particpant based on the previous position and the current turn.
-func moveRacer(previousPosition *RaceParticipantPosition, turn int) *RaceParticipantPosition {
+func moveRacer(previousPosition *RaceParticipantPosition, turn int, r *rand.Rand, profiles MovementProfiles) *RaceParticipantPosition {
 	// Already done with the race
 	if previousPosition.Position <= 0 {
 		newPosition := &RaceParticipantPosition{
@@ -372,7 +657,7 @@ func moveRacer(previousPosition *RaceParticipantPosition, turn int) *RacePartici
 		return newPosition
 	}
 
-	movement := previousPosition.RaceParticipant.Racer.calculateMovement(turn)
+	movement := previousPosition.RaceParticipant.Racer.calculateMovement(turn, r, profiles)
 	newPosition := &RaceParticipantPosition{
 		RaceParticipant: previousPosition.RaceParticipant,
 		Position:        previousPosition.Position - movement,
@@ -398,7 +683,7 @@ func raceStartChecks(guildID string) error {
 		return ErrRaceAlreadyInProgress
 	}
 
-	lastRaceTime := lastRaceTimes[guildID]
+	lastRaceTime := getLastRaceTime(guildID)
 	if time.Since(lastRaceTime) < config.WaitBetweenRaces {
 		timeSinceLastRace := time.Since(lastRaceTime)
 		timeUntilRaceCanStart := config.WaitBetweenRaces - timeSinceLastRace
@@ -441,7 +726,7 @@ func raceJoinChecks(race *Race, memberID string) error {
 
 // placeBet processes a bet placed by a member on the race
 func placeBet(race *Race, better *RaceBetter) error {
-	if err := better.Member.placeBet(race.config.BetAmount); err != nil {
+	if err := better.Member.placeBet(better.Amount); err != nil {
 		return err
 	}
 
@@ -452,8 +737,8 @@ func placeBet(race *Race, better *RaceBetter) error {
 	return nil
 }
 
-// raceBetChecks checks to see if a better is able to place a bet on the current race.
-func raceBetChecks(race *Race, memberID string) error {
+// raceBetChecks checks to see if a better is able to place a bet of the given type on the current race.
+func raceBetChecks(race *Race, memberID string, betType BetType) error {
 	race.mutex.Lock()
 	defer race.mutex.Unlock()
 
@@ -467,8 +752,12 @@ func raceBetChecks(race *Race, memberID string) error {
 		return ErrRaceHasStarted
 	}
 
+	if len(race.Racers) < betType.MinRacers() {
+		return ErrNotEnoughRacersForBet{betType, betType.MinRacers()}
+	}
+
 	for _, b := range race.Betters {
-		if b.Member.MemberID == memberID {
+		if b.Member.MemberID == memberID && b.BetType == betType {
 			return ErrAlreadyBetOnRace
 		}
 	}
@@ -476,11 +765,9 @@ func raceBetChecks(race *Race, memberID string) error {
 	return nil
 }
 
-// calculateWinngins calculates the earnings for the racers that wins, places and shows.
-func calculateWinnings(race *Race, lastLeg *RaceLeg) {
-	source := rand.NewPCG(rand.Uint64(), rand.Uint64())
-	r := rand.New(source)
-
+// calculateWinngins calculates the earnings for the racers that wins, places and shows. Ties and the
+// prize are decided using the given random number generator.
+func calculateWinnings(race *Race, lastLeg *RaceLeg, r *rand.Rand) {
 	// sort the participants in the final race leg
 	sort.Slice(lastLeg.ParticipantPositions, func(i, j int) bool {
 		if lastLeg.ParticipantPositions[i].Speed == lastLeg.ParticipantPositions[j].Speed {
@@ -492,6 +779,10 @@ func calculateWinnings(race *Race, lastLeg *RaceLeg) {
 	// Calculate the winners of the race and save in the results
 	prize := r.IntN(race.config.MaxPrizeAmount-race.config.MinPrizeAmount) + race.config.MinPrizeAmount
 	prize *= len(race.Racers)
+	if !race.TournamentID.IsZero() {
+		// Tournament races are run for points, and only the champion is paid a prize
+		prize = 0
+	}
 
 	// Assign the purse for the winner
 	if len(lastLeg.ParticipantPositions) > 0 {
@@ -524,13 +815,24 @@ func calculateWinnings(race *Race, lastLeg *RaceLeg) {
 	}
 
 	// Pay the winning bets
-	if race.RaceResult.Win != nil {
-		winner := race.RaceResult.Win.Participant
-		winningBet := race.config.BetAmount * len(race.Racers)
-		for _, better := range race.Betters {
-			if better.Racer == winner {
-				better.Winnings = winningBet
-			}
+	if race.RaceResult.Win == nil {
+		return
+	}
+	if race.config.PariMutuel {
+		payPariMutuelBets(race)
+		return
+	}
+	winner := race.RaceResult.Win.Participant
+	winningBet := race.config.BetAmount * len(race.Racers)
+	for _, better := range race.Betters {
+		if better.BetType == BetWin && better.Racer == winner {
+			better.Winnings = winningBet
 		}
 	}
+	payExoticBets(race)
+}
+
+// newRaceRand returns a random number generator for the given stream of random numbers in a race.
+func newRaceRand(seed int64, stream uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), stream))
 }
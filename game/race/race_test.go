@@ -5,7 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/joho/godotenv"
+	"github.com/rbrabson/goblin/bank"
 	"github.com/rbrabson/goblin/database/mongo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -52,7 +54,7 @@ func TestCalculateWinnings(t *testing.T) {
 	}
 
 	// Create a bet on racer1
-	raceBetter := getRaceBetter(better, racer1)
+	raceBetter := getRaceBetter(better, BetWin, []*RaceParticipant{racer1}, race.config.BetAmount)
 	race.addBetter(raceBetter)
 
 	// Create the final race leg with positions
@@ -86,7 +88,7 @@ func TestCalculateWinnings(t *testing.T) {
 	}
 
 	// Calculate winnings
-	calculateWinnings(race, raceLeg)
+	calculateWinnings(race, raceLeg, newRaceRand(race.Seed, simulationStream))
 
 	// Verify results
 	if race.RaceResult.Win == nil {
@@ -261,7 +263,7 @@ func TestGetRace(t *testing.T) {
 		t.Error("expected race to be found")
 	}
 
-	racers := getRaceAvatars("123", "clash")
+	racers := getRaceAvatars("123", "clash", newRaceRand(1, avatarStream))
 	if len(racers) < 2 {
 		for i, racer := range racers {
 			t.Error("racer: ", i, " ", racer)
@@ -327,3 +329,46 @@ func TestGetRace(t *testing.T) {
 		)
 	}
 }
+
+func TestRefundSavedRace(t *testing.T) {
+	bank.SetDB(db)
+	guildID := "refund-123"
+	defer func() {
+		deleteSavedRace(guildID)
+		if err := db.DeleteMany("bank_accounts", bson.M{"guild_id": guildID}); err != nil {
+			slog.Error("Error deleting bank accounts", slog.String("guildID", guildID), slog.Any("error", err))
+		}
+		if err := db.Delete(RaceConfigCollection, bson.M{"guild_id": guildID}); err != nil {
+			slog.Error("Error deleting race config", slog.String("guildID", guildID), slog.Any("error", err))
+		}
+	}()
+
+	race := newRace(guildID, GetConfig(guildID), RaceInProgress)
+	race.interaction = &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{ChannelID: "1"}}
+	race.Betters = []*RaceBetter{
+		{Member: &RaceMember{GuildID: guildID, MemberID: "1"}, BetType: BetWin, Amount: 100},
+		{Member: &RaceMember{GuildID: guildID, MemberID: "2"}, BetType: BetWin, Amount: 200},
+		{Member: &RaceMember{GuildID: guildID, MemberID: "1"}, BetType: BetPlace, Amount: 50},
+	}
+
+	// Save the race part way through the payouts, once the second bet has been paid
+	race.Betters[1].Settled = true
+	race.save()
+
+	balances := map[string]int{
+		"1": bank.GetAccount(guildID, "1").GetBalance(),
+		"2": bank.GetAccount(guildID, "2").GetBalance(),
+	}
+	for _, saved := range readSavedRaces() {
+		if saved.GuildID == guildID {
+			refundSavedRace(saved)
+		}
+	}
+
+	if got := bank.GetAccount(guildID, "1").GetBalance() - balances["1"]; got != 150 {
+		t.Errorf("expected the unsettled bets of 150 to be refunded, got %d", got)
+	}
+	if got := bank.GetAccount(guildID, "2").GetBalance() - balances["2"]; got != 0 {
+		t.Errorf("expected the settled bet not to be refunded, got %d", got)
+	}
+}
@@ -0,0 +1,313 @@
+package race
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	_ "image/png"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rbrabson/goblin/internal/pixfont"
+)
+
+const (
+	RenderText = "text" // Each leg of the race is shown by editing a text message
+	RenderGIF  = "gif"  // The whole race is shown as a single animated GIF once it finishes
+)
+
+const (
+	laneHeight   = 32
+	badgeSize    = 24
+	labelWidth   = 136
+	trackWidth   = 480
+	placeWidth   = 64
+	headerHeight = 24
+	frameMargin  = 8
+	textScale    = 2
+	maxNameChars = 10
+
+	legDelay       = 50  // Time each leg is shown, in hundredths of a second
+	finishDelay    = 500 // Time the finishing positions are shown before the animation loops
+	emojiURLFormat = "https://cdn.discordapp.com/emojis/%s.png"
+)
+
+var (
+	backgroundColor = color.RGBA{0x2b, 0x2d, 0x31, 0xff}
+	laneColors      = []color.RGBA{{0x3a, 0x5a, 0x32, 0xff}, {0x33, 0x50, 0x2c, 0xff}}
+	textColor       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	finishColor     = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	badgeColors     = []color.RGBA{
+		{0xe7, 0x4c, 0x3c, 0xff},
+		{0x34, 0x98, 0xdb, 0xff},
+		{0xf1, 0xc4, 0x0f, 0xff},
+		{0x9b, 0x59, 0xb6, 0xff},
+		{0xe6, 0x7e, 0x22, 0xff},
+		{0x1a, 0xbc, 0x9c, 0xff},
+		{0xec, 0x40, 0x7a, 0xff},
+		{0x95, 0xa5, 0xa6, 0xff},
+	}
+
+	avatarImages     = make(map[string]image.Image)
+	avatarFailures   = make(map[string]time.Time)
+	avatarImagesLock = sync.Mutex{}
+	avatarClient     = &http.Client{Timeout: 5 * time.Second}
+	avatarRetryDelay = 10 * time.Minute
+)
+
+// renderRaceGIF renders each leg of the race as a frame of an animated GIF. Racers are drawn using the
+// image for their avatar's custom emoji, or a badge with the first letter of their name if the image isn't
+// available. The time it takes to play the animation once is returned along with the GIF.
+func renderRaceGIF(race *Race) ([]byte, time.Duration, error) {
+	if len(race.RaceLegs) == 0 {
+		return nil, 0, ErrNoRacersFound
+	}
+
+	trackLength := max(race.RaceLegs[0].ParticipantPositions[0].Position, 1)
+	places := getFinishingPlaces(race)
+	badges := make(map[*RaceParticipant]image.Image, len(race.Racers))
+	for _, racer := range race.Racers {
+		badges[racer] = getAvatarImage(racer.Racer.Emoji)
+	}
+
+	pal := newRacePalette()
+	bounds := image.Rect(0, 0, labelWidth+trackWidth+placeWidth+2*frameMargin, headerHeight+laneHeight*len(race.Racers)+frameMargin)
+	animation := &gif.GIF{
+		Image: make([]*image.Paletted, 0, len(race.RaceLegs)),
+		Delay: make([]int, 0, len(race.RaceLegs)),
+	}
+	for n, leg := range race.RaceLegs {
+		frame := image.NewPaletted(bounds, pal)
+		drawRaceFrame(frame, race, leg, n, trackLength, places, badges)
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, legDelay)
+	}
+	animation.Delay[len(animation.Delay)-1] = finishDelay
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, animation); err != nil {
+		return nil, 0, err
+	}
+
+	duration := time.Duration(0)
+	for _, delay := range animation.Delay {
+		duration += time.Duration(delay) * 10 * time.Millisecond
+	}
+	return buf.Bytes(), duration, nil
+}
+
+// drawRaceFrame draws a single leg of the race. Each racer keeps the same lane in every frame.
+func drawRaceFrame(frame *image.Paletted, race *Race, leg *RaceLeg, legNum int, trackLength int, places map[*RaceParticipant]int, badges map[*RaceParticipant]image.Image) {
+	draw.Draw(frame, frame.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+	header := fmt.Sprintf("LEG %d/%d", legNum, len(race.RaceLegs)-1)
+	if legNum == 0 {
+		header = "READY"
+	}
+	pixfont.Draw(frame, frameMargin, (headerHeight-pixfont.Height(textScale))/2, header, textColor, textScale)
+
+	positions := make(map[*RaceParticipant]*RaceParticipantPosition, len(leg.ParticipantPositions))
+	for _, position := range leg.ParticipantPositions {
+		positions[position.RaceParticipant] = position
+	}
+
+	trackLeft := frameMargin + labelWidth
+	finishX := trackLeft + trackWidth
+	for lane, racer := range race.Racers {
+		top := headerHeight + lane*laneHeight
+		laneRect := image.Rect(trackLeft, top, finishX, top+laneHeight)
+		draw.Draw(frame, laneRect, image.NewUniform(laneColors[lane%len(laneColors)]), image.Point{}, draw.Src)
+		drawFinishLine(frame, finishX, top)
+
+		textTop := top + (laneHeight-pixfont.Height(textScale))/2
+		pixfont.Draw(frame, frameMargin, textTop, racerLabel(racer), textColor, textScale)
+
+		position := positions[racer]
+		if position == nil {
+			continue
+		}
+		remaining := max(position.Position, 0)
+		x := trackLeft + (trackLength-remaining)*(trackWidth-badgeSize)/trackLength
+		badgeTop := top + (laneHeight-badgeSize)/2
+		drawBadge(frame, image.Pt(x, badgeTop), racer, lane, badges[racer])
+
+		if remaining == 0 {
+			pixfont.Draw(frame, finishX+frameMargin+4, textTop, ordinal(places[racer]), textColor, textScale)
+		}
+	}
+}
+
+// drawFinishLine draws the checkered finish line for a lane.
+func drawFinishLine(frame *image.Paletted, x int, top int) {
+	const square = 4
+	for row := 0; row < laneHeight/square; row++ {
+		for col := range 2 {
+			c := color.Color(textColor)
+			if (row+col)%2 == 0 {
+				c = finishColor
+			}
+			rect := image.Rect(x+col*square, top+row*square, x+(col+1)*square, top+(row+1)*square)
+			draw.Draw(frame, rect, image.NewUniform(c), image.Point{}, draw.Src)
+		}
+	}
+}
+
+// drawBadge draws the racer at the given point. The avatar image is used if there is one, otherwise a
+// circle with the first letter of the racer's name is drawn.
+func drawBadge(frame *image.Paletted, at image.Point, racer *RaceParticipant, lane int, avatar image.Image) {
+	if avatar != nil {
+		drawScaled(frame, image.Rectangle{Min: at, Max: at.Add(image.Pt(badgeSize, badgeSize))}, avatar)
+		return
+	}
+
+	fill := badgeColors[lane%len(badgeColors)]
+	radius := badgeSize / 2
+	center := at.Add(image.Pt(radius, radius))
+	for y := -radius; y < radius; y++ {
+		for x := -radius; x < radius; x++ {
+			if x*x+y*y < radius*radius {
+				frame.Set(center.X+x, center.Y+y, fill)
+			}
+		}
+	}
+
+	letter := badgeLetter(racerName(racer))
+	pixfont.Draw(frame,
+		center.X-pixfont.Width(letter, textScale)/2,
+		center.Y-pixfont.Height(textScale)/2,
+		letter, textColor, textScale)
+}
+
+// drawScaled draws the image into the rectangle, scaling it using the nearest pixel. Transparent pixels
+// are skipped so the lane shows through.
+func drawScaled(frame *image.Paletted, rect image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	for y := range rect.Dy() {
+		for x := range rect.Dx() {
+			c := src.At(sb.Min.X+x*sb.Dx()/rect.Dx(), sb.Min.Y+y*sb.Dy()/rect.Dy())
+			if _, _, _, a := c.RGBA(); a < 0x8000 {
+				continue
+			}
+			frame.Set(rect.Min.X+x, rect.Min.Y+y, c)
+		}
+	}
+}
+
+// newRacePalette returns the colors used in the frames of the race. Web safe colors are included for
+// drawing avatar images.
+func newRacePalette() color.Palette {
+	pal := color.Palette{backgroundColor, textColor, finishColor}
+	for _, c := range laneColors {
+		pal = append(pal, c)
+	}
+	for _, c := range badgeColors {
+		pal = append(pal, c)
+	}
+	return append(pal, palette.WebSafe...)
+}
+
+// getFinishingPlaces returns the place, starting at one, in which each racer finished the race.
+func getFinishingPlaces(race *Race) map[*RaceParticipant]int {
+	lastLeg := race.RaceLegs[len(race.RaceLegs)-1]
+	places := make(map[*RaceParticipant]int, len(lastLeg.ParticipantPositions))
+	for n, position := range lastLeg.ParticipantPositions {
+		places[position.RaceParticipant] = n + 1
+	}
+	return places
+}
+
+// racerName returns the name of the member racing.
+func racerName(racer *RaceParticipant) string {
+	if racer.Member.guildMember != nil {
+		return racer.Member.guildMember.Name
+	}
+	return racer.Member.MemberID
+}
+
+// racerLabel returns the name of the racer shortened to fit beside the lane.
+func racerLabel(racer *RaceParticipant) string {
+	name := []rune(strings.TrimSpace(racerName(racer)))
+	if len(name) > maxNameChars {
+		name = name[:maxNameChars]
+	}
+	return string(name)
+}
+
+// badgeLetter returns the first character of the name that can be drawn, or '?' if there isn't one.
+func badgeLetter(name string) string {
+	for _, r := range name {
+		if r != ' ' && pixfont.HasGlyph(r) {
+			return strings.ToUpper(string(r))
+		}
+	}
+	return "?"
+}
+
+// getAvatarImage returns the image for a custom Discord emoji, such as "<:Minion:1346564146463768636>".
+// Images are downloaded once and cached. A failed download isn't tried again until the retry delay has
+// passed, so a missing image doesn't slow down every race. If the emoji isn't a custom emoji or the image
+// can't be downloaded, then a `nil` value is returned.
+func getAvatarImage(emoji string) image.Image {
+	id := customEmojiID(emoji)
+	if id == "" {
+		return nil
+	}
+
+	avatarImagesLock.Lock()
+	img, ok := avatarImages[id]
+	failedAt, failed := avatarFailures[id]
+	avatarImagesLock.Unlock()
+	if ok {
+		return img
+	}
+	if failed && time.Since(failedAt) < avatarRetryDelay {
+		return nil
+	}
+
+	// The image is downloaded without holding the lock, so a slow download doesn't hold up races in other guilds
+	img, err := downloadEmoji(id)
+
+	avatarImagesLock.Lock()
+	defer avatarImagesLock.Unlock()
+	if err != nil {
+		slog.Warn("failed to download the avatar image", slog.String("emoji", emoji), slog.Any("error", err))
+		avatarFailures[id] = time.Now()
+		return nil
+	}
+	avatarImages[id] = img
+	delete(avatarFailures, id)
+	return img
+}
+
+// downloadEmoji downloads the image for the custom emoji with the given ID.
+func downloadEmoji(id string) (image.Image, error) {
+	resp, err := avatarClient.Get(fmt.Sprintf(emojiURLFormat, id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	return img, err
+}
+
+// customEmojiID returns the ID of a custom Discord emoji, or an empty string if the emoji isn't a custom emoji.
+func customEmojiID(emoji string) string {
+	if !strings.HasPrefix(emoji, "<") || !strings.HasSuffix(emoji, ">") {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(emoji, "<>"), ":")
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[2]
+}
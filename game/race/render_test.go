@@ -0,0 +1,119 @@
+package race
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	imagepng "image/png"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rbrabson/goblin/guild"
+)
+
+func TestRenderRaceGIF(t *testing.T) {
+	race := &Race{
+		GuildID:    "123",
+		Seed:       42,
+		RaceResult: &RaceResult{},
+		config:     &Config{GuildID: "123", MinPrizeAmount: 100, MaxPrizeAmount: 200},
+		profiles:   newMovementProfiles(nil),
+	}
+	for _, name := range []string{"alice", "bob", "", "a very long member name"} {
+		race.Racers = append(race.Racers, &RaceParticipant{
+			Member: &RaceMember{GuildID: "123", MemberID: name, guildMember: &guild.Member{Name: name}},
+			Racer:  &Avatar{Emoji: "x", MovementSpeed: "steady"},
+		})
+	}
+	race.simulate(40)
+
+	data, duration, err := renderRaceGIF(race)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(animation.Image) != len(race.RaceLegs) {
+		t.Errorf("expected %d frames, got %d", len(race.RaceLegs), len(animation.Image))
+	}
+	if height := animation.Config.Height; height != headerHeight+laneHeight*len(race.Racers)+frameMargin {
+		t.Errorf("expected a lane for each racer, got a height of %d", height)
+	}
+	if duration <= 0 {
+		t.Errorf("expected the animation to have a duration, got %s", duration)
+	}
+}
+
+func TestCustomEmojiID(t *testing.T) {
+	tests := map[string]string{
+		"<:Minion:1346564146463768636>": "1346564146463768636",
+		"<a:Dance:1346564146463768637>": "1346564146463768637",
+		":checkered_flag:":              "",
+		"x":                             "",
+		"<:broken>":                     "",
+	}
+	for emoji, want := range tests {
+		if got := customEmojiID(emoji); got != want {
+			t.Errorf("expected %q for %s, got %q", want, emoji, got)
+		}
+	}
+}
+
+func TestBadgeLetter(t *testing.T) {
+	tests := map[string]string{"alice": "A", " bob": "B", "★zed": "Z", "": "?", "★★": "?"}
+	for name, want := range tests {
+		if got := badgeLetter(name); got != want {
+			t.Errorf("expected %s for %q, got %s", want, name, got)
+		}
+	}
+}
+
+// roundTripFunc lets a test replace the HTTP transport used to download avatar images.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestGetAvatarImageRetriesFailures(t *testing.T) {
+	var png bytes.Buffer
+	if err := imagepng.Encode(&png, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := 0
+	fail := true
+	client := avatarClient
+	avatarClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if fail {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Body: io.NopCloser(strings.NewReader(""))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(bytes.NewReader(png.Bytes()))}, nil
+	})}
+	defer func() { avatarClient = client }()
+
+	emoji := "<:Test:987654321>"
+	if img := getAvatarImage(emoji); img != nil {
+		t.Fatal("expected no image when the download fails")
+	}
+	if img := getAvatarImage(emoji); img != nil || requests != 1 {
+		t.Fatalf("expected a failed download not to be retried right away, got %d requests", requests)
+	}
+
+	fail = false
+	avatarImagesLock.Lock()
+	avatarFailures["987654321"] = time.Now().Add(-avatarRetryDelay)
+	avatarImagesLock.Unlock()
+	if img := getAvatarImage(emoji); img == nil {
+		t.Fatal("expected the image once the download succeeds")
+	}
+	if img := getAvatarImage(emoji); img == nil || requests != 2 {
+		t.Errorf("expected the image to be cached, got %d requests", requests)
+	}
+}
@@ -0,0 +1,253 @@
+package race
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RaceRecord is the saved outcome of a race, along with everything needed to replay it.
+type RaceRecord struct {
+	ID               bson.ObjectID      `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID          string             `json:"guild_id" bson:"guild_id"`
+	TournamentID     bson.ObjectID      `json:"tournament_id,omitempty" bson:"tournament_id,omitempty"`
+	Seed             int64              `json:"seed" bson:"seed"`
+	StartTime        time.Time          `json:"start_time" bson:"start_time"`
+	TrackLength      int                `json:"track_length" bson:"track_length"`
+	MinPrizeAmount   int                `json:"min_prize_amount" bson:"min_prize_amount"`
+	MaxPrizeAmount   int                `json:"max_prize_amount" bson:"max_prize_amount"`
+	MovementProfiles []*MovementProfile `json:"movement_profiles" bson:"movement_profiles"`
+	Racers           []*RacerRecord     `json:"racers" bson:"racers"`
+	Finishers        []*FinisherRecord  `json:"finishers" bson:"finishers"`
+	PariMutuel       bool               `json:"pari_mutuel" bson:"pari_mutuel"`
+	BetPool          int                `json:"bet_pool,omitempty" bson:"bet_pool,omitempty"`
+	HouseCut         int                `json:"house_cut,omitempty" bson:"house_cut,omitempty"`
+	Bets             []*BetRecord       `json:"bets" bson:"bets"`
+	BetSettings      *BetSettings       `json:"bet_settings,omitempty" bson:"bet_settings,omitempty"`
+}
+
+// BetSettings are the betting settings in effect for a saved race, which are needed to recalculate the
+// amount paid for each bet. Races saved before the settings were recorded don't have them, and the
+// bets on those races can't be verified.
+type BetSettings struct {
+	BetAmount         int                 `json:"bet_amount" bson:"bet_amount"`
+	HouseCut          float64             `json:"house_cut" bson:"house_cut"`
+	PayoutMultipliers map[BetType]float64 `json:"payout_multipliers,omitempty" bson:"payout_multipliers,omitempty"`
+}
+
+// RacerRecord is a racer in a saved race, in the order in which the racers joined the race.
+type RacerRecord struct {
+	MemberID      string `json:"member_id" bson:"member_id"`
+	Name          string `json:"name" bson:"name"`
+	Emoji         string `json:"emoji" bson:"emoji"`
+	MovementSpeed string `json:"movement_speed" bson:"movement_speed"`
+	Training      int    `json:"training,omitempty" bson:"training,omitempty"`
+	RacerName     string `json:"racer_name,omitempty" bson:"racer_name,omitempty"`
+}
+
+// FinisherRecord is the result for a racer in a saved race, in finishing order.
+type FinisherRecord struct {
+	MemberID string  `json:"member_id" bson:"member_id"`
+	RaceTime float64 `json:"race_time" bson:"race_time"`
+	Winnings int     `json:"winnings" bson:"winnings"`
+}
+
+// BetRecord is a bet placed on a saved race, along with the amount it paid.
+type BetRecord struct {
+	MemberID string   `json:"member_id" bson:"member_id"`
+	Name     string   `json:"name" bson:"name"`
+	BetType  BetType  `json:"bet_type" bson:"bet_type"`
+	Picks    []string `json:"picks" bson:"picks"`
+	Amount   int      `json:"amount" bson:"amount"`
+	Winnings int      `json:"winnings" bson:"winnings"`
+}
+
+// newRaceRecord returns the record of a race that has been run.
+func newRaceRecord(race *Race, trackLength int) *RaceRecord {
+	record := &RaceRecord{
+		ID:               bson.NewObjectID(),
+		GuildID:          race.GuildID,
+		TournamentID:     race.TournamentID,
+		Seed:             race.Seed,
+		StartTime:        race.RaceStartTime,
+		TrackLength:      trackLength,
+		MinPrizeAmount:   race.config.MinPrizeAmount,
+		MaxPrizeAmount:   race.config.MaxPrizeAmount,
+		MovementProfiles: race.profiles.used(race.Racers),
+		Racers:           make([]*RacerRecord, 0, len(race.Racers)),
+		Finishers:        getFinishers(race),
+		PariMutuel:       race.config.PariMutuel,
+		Bets:             make([]*BetRecord, 0, len(race.Betters)),
+		BetSettings: &BetSettings{
+			BetAmount:         race.config.BetAmount,
+			HouseCut:          race.config.HouseCut,
+			PayoutMultipliers: race.config.PayoutMultipliers,
+		},
+	}
+	for _, racer := range race.Racers {
+		racerRecord := &RacerRecord{
+			MemberID:      racer.Member.MemberID,
+			Name:          racer.Member.guildMember.Name,
+			Emoji:         racer.Racer.Emoji,
+			MovementSpeed: racer.Racer.MovementSpeed,
+			Training:      racer.Racer.Training,
+		}
+		if racer.Stable != nil {
+			racerRecord.RacerName = racer.Stable.Name
+		}
+		record.Racers = append(record.Racers, racerRecord)
+	}
+	for _, better := range race.Betters {
+		bet := &BetRecord{
+			MemberID: better.Member.MemberID,
+			Name:     better.Member.guildMember.Name,
+			BetType:  better.BetType,
+			Picks:    make([]string, 0, len(better.Picks)),
+			Amount:   better.Amount,
+			Winnings: better.Winnings,
+		}
+		for _, pick := range better.Picks {
+			bet.Picks = append(bet.Picks, pick.Member.MemberID)
+		}
+		record.Bets = append(record.Bets, bet)
+	}
+	if race.config.PariMutuel {
+		record.BetPool, record.HouseCut = betPoolTotals(race.Betters, race.config.HouseCut)
+	}
+
+	return record
+}
+
+// getFinishers returns the results for each racer in the race, in finishing order.
+func getFinishers(race *Race) []*FinisherRecord {
+	if len(race.RaceLegs) == 0 {
+		return nil
+	}
+
+	winnings := make(map[*RaceParticipant]int, 3)
+	for _, result := range []*RaceParticipantResult{race.RaceResult.Win, race.RaceResult.Place, race.RaceResult.Show} {
+		if result != nil {
+			winnings[result.Participant] = result.Winnings
+		}
+	}
+
+	lastLeg := race.RaceLegs[len(race.RaceLegs)-1]
+	finishers := make([]*FinisherRecord, 0, len(lastLeg.ParticipantPositions))
+	for _, position := range lastLeg.ParticipantPositions {
+		finishers = append(finishers, &FinisherRecord{
+			MemberID: position.RaceParticipant.Member.MemberID,
+			RaceTime: position.Speed,
+			Winnings: winnings[position.RaceParticipant],
+		})
+	}
+
+	return finishers
+}
+
+// replay runs the saved race again using the same racers, bets and seed. The replayed results for each
+// racer, in finishing order, and the amount paid for each bet are returned in a new record.
+func (record *RaceRecord) replay() *RaceRecord {
+	race := &Race{
+		GuildID:       record.GuildID,
+		TournamentID:  record.TournamentID,
+		Seed:          record.Seed,
+		Racers:        make([]*RaceParticipant, 0, len(record.Racers)),
+		Betters:       make([]*RaceBetter, 0, len(record.Bets)),
+		RaceResult:    &RaceResult{},
+		RaceStartTime: record.StartTime,
+		state:         RaceInProgress,
+		config: &Config{
+			GuildID:        record.GuildID,
+			MinPrizeAmount: record.MinPrizeAmount,
+			MaxPrizeAmount: record.MaxPrizeAmount,
+			PariMutuel:     record.PariMutuel,
+		},
+		profiles: newMovementProfiles(record.MovementProfiles),
+	}
+	if record.BetSettings != nil {
+		race.config.BetAmount = record.BetSettings.BetAmount
+		race.config.HouseCut = record.BetSettings.HouseCut
+		race.config.PayoutMultipliers = record.BetSettings.PayoutMultipliers
+	}
+	participants := make(map[string]*RaceParticipant, len(record.Racers))
+	for _, racer := range record.Racers {
+		participant := &RaceParticipant{
+			Member: &RaceMember{GuildID: record.GuildID, MemberID: racer.MemberID},
+			Racer:  &Avatar{GuildID: record.GuildID, Emoji: racer.Emoji, MovementSpeed: racer.MovementSpeed, Training: racer.Training},
+		}
+		participants[racer.MemberID] = participant
+		race.Racers = append(race.Racers, participant)
+	}
+	for _, bet := range record.Bets {
+		picks := make([]*RaceParticipant, 0, len(bet.Picks))
+		for _, pick := range bet.Picks {
+			picks = append(picks, participants[pick])
+		}
+		better := &RaceBetter{
+			Member:  &RaceMember{GuildID: record.GuildID, MemberID: bet.MemberID},
+			BetType: bet.BetType,
+			Picks:   picks,
+			Amount:  bet.Amount,
+		}
+		if len(picks) > 0 {
+			better.Racer = picks[0]
+		}
+		race.Betters = append(race.Betters, better)
+	}
+
+	race.simulate(record.TrackLength)
+
+	replayed := &RaceRecord{
+		Finishers: getFinishers(race),
+		Bets:      make([]*BetRecord, 0, len(race.Betters)),
+	}
+	for i, better := range race.Betters {
+		bet := *record.Bets[i]
+		bet.Winnings = better.Winnings
+		replayed.Bets = append(replayed.Bets, &bet)
+	}
+
+	return replayed
+}
+
+// verify checks that the replayed results, and the amount paid for each bet, match the results saved
+// for the race. If they differ, an error describing the first difference is returned. The bets are
+// only checked if the betting settings were saved with the race.
+func (record *RaceRecord) verify(replayed *RaceRecord) error {
+	if len(replayed.Finishers) != len(record.Finishers) {
+		return fmt.Errorf("replay has %d finishers, but the race had %d", len(replayed.Finishers), len(record.Finishers))
+	}
+	for i, want := range record.Finishers {
+		got := replayed.Finishers[i]
+		if got.MemberID != want.MemberID || got.RaceTime != want.RaceTime || got.Winnings != want.Winnings {
+			return fmt.Errorf("position %d differs: replay has <@%s> at %.2fs winning %d, but the race had <@%s> at %.2fs winning %d",
+				i+1, got.MemberID, got.RaceTime, got.Winnings, want.MemberID, want.RaceTime, want.Winnings)
+		}
+	}
+
+	if record.BetSettings == nil {
+		return nil
+	}
+	if len(replayed.Bets) != len(record.Bets) {
+		return fmt.Errorf("replay has %d bets, but the race had %d", len(replayed.Bets), len(record.Bets))
+	}
+	for i, want := range record.Bets {
+		got := replayed.Bets[i]
+		if got.Winnings != want.Winnings {
+			return fmt.Errorf("the %s bet by <@%s> differs: replay paid %d, but the race paid %d",
+				want.BetType, want.MemberID, got.Winnings, want.Winnings)
+		}
+	}
+	return nil
+}
+
+// getRacerRecord returns the racer with the given member ID, or nil if the member was not in the race.
+func (record *RaceRecord) getRacerRecord(memberID string) *RacerRecord {
+	for _, racer := range record.Racers {
+		if racer.MemberID == memberID {
+			return racer
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package race
+
+import (
+	"testing"
+)
+
+func TestReplayRace(t *testing.T) {
+	record := &RaceRecord{
+		GuildID:        "123",
+		Seed:           42,
+		TrackLength:    60,
+		MinPrizeAmount: 100,
+		MaxPrizeAmount: 200,
+		MovementProfiles: []*MovementProfile{
+			{Name: "veryfast", Rules: []*MovementRule{{Distribution: []int{0, 2, 4, 6, 8, 10, 12, 14}}}},
+			{Name: "fast", Rules: []*MovementRule{{Distribution: []int{0, 3, 6, 9, 12}}}},
+			{Name: "abberant", Rules: []*MovementRule{{Distribution: []int{0, 3, 6}, BurstChance: 30, BurstMovement: 15}}},
+		},
+		Racers: []*RacerRecord{
+			{MemberID: "1", Emoji: "a", MovementSpeed: "veryfast"},
+			{MemberID: "2", Emoji: "b", MovementSpeed: "fast"},
+			{MemberID: "3", Emoji: "c", MovementSpeed: "abberant"},
+			{MemberID: "4", Emoji: "d", MovementSpeed: "steady"},
+		},
+		Bets: []*BetRecord{
+			{MemberID: "5", BetType: BetWin, Picks: []string{"1"}, Amount: 50},
+			{MemberID: "6", BetType: BetWin, Picks: []string{"2"}, Amount: 50},
+			{MemberID: "7", BetType: BetShow, Picks: []string{"1"}, Amount: 50},
+		},
+		BetSettings: &BetSettings{
+			BetAmount:         50,
+			PayoutMultipliers: map[BetType]float64{BetShow: 0.5},
+		},
+	}
+
+	replayed := record.replay()
+	record.Finishers = replayed.Finishers
+	record.Bets = replayed.Bets
+	if len(record.Finishers) != len(record.Racers) {
+		t.Fatalf("expected %d finishers, got %d", len(record.Racers), len(record.Finishers))
+	}
+	if err := record.verify(record.replay()); err != nil {
+		t.Errorf("expected replay to match the race, got %v", err)
+	}
+
+	paid := 0
+	for _, bet := range record.Bets {
+		paid += bet.Winnings
+	}
+	if paid == 0 {
+		t.Fatal("expected at least one bet to be paid")
+	}
+	winner := record.Finishers[0].MemberID
+	for _, bet := range record.Bets {
+		if bet.BetType == BetWin && bet.Picks[0] == winner && bet.Winnings != 50*len(record.Racers) {
+			t.Errorf("expected the win bet on the winner to pay %d, got %d", 50*len(record.Racers), bet.Winnings)
+		}
+	}
+
+	overpaid := *record
+	overpaid.Bets = make([]*BetRecord, 0, len(record.Bets))
+	for _, bet := range record.Bets {
+		b := *bet
+		b.Winnings += 10
+		overpaid.Bets = append(overpaid.Bets, &b)
+	}
+	if err := overpaid.verify(overpaid.replay()); err == nil {
+		t.Error("expected replay to differ from the overpaid bets")
+	}
+
+	unverified := overpaid
+	unverified.BetSettings = nil
+	if err := unverified.verify(unverified.replay()); err != nil {
+		t.Errorf("expected bets to be skipped when the betting settings weren't saved, got %v", err)
+	}
+
+	tampered := *record
+	tampered.Finishers = append([]*FinisherRecord(nil), record.Finishers...)
+	tampered.Finishers[0], tampered.Finishers[1] = tampered.Finishers[1], tampered.Finishers[0]
+	if err := tampered.verify(tampered.replay()); err == nil {
+		t.Error("expected replay to differ from the tampered results")
+	}
+}
+
+func TestRaceRandStreams(t *testing.T) {
+	a := newRaceRand(7, simulationStream)
+	b := newRaceRand(7, simulationStream)
+	for range 10 {
+		if a.Uint64() != b.Uint64() {
+			t.Fatal("expected the same seed and stream to produce the same numbers")
+		}
+	}
+
+	if newRaceRand(7, avatarStream).Uint64() == newRaceRand(7, simulationStream).Uint64() {
+		t.Error("expected different streams to produce different numbers")
+	}
+}
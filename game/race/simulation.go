@@ -0,0 +1,193 @@
+package race
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// SimulationResults are the results of simulating races for a theme. They are used to tune the prizes
+// and bet payouts before changing the configuration used by a guild.
+type SimulationResults struct {
+	NumRaces    int                // Number of races that were simulated
+	NumRacers   int                // Number of racers in each race
+	TrackLength int                // Length of the track
+	TotalPrize  int                // Total prize paid to the racers that finished in the top three
+	Avatars     []*SimulationStats // Results for each avatar
+	Speeds      []*SimulationStats // Results for each movement speed
+}
+
+// SimulationStats are the finishing positions for an avatar or movement speed over the simulated races.
+type SimulationStats struct {
+	Name    string // Name of the avatar or movement speed
+	Entries int    // Number of racers using the avatar or movement speed
+	Wins    int    // Number of times the racer finished first
+	Places  int    // Number of times the racer finished first or second
+	Shows   int    // Number of times the racer finished in the top three
+}
+
+// SimulateRaces simulates the given number of races for the configuration's theme, using the theme's
+// avatars and movement profiles and the same race logic as the game. Each race is run with randomly
+// chosen avatars. The seed makes the simulation repeatable.
+func SimulateRaces(config *Config, numRaces int, numRacers int, seed int64) (*SimulationResults, error) {
+	if numRaces <= 0 || numRacers < 2 {
+		return nil, errors.New("at least one race with two or more racers must be simulated")
+	}
+	if config.MaxPrizeAmount <= config.MinPrizeAmount {
+		return nil, fmt.Errorf("the maximum prize (%d) must be greater than the minimum prize (%d)", config.MaxPrizeAmount, config.MinPrizeAmount)
+	}
+	file, err := readAvatarFile(config.Theme)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Avatars) == 0 {
+		return nil, ErrNoRacersFound
+	}
+
+	results := &SimulationResults{
+		NumRaces:    numRaces,
+		NumRacers:   numRacers,
+		TrackLength: len([]rune(config.Track)),
+		Avatars:     make([]*SimulationStats, 0, len(file.Avatars)),
+		Speeds:      make([]*SimulationStats, 0, len(file.MovementProfiles)),
+	}
+	avatarStats := make(map[*Avatar]*SimulationStats, len(file.Avatars))
+	speedStats := make(map[string]*SimulationStats, len(file.MovementProfiles))
+	for _, avatar := range file.Avatars {
+		stats := &SimulationStats{Name: avatar.Emoji}
+		results.Avatars = append(results.Avatars, stats)
+		avatarStats[avatar] = stats
+		if speedStats[avatar.MovementSpeed] == nil {
+			speedStats[avatar.MovementSpeed] = &SimulationStats{Name: avatar.MovementSpeed}
+			results.Speeds = append(results.Speeds, speedStats[avatar.MovementSpeed])
+		}
+	}
+
+	profiles := config.movementProfiles(newMovementProfiles(file.MovementProfiles), time.Now())
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	for range numRaces {
+		race := &Race{
+			GuildID:    config.GuildID,
+			Seed:       rng.Int64(),
+			Racers:     make([]*RaceParticipant, 0, numRacers),
+			RaceResult: &RaceResult{},
+			config:     config,
+			profiles:   profiles,
+		}
+		for i, avatar := range pickAvatars(file.Avatars, numRacers, rng) {
+			race.Racers = append(race.Racers, &RaceParticipant{
+				Member: &RaceMember{GuildID: config.GuildID, MemberID: fmt.Sprintf("%d", i)},
+				Racer:  avatar,
+			})
+			avatarStats[avatar].Entries++
+			speedStats[avatar.MovementSpeed].Entries++
+		}
+
+		race.simulate(results.TrackLength)
+
+		for position, result := range []*RaceParticipantResult{race.RaceResult.Win, race.RaceResult.Place, race.RaceResult.Show} {
+			if result == nil {
+				continue
+			}
+			results.TotalPrize += result.Winnings
+			avatar := result.Participant.Racer
+			for _, stats := range []*SimulationStats{avatarStats[avatar], speedStats[avatar.MovementSpeed]} {
+				stats.recordFinish(position)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// pickAvatars returns the avatars for a simulated race. Avatars are only reused if there are more racers
+// than avatars, which is the same as when a race is run.
+func pickAvatars(avatars []*Avatar, numRacers int, r *rand.Rand) []*Avatar {
+	picked := make([]*Avatar, 0, numRacers)
+	for len(picked) < numRacers {
+		perm := r.Perm(len(avatars))
+		for _, index := range perm[:min(len(perm), numRacers-len(picked))] {
+			picked = append(picked, avatars[index])
+		}
+	}
+	return picked
+}
+
+// recordFinish records a finish in the given position, where zero is first place.
+func (stats *SimulationStats) recordFinish(position int) {
+	if position == 0 {
+		stats.Wins++
+	}
+	if position <= 1 {
+		stats.Places++
+	}
+	stats.Shows++
+}
+
+// Probability returns the probability, as a percentage, that a racer wins the bet type. Only win, place
+// and show bets are supported, as exacta and trifecta bets depend on more than one racer.
+func (stats *SimulationStats) Probability(betType BetType) float64 {
+	if stats.Entries == 0 {
+		return 0
+	}
+	var finishes int
+	switch betType {
+	case BetWin:
+		finishes = stats.Wins
+	case BetPlace:
+		finishes = stats.Places
+	case BetShow:
+		finishes = stats.Shows
+	}
+	return float64(finishes) / float64(stats.Entries) * 100
+}
+
+// Return returns the amount paid back per credit bet, for bets of the given type on racers using the
+// avatar or movement speed. A return less than one means the house wins over time.
+func (stats *SimulationStats) Return(betType BetType, config *Config, numRacers int) float64 {
+	odds := float64(numRacers)
+	if betType != BetWin {
+		odds = betType.Outcomes(numRacers) * config.PayoutMultiplier(betType)
+	}
+	return stats.Probability(betType) / 100 * odds
+}
+
+// AveragePrize returns the average prize paid to the racers in a race.
+func (results *SimulationResults) AveragePrize() float64 {
+	return float64(results.TotalPrize) / float64(results.NumRaces)
+}
+
+// HouseEdge returns the house edge, as a percentage, for a member who bets on a random racer. Exacta and
+// trifecta bets cover every finishing order equally, so their edge depends only on the payout multiplier.
+// Pari-mutuel bets return the pool less the house cut.
+func (results *SimulationResults) HouseEdge(betType BetType, config *Config) float64 {
+	switch {
+	case config.PariMutuel:
+		return config.HouseCut
+	case betType == BetExacta || betType == BetTrifecta:
+		return (1 - config.PayoutMultiplier(betType)) * 100
+	}
+
+	totalReturn := 0.0
+	entries := 0
+	for _, stats := range results.Speeds {
+		totalReturn += stats.Return(betType, config, results.NumRacers) * float64(stats.Entries)
+		entries += stats.Entries
+	}
+	if entries == 0 {
+		return 0
+	}
+	return (1 - totalReturn/float64(entries)) * 100
+}
+
+// BestReturn returns the movement speed that pays the most per credit bet for the bet type, along
+// with the return. A return greater than one means members can beat the house by always betting on it.
+func (results *SimulationResults) BestReturn(betType BetType, config *Config) (string, float64) {
+	best := slices.MaxFunc(results.Speeds, func(a, b *SimulationStats) int {
+		return cmp.Compare(a.Return(betType, config, results.NumRacers), b.Return(betType, config, results.NumRacers))
+	})
+	return best.Name, best.Return(betType, config, results.NumRacers)
+}
@@ -0,0 +1,48 @@
+package race
+
+import (
+	"testing"
+
+	"github.com/rbrabson/goblin/discord"
+)
+
+func TestSimulateRaces(t *testing.T) {
+	configDir := discord.ConfigDir
+	discord.ConfigDir = "../../config"
+	defer func() { discord.ConfigDir = configDir }()
+
+	config, err := ReadConfigFile("clash")
+	if err != nil {
+		t.Skip("race config is not available")
+	}
+	config.Theme = "clash"
+
+	results, err := SimulateRaces(config, 500, 6, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wins, places, shows := 0, 0, 0
+	for _, stats := range results.Speeds {
+		wins += stats.Wins
+		places += stats.Places
+		shows += stats.Shows
+	}
+	if wins != 500 || places != 1000 || shows != 1500 {
+		t.Errorf("expected 500 wins, 1000 places and 1500 shows, got %d, %d and %d", wins, places, shows)
+	}
+	if prize := results.AveragePrize(); prize < float64(config.MinPrizeAmount*6) {
+		t.Errorf("expected an average prize of at least %d, got %.0f", config.MinPrizeAmount*6, prize)
+	}
+
+	again, _ := SimulateRaces(config, 500, 6, 1)
+	for i, stats := range results.Avatars {
+		if *stats != *again.Avatars[i] {
+			t.Fatalf("expected the same seed to produce the same results for %s", stats.Name)
+		}
+	}
+
+	if _, err := SimulateRaces(config, 10, 1, 1); err == nil {
+		t.Error("expected an error for a race with a single racer")
+	}
+}
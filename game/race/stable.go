@@ -0,0 +1,538 @@
+package race
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/internal/unicode"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	RacerAssignmentRandom = "random" // Racers are assigned a random avatar for each race
+	RacerAssignmentOwned  = "owned"  // Members race with the active racer in their stable, if they have one
+
+	trainingChancePerLevel = 5  // Chance, as a percentage, per training level of moving an extra space on a turn
+	maxTrainingLevel       = 10 // Most a racer may be trained, regardless of the guild's configuration
+	maxRacerNameLength     = 24
+
+	defaultRacerPrice    = 5000
+	defaultTrainingCost  = 1000
+	defaultMaxTraining   = 5
+	defaultMaxStableSize = 3
+)
+
+var (
+	stableLocks = make(map[string]*sync.Mutex)
+	stableLock  = sync.Mutex{}
+)
+
+// StableRacer is a racer owned by a member. The member races with their active racer when the guild uses
+// owned racers, and the racer keeps its training and record from race to race.
+type StableRacer struct {
+	ID            bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID       string        `json:"guild_id" bson:"guild_id"`
+	MemberID      string        `json:"member_id" bson:"member_id"`
+	Theme         string        `json:"theme" bson:"theme"`
+	Name          string        `json:"name" bson:"name"`
+	Emoji         string        `json:"emoji" bson:"emoji"`
+	MovementSpeed string        `json:"movement_speed" bson:"movement_speed"`
+	Training      int           `json:"training" bson:"training"`
+	Active        bool          `json:"active" bson:"active"`
+	Races         int           `json:"races" bson:"races"`
+	Wins          int           `json:"wins" bson:"wins"`
+	Places        int           `json:"places" bson:"places"`
+	Shows         int           `json:"shows" bson:"shows"`
+	PurchasedAt   time.Time     `json:"purchased_at" bson:"purchased_at"`
+}
+
+// getStable returns the racers owned by the member for the theme, in the order they were bought.
+func getStable(guildID string, memberID string, themeName string) ([]*StableRacer, error) {
+	racers, err := readStableRacers(guildID, memberID, themeName)
+	if err != nil {
+		return nil, ErrStableUnavailable
+	}
+	return racers, nil
+}
+
+// getStableLock returns the lock for the member's stable. If one doesn't exist, it creates a new one.
+// Purchases, training and changes to the active racer hold the lock, so two commands run at the same
+// time can't both pass the checks made before paying.
+func getStableLock(guildID string, memberID string) *sync.Mutex {
+	stableLock.Lock()
+	defer stableLock.Unlock()
+
+	key := guildID + ":" + memberID
+	mutex, ok := stableLocks[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		stableLocks[key] = mutex
+	}
+	return mutex
+}
+
+// getActiveRacer returns the racer the member races with, or nil if the member doesn't own a racer or
+// their stable can't be read.
+func getActiveRacer(guildID string, memberID string, themeName string) *StableRacer {
+	stable, _ := getStable(guildID, memberID, themeName)
+	for _, racer := range stable {
+		if racer.Active {
+			return racer
+		}
+	}
+	return nil
+}
+
+// BuyRacer buys a racer using one of the theme's avatars and adds it to the member's stable. The first
+// racer bought becomes the member's active racer.
+func BuyRacer(guildID string, memberID string, avatarName string, name string) (*StableRacer, error) {
+	config := GetConfig(guildID)
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxRacerNameLength {
+		return nil, ErrInvalidRacerName{MaxLength: maxRacerNameLength}
+	}
+
+	mutex := getStableLock(guildID, memberID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stable, err := getStable(guildID, memberID, config.Theme)
+	if err != nil {
+		return nil, err
+	}
+	if len(stable) >= config.maxStableSize() {
+		return nil, ErrStableFull{MaxStableSize: config.maxStableSize()}
+	}
+	if findStableRacer(stable, name) != nil {
+		return nil, ErrRacerNameTaken
+	}
+	avatar := findAvatar(getAvatars(guildID, config.Theme), avatarName)
+	if avatar == nil {
+		return nil, ErrAvatarNotFound
+	}
+
+	account := bank.GetAccount(guildID, memberID)
+	if err := account.Withdraw(config.racerPrice()); err != nil {
+		return nil, err
+	}
+
+	racer := &StableRacer{
+		GuildID:       guildID,
+		MemberID:      memberID,
+		Theme:         config.Theme,
+		Name:          name,
+		Emoji:         avatar.Emoji,
+		MovementSpeed: avatar.MovementSpeed,
+		Active:        len(stable) == 0,
+		PurchasedAt:   time.Now(),
+	}
+	writeStableRacer(racer)
+
+	slog.Info("bought racer",
+		slog.String("guildID", guildID),
+		slog.String("memberID", memberID),
+		slog.String("name", racer.Name),
+		slog.String("avatar", avatarName),
+		slog.Int("price", config.racerPrice()),
+	)
+
+	return racer, nil
+}
+
+// TrainRacer trains one of the member's racers by one level, increasing the chance it moves an extra
+// space on each turn. Each level costs more than the last. The amount paid is returned.
+func TrainRacer(guildID string, memberID string, name string) (*StableRacer, int, error) {
+	config := GetConfig(guildID)
+	mutex := getStableLock(guildID, memberID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stable, err := getStable(guildID, memberID, config.Theme)
+	if err != nil {
+		return nil, 0, err
+	}
+	racer := findStableRacer(stable, name)
+	if racer == nil {
+		return nil, 0, ErrRacerNotFound
+	}
+	if racer.Training >= config.maxTraining() {
+		return nil, 0, ErrFullyTrained
+	}
+
+	cost := config.trainingCost(racer.Training + 1)
+	account := bank.GetAccount(guildID, memberID)
+	if err := account.Withdraw(cost); err != nil {
+		return nil, 0, err
+	}
+	racer.Training++
+	writeStableRacer(racer)
+
+	slog.Info("trained racer",
+		slog.String("guildID", guildID),
+		slog.String("memberID", memberID),
+		slog.String("name", racer.Name),
+		slog.Int("training", racer.Training),
+		slog.Int("cost", cost),
+	)
+
+	return racer, cost, nil
+}
+
+// UseRacer makes the racer the one the member races with.
+func UseRacer(guildID string, memberID string, name string) (*StableRacer, error) {
+	config := GetConfig(guildID)
+	mutex := getStableLock(guildID, memberID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	stable, err := getStable(guildID, memberID, config.Theme)
+	if err != nil {
+		return nil, err
+	}
+	racer := findStableRacer(stable, name)
+	if racer == nil {
+		return nil, ErrRacerNotFound
+	}
+
+	for _, r := range stable {
+		if r.Active != (r == racer) {
+			r.Active = r == racer
+			writeStableRacer(r)
+		}
+	}
+
+	return racer, nil
+}
+
+// findStableRacer returns the racer in the stable with the given name, ignoring case.
+func findStableRacer(stable []*StableRacer, name string) *StableRacer {
+	name = strings.TrimSpace(name)
+	for _, racer := range stable {
+		if strings.EqualFold(racer.Name, name) {
+			return racer
+		}
+	}
+	return nil
+}
+
+// findAvatar returns the avatar with the given name, such as "Minion" for "<:Minion:1346564146463768636>",
+// ignoring case.
+func findAvatar(avatars []*Avatar, name string) *Avatar {
+	name = strings.TrimSpace(name)
+	for _, avatar := range avatars {
+		if strings.EqualFold(avatarName(avatar.Emoji), name) || avatar.Emoji == name {
+			return avatar
+		}
+	}
+	return nil
+}
+
+// avatarName returns the name of a custom Discord emoji, such as "Minion" for "<:Minion:1346564146463768636>".
+// Other emoji are returned unchanged.
+func avatarName(emoji string) string {
+	parts := strings.Split(strings.Trim(emoji, "<>"), ":")
+	if strings.HasPrefix(emoji, "<") && len(parts) == 3 {
+		return parts[1]
+	}
+	return emoji
+}
+
+// avatar returns the avatar used when the racer is in a race.
+func (racer *StableRacer) avatar() *Avatar {
+	return &Avatar{
+		GuildID:       racer.GuildID,
+		Theme:         racer.Theme,
+		Emoji:         racer.Emoji,
+		MovementSpeed: racer.MovementSpeed,
+		Training:      racer.Training,
+	}
+}
+
+// recordFinish adds a race to the racer's record, where a place of one is a win.
+func (racer *StableRacer) recordFinish(place int) {
+	racer.Races++
+	switch place {
+	case 1:
+		racer.Wins++
+	case 2:
+		racer.Places++
+	case 3:
+		racer.Shows++
+	}
+}
+
+// Losses returns the number of races in which the racer didn't finish in the top three.
+func (racer *StableRacer) Losses() int {
+	return racer.Races - racer.Wins - racer.Places - racer.Shows
+}
+
+// trainingBonus returns the extra space moved on a turn by a racer with the given training. Racers that
+// don't move on a turn aren't helped by training, and untrained racers don't draw a random number.
+func trainingBonus(training int, movement int, r *rand.Rand) int {
+	if training <= 0 || movement <= 0 {
+		return 0
+	}
+	if r.IntN(100) < min(training, maxTrainingLevel)*trainingChancePerLevel {
+		return 1
+	}
+	return 0
+}
+
+// getParticipantAvatar returns the avatar for a member joining the race. When the guild uses owned racers
+// and the member has an active racer, that racer is used. Otherwise, a random avatar is assigned.
+func getParticipantAvatar(race *Race, memberID string) (*Avatar, *StableRacer) {
+	if race.config.RacerAssignment == RacerAssignmentOwned {
+		if racer := getActiveRacer(race.GuildID, memberID, race.config.Theme); racer != nil {
+			return racer.avatar(), racer
+		}
+	}
+	return getRaceAvatar(race), nil
+}
+
+// updateStableRecords adds the race to the record of each owned racer that took part in it.
+func updateStableRecords(race *Race) {
+	if len(race.RaceLegs) == 0 {
+		return
+	}
+	places := getFinishingPlaces(race)
+	for _, participant := range race.Racers {
+		if participant.Stable == nil {
+			continue
+		}
+		participant.Stable.recordFinish(places[participant])
+		writeStableRacer(participant.Stable)
+	}
+}
+
+// racerPrice returns the cost of buying a racer.
+func (config *Config) racerPrice() int {
+	if config.RacerPrice > 0 {
+		return config.RacerPrice
+	}
+	return defaultRacerPrice
+}
+
+// trainingCost returns the cost of training a racer to the given level. Each level costs more than the last.
+func (config *Config) trainingCost(level int) int {
+	cost := config.TrainingCost
+	if cost <= 0 {
+		cost = defaultTrainingCost
+	}
+	return cost * level
+}
+
+// maxTraining returns the highest level to which a racer may be trained.
+func (config *Config) maxTraining() int {
+	if config.MaxTraining > 0 {
+		return min(config.MaxTraining, maxTrainingLevel)
+	}
+	return defaultMaxTraining
+}
+
+// maxStableSize returns the most racers a member may own.
+func (config *Config) maxStableSize() int {
+	if config.MaxStableSize > 0 {
+		return config.MaxStableSize
+	}
+	return defaultMaxStableSize
+}
+
+// sortAvatarsByName returns the avatars sorted by name, for listing the racers that may be bought.
+func sortAvatarsByName(avatars []*Avatar) []*Avatar {
+	sorted := slices.Clone(avatars)
+	slices.SortFunc(sorted, func(a, b *Avatar) int {
+		return strings.Compare(strings.ToLower(avatarName(a.Emoji)), strings.ToLower(avatarName(b.Emoji)))
+	})
+	return sorted
+}
+
+// raceStable routes the `race stable` commands to the proper handlers.
+func raceStable(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "list":
+		listStable(s, i)
+	case "shop":
+		stableShop(s, i)
+	case "buy":
+		buyRacer(s, i)
+	case "train":
+		trainRacer(s, i)
+	case "use":
+		useRacer(s, i)
+	}
+}
+
+// listStable shows the racers owned by the member.
+func listStable(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	config := GetConfig(i.GuildID)
+	stable, err := getStable(i.GuildID, i.Member.User.ID, config.Theme)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to show your stable: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if len(stable) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent("You don't own any racers. Use `/race stable shop` to see the racers you can buy.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	fields := make([]*discordgo.MessageEmbedField, 0, len(stable))
+	for _, racer := range stable {
+		name := p.Sprintf("%s %s", racer.Emoji, racer.Name)
+		if racer.Active {
+			name += " (active)"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   name,
+			Value:  formatStableRacer(racer, config),
+			Inline: false,
+		})
+	}
+	description := p.Sprintf("You own %d of %d racers.", len(stable), config.maxStableSize())
+	if config.RacerAssignment != RacerAssignmentOwned {
+		description += " Racers are currently assigned at random, so your racers won't race until owned racers are enabled."
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Your Stable",
+		Description: description,
+		Fields:      fields,
+	}
+
+	disgomsg.NewResponse(disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed})).SendEphemeral(s, i.Interaction)
+}
+
+// stableShop shows the racers that may be bought.
+func stableShop(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	config := GetConfig(i.GuildID)
+	avatars := sortAvatarsByName(getAvatars(i.GuildID, config.Theme))
+	if len(avatars) == 0 {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrNoRacersFound.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	lines := make([]string, 0, len(avatars))
+	for _, avatar := range avatars {
+		lines = append(lines, p.Sprintf("%s %s (%s)", avatar.Emoji, avatarName(avatar.Emoji), avatar.MovementSpeed))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Racers for Sale",
+		Description: p.Sprintf("Every racer costs %d. Buy one with `/race stable buy`.", config.racerPrice()),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Racers",
+				Value:  unicode.Truncate(strings.Join(lines, "\n"), maxFieldLength),
+				Inline: false,
+			},
+		},
+	}
+
+	disgomsg.NewResponse(disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed})).SendEphemeral(s, i.Interaction)
+}
+
+// buyRacer buys a racer for the member.
+func buyRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	var avatar, name string
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "racer":
+			avatar = option.StringValue()
+		case "name":
+			name = option.StringValue()
+		}
+	}
+
+	racer, err := BuyRacer(i.GuildID, i.Member.User.ID, avatar, name)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to buy the racer: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	content := p.Sprintf("You bought %s %s for %d.", racer.Emoji, racer.Name, GetConfig(i.GuildID).racerPrice())
+	if racer.Active {
+		content += " It's your active racer."
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(content)).SendEphemeral(s, i.Interaction)
+}
+
+// trainRacer trains one of the member's racers.
+func trainRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	name := i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()
+	racer, cost, err := TrainRacer(i.GuildID, i.Member.User.ID, name)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to train the racer: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You paid %d to train %s %s to level %d. It now has a %d%% chance of moving an extra space each turn.",
+		cost, racer.Emoji, racer.Name, racer.Training, racer.Training*trainingChancePerLevel))).SendEphemeral(s, i.Interaction)
+}
+
+// useRacer sets the racer the member races with.
+func useRacer(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	name := i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()
+	racer, err := UseRacer(i.GuildID, i.Member.User.ID, name)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("You will race with %s %s.", racer.Emoji, racer.Name))).SendEphemeral(s, i.Interaction)
+}
+
+// configStables sets whether members race with their own racers, along with the cost of buying and training racers.
+func configStables(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "assignment":
+			config.RacerAssignment = option.StringValue()
+		case "price":
+			config.RacerPrice = int(option.IntValue())
+		case "training-cost":
+			config.TrainingCost = int(option.IntValue())
+		case "max-training":
+			config.MaxTraining = int(option.IntValue())
+		case "stable-size":
+			config.MaxStableSize = int(option.IntValue())
+		}
+	}
+	writeConfig(config)
+
+	disgomsg.NewResponse(disgomsg.WithContent("Stables set to "+formatStableConfig(config))).Send(s, i.Interaction)
+}
+
+// formatStableConfig returns a description of how racers are assigned and what owned racers cost.
+func formatStableConfig(config *Config) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	assignment := RacerAssignmentRandom
+	if config.RacerAssignment == RacerAssignmentOwned {
+		assignment = RacerAssignmentOwned
+	}
+	return p.Sprintf("%s racers, price %d, training %d per level up to level %d, %d racers per member",
+		assignment, config.racerPrice(), config.trainingCost(1), config.maxTraining(), config.maxStableSize())
+}
+
+// formatStableRacer returns the training and record of an owned racer.
+func formatStableRacer(racer *StableRacer, config *Config) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	training := p.Sprintf("Training level %d of %d", racer.Training, config.maxTraining())
+	if racer.Training < config.maxTraining() {
+		training += p.Sprintf(" (next level costs %d)", config.trainingCost(racer.Training+1))
+	}
+	return p.Sprintf("%s, %s\n%d races: %d wins, %d places, %d shows, %d losses",
+		racer.MovementSpeed, training, racer.Races, racer.Wins, racer.Places, racer.Shows, racer.Losses())
+}
@@ -0,0 +1,102 @@
+package race
+
+import (
+	"testing"
+)
+
+func TestTrainingBonus(t *testing.T) {
+	r := newRaceRand(1, simulationStream)
+	untouched := newRaceRand(1, simulationStream)
+	if bonus := trainingBonus(0, 6, r); bonus != 0 {
+		t.Errorf("expected no bonus for an untrained racer, got %d", bonus)
+	}
+	if bonus := trainingBonus(5, 0, r); bonus != 0 {
+		t.Errorf("expected no bonus for a racer that didn't move, got %d", bonus)
+	}
+	if r.Uint64() != untouched.Uint64() {
+		t.Error("expected no random numbers to be drawn when training can't apply")
+	}
+
+	bonuses := 0
+	for range 10000 {
+		bonuses += trainingBonus(maxTrainingLevel+5, 6, r)
+	}
+	maxChance := maxTrainingLevel * trainingChancePerLevel
+	if rate := bonuses / 100; rate < maxChance-3 || rate > maxChance+3 {
+		t.Errorf("expected training to be capped at a %d%% chance, got %d%%", maxChance, rate)
+	}
+}
+
+func TestStableRacerRecord(t *testing.T) {
+	racer := &StableRacer{}
+	for _, place := range []int{1, 2, 3, 4, 1, 7} {
+		racer.recordFinish(place)
+	}
+	if racer.Races != 6 || racer.Wins != 2 || racer.Places != 1 || racer.Shows != 1 || racer.Losses() != 2 {
+		t.Errorf("expected 6 races with 2 wins, 1 place, 1 show and 2 losses, got %+v with %d losses", racer, racer.Losses())
+	}
+}
+
+func TestFindAvatar(t *testing.T) {
+	avatars := []*Avatar{
+		{Emoji: "<:Minion:1346564146463768636>", MovementSpeed: "fast"},
+		{Emoji: "🐢", MovementSpeed: "slow"},
+	}
+	if avatar := findAvatar(avatars, " minion "); avatar != avatars[0] {
+		t.Errorf("expected to find the Minion, got %v", avatar)
+	}
+	if avatar := findAvatar(avatars, "🐢"); avatar != avatars[1] {
+		t.Errorf("expected to find the turtle, got %v", avatar)
+	}
+	if avatar := findAvatar(avatars, "Dragon"); avatar != nil {
+		t.Errorf("expected no avatar, got %v", avatar)
+	}
+}
+
+func TestStableConfigDefaults(t *testing.T) {
+	config := &Config{}
+	if config.racerPrice() != defaultRacerPrice || config.maxTraining() != defaultMaxTraining || config.maxStableSize() != defaultMaxStableSize {
+		t.Error("expected the defaults for an unset configuration")
+	}
+	if cost := config.trainingCost(3); cost != 3*defaultTrainingCost {
+		t.Errorf("expected the third level to cost %d, got %d", 3*defaultTrainingCost, cost)
+	}
+
+	config.MaxTraining = maxTrainingLevel * 2
+	if config.maxTraining() != maxTrainingLevel {
+		t.Errorf("expected training to be capped at %d, got %d", maxTrainingLevel, config.maxTraining())
+	}
+}
+
+func TestReplayTrainedRacer(t *testing.T) {
+	record := &RaceRecord{
+		GuildID:        "123",
+		Seed:           9,
+		TrackLength:    60,
+		MinPrizeAmount: 100,
+		MaxPrizeAmount: 200,
+		Racers: []*RacerRecord{
+			{MemberID: "1", Emoji: "a", MovementSpeed: "steady", Training: 10},
+			{MemberID: "2", Emoji: "b", MovementSpeed: "steady"},
+		},
+	}
+	record.Finishers = record.replay().Finishers
+	if err := record.verify(record.replay()); err != nil {
+		t.Errorf("expected the trained racer to replay the same way, got %v", err)
+	}
+	if record.Finishers[0].MemberID != "1" {
+		t.Errorf("expected the trained racer to beat a steady racer, got %s", record.Finishers[0].MemberID)
+	}
+}
+
+func TestGetStableLock(t *testing.T) {
+	if getStableLock("1", "2") != getStableLock("1", "2") {
+		t.Error("expected the same lock for the same member")
+	}
+	if getStableLock("1", "2") == getStableLock("1", "3") {
+		t.Error("expected a different lock for each member")
+	}
+	if getStableLock("1", "23") == getStableLock("12", "3") {
+		t.Error("expected a different lock for each guild")
+	}
+}
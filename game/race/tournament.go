@@ -0,0 +1,772 @@
+package race
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/disgomsg"
+	"github.com/rbrabson/goblin/bank"
+	"github.com/rbrabson/goblin/guild"
+	"github.com/rbrabson/goblin/internal/format"
+	"github.com/rbrabson/goblin/internal/unicode"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+const (
+	TournamentStartLayout = "2006-01-02 15:04"
+	maxStandingsShown     = 10
+)
+
+// TournamentState is the stage a tournament has reached.
+type TournamentState string
+
+const (
+	TournamentScheduled TournamentState = "scheduled"
+	TournamentHeats     TournamentState = "heats"
+	TournamentCompleted TournamentState = "completed"
+	TournamentCancelled TournamentState = "cancelled"
+)
+
+var (
+	defaultTournamentPoints = []int{10, 7, 5, 3, 2, 1}
+
+	tournamentTimers = make(map[string]*time.Timer)
+	tournamentLocks  = make(map[string]*sync.Mutex)
+	tournamentLock   = sync.Mutex{}
+)
+
+// Tournament is a series of heats run over a day or a week. Racers earn points for their finishing
+// position in each heat, and the racers with the most points qualify for a final that decides the champion.
+type Tournament struct {
+	ID           bson.ObjectID         `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID      string                `json:"guild_id" bson:"guild_id"`
+	Name         string                `json:"name" bson:"name"`
+	ChannelID    string                `json:"channel_id" bson:"channel_id"`
+	Start        time.Time             `json:"start" bson:"start"`
+	TimeZone     string                `json:"time_zone" bson:"time_zone"`
+	HeatInterval time.Duration         `json:"heat_interval" bson:"heat_interval"`
+	NumHeats     int                   `json:"num_heats" bson:"num_heats"`
+	HeatsRun     int                   `json:"heats_run" bson:"heats_run"`
+	Points       []int                 `json:"points" bson:"points"`
+	Qualifiers   int                   `json:"qualifiers" bson:"qualifiers"`
+	Prize        int                   `json:"prize" bson:"prize"`
+	State        TournamentState       `json:"state" bson:"state"`
+	Standings    []*TournamentStanding `json:"standings" bson:"standings"`
+	RaceIDs      []bson.ObjectID       `json:"race_ids" bson:"race_ids"`
+	ChampionID   string                `json:"champion_id,omitempty" bson:"champion_id,omitempty"`
+	Seed         int64                 `json:"seed" bson:"seed"`
+}
+
+// TournamentStanding is the points earned by a racer entered into a tournament.
+type TournamentStanding struct {
+	MemberID string `json:"member_id" bson:"member_id"`
+	Name     string `json:"name" bson:"name"`
+	Points   int    `json:"points" bson:"points"`
+	Heats    int    `json:"heats" bson:"heats"`
+	Wins     int    `json:"wins" bson:"wins"`
+}
+
+// NewTournament creates a new tournament. The start time is parsed in the given time zone, which defaults
+// to UTC. The points are a comma separated list of the points awarded for each finishing position in a heat.
+func NewTournament(name string, channelID string, start string, timeZone string, numHeats int, interval time.Duration, qualifiers int, prize int, points string) (*Tournament, error) {
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", timeZone)
+	}
+	startTime, err := time.ParseInLocation(TournamentStartLayout, start, loc)
+	if err != nil {
+		return nil, fmt.Errorf("the start time must be in the format `YYYY-MM-DD HH:MM`")
+	}
+	if !startTime.After(time.Now()) {
+		return nil, fmt.Errorf("the start time must be in the future")
+	}
+	if numHeats < 1 {
+		return nil, fmt.Errorf("there must be at least one heat")
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("the interval between heats must be greater than zero")
+	}
+	if qualifiers < 2 {
+		return nil, fmt.Errorf("at least two racers must qualify for the final")
+	}
+	if prize < 0 {
+		return nil, fmt.Errorf("the prize can't be negative")
+	}
+	pointsTable, err := parsePoints(points)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament := &Tournament{
+		Name:         name,
+		ChannelID:    channelID,
+		Start:        startTime,
+		TimeZone:     timeZone,
+		HeatInterval: interval,
+		NumHeats:     numHeats,
+		Points:       pointsTable,
+		Qualifiers:   qualifiers,
+		Prize:        prize,
+		State:        TournamentScheduled,
+		Standings:    make([]*TournamentStanding, 0),
+		RaceIDs:      make([]bson.ObjectID, 0),
+		Seed:         rand.Int64(),
+	}
+
+	return tournament, nil
+}
+
+// parsePoints returns the points awarded for each finishing position. An empty string returns the default points.
+func parsePoints(points string) ([]int, error) {
+	if strings.TrimSpace(points) == "" {
+		return slices.Clone(defaultTournamentPoints), nil
+	}
+
+	fields := strings.Split(points, ",")
+	table := make([]int, 0, len(fields))
+	for _, field := range fields {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("the points must be a comma separated list of whole numbers, such as `10,7,5,3,2,1`")
+		}
+		table = append(table, value)
+	}
+
+	return table, nil
+}
+
+// CreateTournament schedules the tournament for the guild. Only one tournament may be scheduled or running
+// in a guild at a time.
+func CreateTournament(guildID string, tournament *Tournament) error {
+	mutex := getTournamentLock(guildID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if readActiveTournament(guildID) != nil {
+		return ErrTournamentAlreadyScheduled
+	}
+
+	tournament.ID = bson.NewObjectID()
+	tournament.GuildID = guildID
+	writeTournament(tournament)
+	scheduleTournament(tournament)
+
+	slog.Info("scheduled race tournament",
+		slog.String("guildID", guildID),
+		slog.String("name", tournament.Name),
+		slog.Time("start", tournament.Start),
+		slog.Int("heats", tournament.NumHeats),
+	)
+
+	return nil
+}
+
+// CancelTournament cancels the tournament that is scheduled or running in the guild.
+func CancelTournament(guildID string) (*Tournament, error) {
+	mutex := getTournamentLock(guildID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	tournament.State = TournamentCancelled
+	writeTournament(tournament)
+	stopTournamentTimer(guildID)
+
+	slog.Info("cancelled race tournament", slog.String("guildID", guildID), slog.String("name", tournament.Name))
+
+	return tournament, nil
+}
+
+// GetTournament returns the tournament that is scheduled or running in the guild, or nil if there isn't one.
+func GetTournament(guildID string) *Tournament {
+	mutex := getTournamentLock(guildID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return readActiveTournament(guildID)
+}
+
+// JoinTournament enters the member into the tournament for the guild. Members may only join before the
+// first heat is run.
+func JoinTournament(guildID string, memberID string, name string) (*Tournament, error) {
+	mutex := getTournamentLock(guildID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	if err := tournament.addEntrant(memberID, name); err != nil {
+		return nil, err
+	}
+	writeTournament(tournament)
+
+	return tournament, nil
+}
+
+// LeaveTournament removes the member from the tournament for the guild. Members may only leave before the
+// first heat is run.
+func LeaveTournament(guildID string, memberID string) (*Tournament, error) {
+	mutex := getTournamentLock(guildID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil {
+		return nil, ErrNoTournament
+	}
+	if err := tournament.removeEntrant(memberID); err != nil {
+		return nil, err
+	}
+	writeTournament(tournament)
+
+	return tournament, nil
+}
+
+// addEntrant adds the member to the tournament standings.
+func (t *Tournament) addEntrant(memberID string, name string) error {
+	if t.State != TournamentScheduled {
+		return ErrTournamentStarted
+	}
+	if t.getStanding(memberID) != nil {
+		return ErrAlreadyInTournament
+	}
+	t.Standings = append(t.Standings, &TournamentStanding{MemberID: memberID, Name: name})
+	return nil
+}
+
+// removeEntrant removes the member from the tournament standings.
+func (t *Tournament) removeEntrant(memberID string) error {
+	if t.State != TournamentScheduled {
+		return ErrTournamentStarted
+	}
+	index := slices.IndexFunc(t.Standings, func(standing *TournamentStanding) bool {
+		return standing.MemberID == memberID
+	})
+	if index < 0 {
+		return ErrNotInTournament
+	}
+	t.Standings = slices.Delete(t.Standings, index, index+1)
+	return nil
+}
+
+// getStanding returns the standing for the member, or nil if the member hasn't entered the tournament.
+func (t *Tournament) getStanding(memberID string) *TournamentStanding {
+	for _, standing := range t.Standings {
+		if standing.MemberID == memberID {
+			return standing
+		}
+	}
+	return nil
+}
+
+// nextEventTime returns the time at which the next heat, or the final once all heats are run, takes place.
+func (t *Tournament) nextEventTime() time.Time {
+	return t.Start.Add(time.Duration(t.HeatsRun) * t.HeatInterval)
+}
+
+// awardPoints adds the points for each finishing position in a heat to the standings.
+func (t *Tournament) awardPoints(finishers []*FinisherRecord) {
+	for position, finisher := range finishers {
+		standing := t.getStanding(finisher.MemberID)
+		if standing == nil {
+			continue
+		}
+		standing.Heats++
+		if position == 0 {
+			standing.Wins++
+		}
+		if position < len(t.Points) {
+			standing.Points += t.Points[position]
+		}
+	}
+}
+
+// rankings returns the standings ordered by points, with ties broken by the number of heats won. Racers
+// who are still tied keep the order in which they entered the tournament.
+func (t *Tournament) rankings() []*TournamentStanding {
+	rankings := slices.Clone(t.Standings)
+	slices.SortStableFunc(rankings, func(a, b *TournamentStanding) int {
+		if c := cmp.Compare(b.Points, a.Points); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Wins, a.Wins)
+	})
+	return rankings
+}
+
+// finalists returns the members who qualify for the final, limited to the number of racers allowed in a race.
+func (t *Tournament) finalists(maxNumRacers int) []string {
+	rankings := t.rankings()
+	numFinalists := min(t.Qualifiers, maxNumRacers, len(rankings))
+	finalists := make([]string, 0, numFinalists)
+	for _, standing := range rankings[:numFinalists] {
+		finalists = append(finalists, standing.MemberID)
+	}
+	return finalists
+}
+
+// drawHeat returns the entrants in the order in which they are split into groups for the heat. The order
+// is drawn from the tournament's seed, using a separate stream of random numbers for each heat, so the
+// groups for a heat can be drawn again from the seed.
+func (t *Tournament) drawHeat(heat int) []string {
+	entrants := make([]string, 0, len(t.Standings))
+	for _, standing := range t.Standings {
+		entrants = append(entrants, standing.MemberID)
+	}
+	rng := newRaceRand(t.Seed, heatStream+uint64(heat))
+	rng.Shuffle(len(entrants), func(i, j int) {
+		entrants[i], entrants[j] = entrants[j], entrants[i]
+	})
+	return entrants
+}
+
+// splitHeat splits the entrants into groups that each fit in a single race. The groups are as close to
+// the same size as possible.
+func splitHeat(memberIDs []string, maxNumRacers int) [][]string {
+	if len(memberIDs) == 0 {
+		return nil
+	}
+	numGroups := (len(memberIDs) + maxNumRacers - 1) / maxNumRacers
+	groups := make([][]string, numGroups)
+	for i, memberID := range memberIDs {
+		groups[i%numGroups] = append(groups[i%numGroups], memberID)
+	}
+	return groups
+}
+
+// scheduleTournament sets a timer for the next heat or the final of the tournament.
+func scheduleTournament(tournament *Tournament) {
+	guildID, id := tournament.GuildID, tournament.ID
+	timer := time.AfterFunc(time.Until(tournament.nextEventTime()), func() {
+		runTournamentEvent(guildID, id)
+	})
+
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+	if existing := tournamentTimers[guildID]; existing != nil {
+		existing.Stop()
+	}
+	tournamentTimers[guildID] = timer
+}
+
+// getTournamentLock returns the lock for the tournament in the guild. If one doesn't exist, it creates a
+// new one. Each guild has its own lock, so running the heats in one guild doesn't hold up the tournament
+// commands in the others.
+func getTournamentLock(guildID string) *sync.Mutex {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+
+	mutex, ok := tournamentLocks[guildID]
+	if !ok {
+		mutex = &sync.Mutex{}
+		tournamentLocks[guildID] = mutex
+	}
+	return mutex
+}
+
+// stopTournamentTimer stops the timer for the tournament in the guild.
+func stopTournamentTimer(guildID string) {
+	tournamentLock.Lock()
+	defer tournamentLock.Unlock()
+	if timer := tournamentTimers[guildID]; timer != nil {
+		timer.Stop()
+		delete(tournamentTimers, guildID)
+	}
+}
+
+// runTournamentEvent runs the next heat or the final of the tournament, posts the results and schedules
+// the next event.
+func runTournamentEvent(guildID string, id bson.ObjectID) {
+	mutex := getTournamentLock(guildID)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	tournament := readActiveTournament(guildID)
+	if tournament == nil || tournament.ID != id {
+		return
+	}
+	tournamentLock.Lock()
+	delete(tournamentTimers, guildID)
+	tournamentLock.Unlock()
+
+	config := GetConfig(guildID)
+	if tournament.HeatsRun == 0 && len(tournament.Standings) < max(config.MinNumRacers, 2) {
+		tournament.State = TournamentCancelled
+		writeTournament(tournament)
+		slog.Info("cancelled race tournament without enough racers", slog.String("guildID", guildID), slog.String("name", tournament.Name))
+		announceTournament(tournament, &discordgo.MessageEmbed{
+			Title:       tournament.Name,
+			Description: "The tournament has been cancelled, as not enough racers entered.",
+		})
+		return
+	}
+
+	if tournament.HeatsRun < tournament.NumHeats {
+		runTournamentHeat(tournament, config)
+		scheduleTournament(tournament)
+		return
+	}
+	runTournamentFinal(tournament, config)
+}
+
+// runTournamentHeat runs the next heat of the tournament and awards points to the racers.
+func runTournamentHeat(tournament *Tournament, config *Config) {
+	p := message.NewPrinter(language.AmericanEnglish)
+
+	tournament.State = TournamentHeats
+	tournament.HeatsRun++
+	entrants := tournament.drawHeat(tournament.HeatsRun)
+	groups := splitHeat(entrants, config.MaxNumRacers)
+	fields := make([]*discordgo.MessageEmbedField, 0, len(groups)+1)
+	for n, group := range groups {
+		race := runTournamentRace(tournament, config, group)
+		finishers := getFinishers(race)
+		tournament.awardPoints(finishers)
+		tournament.RaceIDs = append(tournament.RaceIDs, race.ID)
+
+		name := "Results"
+		if len(groups) > 1 {
+			name = p.Sprintf("Group %d", n+1)
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   name,
+			Value:  unicode.Truncate(formatTournamentRace(race, tournament.Points), maxFieldLength),
+			Inline: false,
+		})
+	}
+	writeTournament(tournament)
+
+	slog.Info("ran race tournament heat",
+		slog.String("guildID", tournament.GuildID),
+		slog.String("name", tournament.Name),
+		slog.Int("heat", tournament.HeatsRun),
+		slog.Int("numRacers", len(entrants)),
+	)
+
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   "Standings",
+		Value:  unicode.Truncate(formatStandings(tournament.rankings(), tournament.Qualifiers), maxFieldLength),
+		Inline: false,
+	})
+	next := "The final"
+	if tournament.HeatsRun < tournament.NumHeats {
+		next = p.Sprintf("Heat %d", tournament.HeatsRun+1)
+	}
+	announceTournament(tournament, &discordgo.MessageEmbed{
+		Title:       p.Sprintf("%s: Heat %d of %d", tournament.Name, tournament.HeatsRun, tournament.NumHeats),
+		Description: p.Sprintf("%s runs <t:%d:R>.", next, tournament.nextEventTime().Unix()),
+		Fields:      fields,
+	})
+}
+
+// runTournamentFinal runs the final of the tournament and pays the prize to the champion.
+func runTournamentFinal(tournament *Tournament, config *Config) {
+	p := message.NewPrinter(language.AmericanEnglish)
+
+	race := runTournamentRace(tournament, config, tournament.finalists(config.MaxNumRacers))
+	finishers := getFinishers(race)
+	tournament.RaceIDs = append(tournament.RaceIDs, race.ID)
+	tournament.ChampionID = finishers[0].MemberID
+	tournament.State = TournamentCompleted
+	writeTournament(tournament)
+
+	if tournament.Prize > 0 {
+		account := bank.GetAccount(tournament.GuildID, tournament.ChampionID)
+		if err := account.Deposit(tournament.Prize); err != nil {
+			slog.Error("failed to pay the race tournament prize",
+				slog.String("guildID", tournament.GuildID),
+				slog.String("memberID", tournament.ChampionID),
+				slog.Int("prize", tournament.Prize),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	slog.Info("race tournament completed",
+		slog.String("guildID", tournament.GuildID),
+		slog.String("name", tournament.Name),
+		slog.String("champion", tournament.ChampionID),
+		slog.Int("prize", tournament.Prize),
+	)
+
+	description := p.Sprintf("<@%s> is the champion!", tournament.ChampionID)
+	if tournament.Prize > 0 {
+		description = p.Sprintf("<@%s> is the champion and wins %d!", tournament.ChampionID, tournament.Prize)
+	}
+	announceTournament(tournament, &discordgo.MessageEmbed{
+		Title:       p.Sprintf("%s: Final", tournament.Name),
+		Description: description,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Results",
+				Value:  unicode.Truncate(formatTournamentRace(race, nil), maxFieldLength),
+				Inline: false,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Race ID: " + race.ID.Hex()},
+	})
+}
+
+// runTournamentRace runs a heat or final of the tournament with the given members, using the same race
+// logic as the game. The race is saved so it may be replayed, and the results are recorded in the members'
+// stats. Tournament races are run for points, so the racers win nothing until the champion is paid.
+func runTournamentRace(tournament *Tournament, config *Config, memberIDs []string) *Race {
+	race := newRace(tournament.GuildID, config, RaceInProgress)
+	race.TournamentID = tournament.ID
+	for _, memberID := range memberIDs {
+		member := getRaceMember(tournament.GuildID, guild.GetMember(tournament.GuildID, memberID))
+		avatar, stableRacer := getParticipantAvatar(race, memberID)
+		race.Racers = append(race.Racers, &RaceParticipant{
+			Member: member,
+			Racer:  avatar,
+			Stable: stableRacer,
+		})
+	}
+	race.runRace(len([]rune(config.Track)))
+	race.setState(RaceFinished)
+	race.recordResults()
+	race.updateGameStats()
+
+	return race
+}
+
+// formatTournamentRace returns the finishing order of a tournament race, along with the points awarded.
+func formatTournamentRace(race *Race, points []int) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	lastLeg := race.RaceLegs[len(race.RaceLegs)-1]
+	var sb strings.Builder
+	for n, position := range lastLeg.ParticipantPositions {
+		participant := position.RaceParticipant
+		sb.WriteString(p.Sprintf("%d. %s %s %.2fs", n+1, participant.Racer.Emoji, participant.Member.guildMember.Name, position.Speed))
+		if n < len(points) && points[n] > 0 {
+			sb.WriteString(p.Sprintf(" (+%d)", points[n]))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatStandings returns the leading racers in the tournament. Racers who would qualify for the final
+// are marked.
+func formatStandings(rankings []*TournamentStanding, qualifiers int) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	if len(rankings) == 0 {
+		return "No racers have entered"
+	}
+	var sb strings.Builder
+	for n, standing := range rankings[:min(len(rankings), maxStandingsShown)] {
+		sb.WriteString(p.Sprintf("%d. %s: %d points, %d wins", n+1, standing.Name, standing.Points, standing.Wins))
+		if n < qualifiers {
+			sb.WriteString(" ✓")
+		}
+		sb.WriteString("\n")
+	}
+	if len(rankings) > maxStandingsShown {
+		sb.WriteString(p.Sprintf("...and %d more", len(rankings)-maxStandingsShown))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// announceTournament posts a message about the tournament to its channel.
+func announceTournament(tournament *Tournament, embed *discordgo.MessageEmbed) {
+	if bot == nil {
+		return
+	}
+	msg := disgomsg.NewMessage(disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed}))
+	if _, err := msg.Send(bot.Session, tournament.ChannelID); err != nil {
+		slog.Error("failed to send race tournament results",
+			slog.String("guildID", tournament.GuildID),
+			slog.String("channelID", tournament.ChannelID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// restoreTournaments sets the timers for the scheduled and running tournaments in all guilds. This is
+// called when the bot starts. Heats that were missed while the bot was down are run right away.
+func restoreTournaments() {
+	tournaments, err := readActiveTournaments()
+	if err != nil {
+		slog.Error("failed to read race tournaments", slog.Any("error", err))
+		return
+	}
+	for _, tournament := range tournaments {
+		mutex := getTournamentLock(tournament.GuildID)
+		mutex.Lock()
+		scheduleTournament(tournament)
+		mutex.Unlock()
+	}
+
+	slog.Debug("restored race tournaments", slog.Int("count", len(tournaments)))
+}
+
+// raceTournament routes the `race tournament` commands to the proper handlers.
+func raceTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "join":
+		joinTournament(s, i)
+	case "leave":
+		leaveTournament(s, i)
+	case "standings":
+		tournamentStandings(s, i)
+	}
+}
+
+// adminRaceTournament routes the `race-admin tournament` commands to the proper handlers.
+func adminRaceTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "create":
+		createTournament(s, i)
+	case "cancel":
+		cancelTournament(s, i)
+	}
+}
+
+// joinTournament enters the member into the race tournament.
+func joinTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	guildMember := guild.GetMember(i.GuildID, i.Member.User.ID).SetName(i.Member.User.Username, i.Member.Nick, i.Member.User.GlobalName)
+	tournament, err := JoinTournament(i.GuildID, i.Member.User.ID, guildMember.Name)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("%s has entered **%s**, which starts <t:%d:R>.", guildMember.Name, tournament.Name, tournament.Start.Unix()))).Send(s, i.Interaction)
+}
+
+// leaveTournament removes the member from the race tournament.
+func leaveTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	tournament, err := LeaveTournament(i.GuildID, i.Member.User.ID)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("You have left **%s**.", tournament.Name))).SendEphemeral(s, i.Interaction)
+}
+
+// tournamentStandings shows the schedule and current standings for the race tournament.
+func tournamentStandings(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	tournament := GetTournament(i.GuildID)
+	if tournament == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(ErrNoTournament.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	next := "The final"
+	if tournament.HeatsRun < tournament.NumHeats {
+		next = p.Sprintf("Heat %d of %d", tournament.HeatsRun+1, tournament.NumHeats)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       tournament.Name,
+		Description: p.Sprintf("%s runs <t:%d:f> (<t:%d:R>). Results are posted in <#%s>.", next, tournament.nextEventTime().Unix(), tournament.nextEventTime().Unix(), tournament.ChannelID),
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Format",
+				Value:  p.Sprintf("%d heats, %s apart. The top %d racers qualify for the final, and the champion wins %d.", tournament.NumHeats, format.Duration(tournament.HeatInterval), tournament.Qualifiers, tournament.Prize),
+				Inline: false,
+			},
+			{
+				Name:   "Points",
+				Value:  formatPoints(tournament.Points),
+				Inline: false,
+			},
+			{
+				Name:   p.Sprintf("Standings (%d racers)", len(tournament.Standings)),
+				Value:  unicode.Truncate(formatStandings(tournament.rankings(), tournament.Qualifiers), maxFieldLength),
+				Inline: false,
+			},
+		},
+	}
+
+	disgomsg.NewResponse(disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed})).SendEphemeral(s, i.Interaction)
+}
+
+// createTournament schedules a new race tournament.
+func createTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	p := message.NewPrinter(language.AmericanEnglish)
+	var name, start, timeZone, points string
+	var numHeats, qualifiers, prize int
+	var interval time.Duration
+	channelID := i.ChannelID
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "name":
+			name = strings.TrimSpace(option.StringValue())
+		case "start":
+			start = strings.TrimSpace(option.StringValue())
+		case "heats":
+			numHeats = int(option.IntValue())
+		case "interval":
+			interval = time.Duration(option.IntValue()) * time.Minute
+		case "qualifiers":
+			qualifiers = int(option.IntValue())
+		case "prize":
+			prize = int(option.IntValue())
+		case "channel":
+			channelID = option.ChannelValue(s).ID
+		case "points":
+			points = option.StringValue()
+		case "timezone":
+			timeZone = strings.TrimSpace(option.StringValue())
+		}
+	}
+
+	tournament, err := NewTournament(name, channelID, start, timeZone, numHeats, interval, qualifiers, prize, points)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to schedule the tournament: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if err := CreateTournament(i.GuildID, tournament); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to schedule the tournament: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	final := tournament.Start.Add(time.Duration(tournament.NumHeats) * tournament.HeatInterval)
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("**%s** is scheduled! The first of %d heats runs <t:%d:f> and the final runs <t:%d:f>. Enter with `/race tournament join`.",
+		tournament.Name, tournament.NumHeats, tournament.Start.Unix(), final.Unix()))).Send(s, i.Interaction)
+}
+
+// cancelTournament cancels the race tournament.
+func cancelTournament(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	tournament, err := CancelTournament(i.GuildID)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent(unicode.FirstToUpper(err.Error()))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Cancelled the tournament %q.", tournament.Name))).Send(s, i.Interaction)
+}
+
+// formatPoints returns the points awarded for each finishing position in a heat.
+func formatPoints(points []int) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	positions := make([]string, 0, len(points))
+	for n, value := range points {
+		positions = append(positions, p.Sprintf("%s: %d", ordinal(n+1), value))
+	}
+	return strings.Join(positions, ", ")
+}
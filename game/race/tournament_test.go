@@ -0,0 +1,163 @@
+package race
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParsePoints(t *testing.T) {
+	points, err := parsePoints("")
+	if err != nil || !slices.Equal(points, defaultTournamentPoints) {
+		t.Errorf("expected the default points, got %v (%v)", points, err)
+	}
+
+	points, err = parsePoints(" 5, 3 ,1")
+	if err != nil || !slices.Equal(points, []int{5, 3, 1}) {
+		t.Errorf("expected [5 3 1], got %v (%v)", points, err)
+	}
+
+	for _, points := range []string{"5,three,1", "5,-1", "5,,1"} {
+		if _, err := parsePoints(points); err == nil {
+			t.Errorf("expected an error for %q", points)
+		}
+	}
+}
+
+func TestNewTournament(t *testing.T) {
+	start := time.Now().Add(time.Hour).UTC().Format(TournamentStartLayout)
+	tournament, err := NewTournament("Cup", "1", start, "", 3, time.Hour, 4, 1000, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tournament.State != TournamentScheduled || tournament.TimeZone != "UTC" {
+		t.Errorf("expected a scheduled tournament in UTC, got %s in %s", tournament.State, tournament.TimeZone)
+	}
+	if !tournament.nextEventTime().Equal(tournament.Start) {
+		t.Errorf("expected the first heat at %s, got %s", tournament.Start, tournament.nextEventTime())
+	}
+	tournament.HeatsRun = 2
+	if want := tournament.Start.Add(2 * time.Hour); !tournament.nextEventTime().Equal(want) {
+		t.Errorf("expected the third heat at %s, got %s", want, tournament.nextEventTime())
+	}
+
+	past := time.Now().Add(-time.Hour).UTC().Format(TournamentStartLayout)
+	if _, err := NewTournament("Cup", "1", past, "", 3, time.Hour, 4, 1000, ""); err == nil {
+		t.Error("expected an error for a start time in the past")
+	}
+	if _, err := NewTournament("Cup", "1", start, "", 3, time.Hour, 1, 1000, ""); err == nil {
+		t.Error("expected an error for a single qualifier")
+	}
+	if _, err := NewTournament("Cup", "1", start, "Nowhere/Special", 3, time.Hour, 4, 1000, ""); err == nil {
+		t.Error("expected an error for an unknown time zone")
+	}
+}
+
+func TestTournamentEntrants(t *testing.T) {
+	tournament := &Tournament{State: TournamentScheduled}
+	if err := tournament.addEntrant("1", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tournament.addEntrant("1", "alice"); err != ErrAlreadyInTournament {
+		t.Errorf("expected %v, got %v", ErrAlreadyInTournament, err)
+	}
+	if err := tournament.removeEntrant("2"); err != ErrNotInTournament {
+		t.Errorf("expected %v, got %v", ErrNotInTournament, err)
+	}
+	if err := tournament.removeEntrant("1"); err != nil || len(tournament.Standings) != 0 {
+		t.Errorf("expected the entrant to be removed, got %v", err)
+	}
+
+	tournament.State = TournamentHeats
+	if err := tournament.addEntrant("2", "bob"); err != ErrTournamentStarted {
+		t.Errorf("expected %v, got %v", ErrTournamentStarted, err)
+	}
+}
+
+func TestTournamentStandings(t *testing.T) {
+	tournament := &Tournament{
+		State:      TournamentScheduled,
+		Points:     []int{10, 5},
+		Qualifiers: 2,
+	}
+	for _, id := range []string{"1", "2", "3", "4"} {
+		tournament.addEntrant(id, id)
+	}
+
+	tournament.awardPoints([]*FinisherRecord{{MemberID: "1"}, {MemberID: "2"}, {MemberID: "3"}, {MemberID: "4"}})
+	tournament.awardPoints([]*FinisherRecord{{MemberID: "2"}, {MemberID: "3"}, {MemberID: "1"}, {MemberID: "4"}})
+	tournament.awardPoints([]*FinisherRecord{{MemberID: "3"}, {MemberID: "4"}})
+
+	want := map[string]int{"1": 10, "2": 15, "3": 15, "4": 5}
+	for _, standing := range tournament.Standings {
+		if standing.Points != want[standing.MemberID] {
+			t.Errorf("expected %d points for %s, got %d", want[standing.MemberID], standing.MemberID, standing.Points)
+		}
+	}
+	if standing := tournament.getStanding("4"); standing.Heats != 3 || standing.Wins != 0 {
+		t.Errorf("expected 3 heats and no wins, got %d and %d", standing.Heats, standing.Wins)
+	}
+
+	// Members 2 and 3 are tied on points and wins, so they keep the order they entered
+	rankings := tournament.rankings()
+	order := make([]string, 0, len(rankings))
+	for _, standing := range rankings {
+		order = append(order, standing.MemberID)
+	}
+	if !slices.Equal(order, []string{"2", "3", "1", "4"}) {
+		t.Errorf("expected rankings [2 3 1 4], got %v", order)
+	}
+
+	if finalists := tournament.finalists(10); !slices.Equal(finalists, []string{"2", "3"}) {
+		t.Errorf("expected finalists [2 3], got %v", finalists)
+	}
+	tournament.Qualifiers = 10
+	if finalists := tournament.finalists(3); len(finalists) != 3 {
+		t.Errorf("expected the finalists to be limited to 3 racers, got %d", len(finalists))
+	}
+}
+
+func TestSplitHeat(t *testing.T) {
+	memberIDs := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}
+	groups := splitHeat(memberIDs, 5)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	total := 0
+	for _, group := range groups {
+		if len(group) < 3 || len(group) > 4 {
+			t.Errorf("expected groups of 3 or 4 racers, got %d", len(group))
+		}
+		total += len(group)
+	}
+	if total != len(memberIDs) {
+		t.Errorf("expected %d racers, got %d", len(memberIDs), total)
+	}
+
+	if groups := splitHeat(memberIDs[:4], 10); len(groups) != 1 {
+		t.Errorf("expected a single group, got %d", len(groups))
+	}
+	if groups := splitHeat(nil, 10); groups != nil {
+		t.Errorf("expected no groups, got %v", groups)
+	}
+}
+
+func TestDrawHeat(t *testing.T) {
+	tournament := &Tournament{Seed: 42}
+	for _, memberID := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		tournament.Standings = append(tournament.Standings, &TournamentStanding{MemberID: memberID})
+	}
+
+	first := tournament.drawHeat(1)
+	if !slices.Equal(first, tournament.drawHeat(1)) {
+		t.Error("expected the same heat to be drawn the same way from the seed")
+	}
+	if slices.Equal(first, tournament.drawHeat(2)) {
+		t.Error("expected each heat to be drawn differently")
+	}
+	sorted := slices.Clone(first)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, []string{"1", "2", "3", "4", "5", "6", "7", "8"}) {
+		t.Errorf("expected every entrant to be drawn once, got %v", first)
+	}
+}
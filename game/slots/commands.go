@@ -3,6 +3,8 @@ package slots
 import (
 	"fmt"
 	"log/slog"
+	"math"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -17,15 +19,164 @@ import (
 	"golang.org/x/text/message"
 )
 
+var (
+	minBetValue      = 1.0
+	minCooldownValue = 0.0
+	minRTPValue      = 0.0
+	maxRTPValue      = 200.0
+)
+
 var (
 	commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"slots": slots,
+		"slots":       slots,
+		"slots-admin": slotsAdmin,
+	}
+
+	adminCommands = []*discordgo.ApplicationCommand{
+		{
+			Name:        "slots-admin",
+			Description: "Slots admin commands.",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Name:        "config",
+					Description: "Configures the slot machine.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "info",
+							Description: "Returns the slots configuration for the server.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+						},
+						{
+							Name:        "cooldown",
+							Description: "Sets the time a member must wait between spins.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "seconds",
+									Description: "The number of seconds between spins.",
+									Required:    true,
+									MinValue:    &minCooldownValue,
+								},
+							},
+						},
+						{
+							Name:        "bets",
+							Description: "Sets the bet amounts allowed on the slot machine.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "amounts",
+									Description: "A comma-separated list of bet amounts, such as 100,300,500.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "symbols",
+							Description: "Sets the theme used for the symbols on the slot machine.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "theme",
+									Description: "The name of the symbol theme.",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Name:        "payout",
+							Description: "Sets the payout table used by the default slot machine.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "table",
+									Description: "The name of the payout table.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:        "machine",
+					Description: "Adds or removes slot machines.",
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "add",
+							Description: "Adds a slot machine, or replaces the one with the same name.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of the slot machine.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "lookup",
+									Description: "The name of the lookup table with the symbols on each reel.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "payout",
+									Description: "The name of the payout table.",
+									Required:    true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "min-bet",
+									Description: "The smallest bet allowed on the slot machine.",
+									Required:    true,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionInteger,
+									Name:        "max-bet",
+									Description: "The largest bet allowed on the slot machine.",
+									Required:    true,
+									MinValue:    &minBetValue,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionNumber,
+									Name:        "rtp",
+									Description: "The target return to player, as a percentage.",
+									Required:    false,
+									MinValue:    &minRTPValue,
+									MaxValue:    maxRTPValue,
+								},
+							},
+						},
+						{
+							Name:        "remove",
+							Description: "Removes a slot machine.",
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "The name of the slot machine.",
+									Required:    true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	memberCommands = []*discordgo.ApplicationCommand{
 		{
 			Name:        "slots",
-			Description: "Interacts with the slot machine.",
+			Description: "Interacts with the slot machines.",
 			Options: []*discordgo.ApplicationCommandOption{
 				{
 					Name:        "play",
@@ -37,20 +188,13 @@ var (
 							Description: "The amount to bet on the slot machine.",
 							Type:        discordgo.ApplicationCommandOptionInteger,
 							Required:    true,
-							Choices: []*discordgo.ApplicationCommandOptionChoice{
-								{
-									Name:  "500",
-									Value: 500,
-								},
-								{
-									Name:  "300",
-									Value: 300,
-								},
-								{
-									Name:  "100",
-									Value: 100,
-								},
-							},
+							MinValue:    &minBetValue,
+						},
+						{
+							Name:        "machine",
+							Description: "The name of the slot machine.",
+							Type:        discordgo.ApplicationCommandOptionString,
+							Required:    false,
 						},
 					},
 				},
@@ -58,6 +202,19 @@ var (
 					Name:        "paytable",
 					Description: "Get the pay table for the slot machine.",
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Name:        "machine",
+							Description: "The name of the slot machine.",
+							Type:        discordgo.ApplicationCommandOptionString,
+							Required:    false,
+						},
+					},
+				},
+				{
+					Name:        "machines",
+					Description: "Lists the slot machines on the server.",
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
 				},
 				{
 					Name:        "stats",
@@ -70,6 +227,12 @@ var (
 							Description: "The member or member ID.",
 							Required:    false,
 						},
+						{
+							Name:        "machine",
+							Description: "Only show the stats for this slot machine.",
+							Type:        discordgo.ApplicationCommandOptionString,
+							Required:    false,
+						},
 					},
 				},
 			},
@@ -89,6 +252,8 @@ func slots(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		playSlots(s, i)
 	case "paytable":
 		payTable(s, i)
+	case "machines":
+		listMachines(s, i)
 	case "stats":
 		showStats(s, i)
 	}
@@ -102,16 +267,58 @@ func playSlots(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	guildID := i.GuildID
 	userID := i.Member.User.ID
 
-	options := i.ApplicationCommandData().Options[0].Options
-	bet := int(options[0].IntValue())
+	var bet int
+	var machineName string
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		switch option.Name {
+		case "bet":
+			bet = int(option.IntValue())
+		case "machine":
+			machineName = strings.TrimSpace(option.StringValue())
+		}
+	}
 
 	slog.Debug("`/slots play` command",
 		slog.String("guildID", guildID),
 		slog.String("userID", userID),
 		slog.Int("bet", bet),
+		slog.String("machine", machineName),
 	)
 
-	config := GetConfig()
+	config := GetConfig(guildID)
+	machine := config.GetMachine(machineName)
+	if machine == nil {
+		resp := disgomsg.NewResponse(
+			disgomsg.WithContent(fmt.Sprintf("The slot machine `%s` was not found. Use `/slots machines` to see the slot machines on this server.", machineName)),
+		)
+		if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+			slog.Error("error sending response",
+				slog.String("guildID", guildID),
+				slog.String("userID", userID),
+				slog.Any("error", err),
+			)
+		}
+		return
+	}
+
+	if !config.IsValidBet(bet) || !machine.IsInRange(bet) {
+		content := fmt.Sprintf("Bets aren't allowed on the %s slot machine.", machine.Name)
+		if bets := machine.allowedBets(config.BetAmounts); len(bets) > 0 {
+			content = fmt.Sprintf("Your bet on the %s slot machine must be one of %s.", machine.Name, formatBetAmounts(bets))
+		}
+		resp := disgomsg.NewResponse(
+			disgomsg.WithContent(content),
+		)
+		if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+			slog.Error("error sending response",
+				slog.String("guildID", guildID),
+				slog.String("userID", userID),
+				slog.Any("error", err),
+			)
+		}
+		return
+	}
+
 	member := GetMember(guildID, userID)
 	if member.IsInCooldown(config) {
 		remaining := member.GetCooldownRemaining(config)
@@ -143,10 +350,11 @@ func playSlots(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	sm := GetSlotMachine()
+	sm := GetSlotMachine(config, machine)
 	spinResult := sm.Spin(bet)
 
-	member.AddResults(spinResult)
+	writeSpinRecord(newSpinRecord(guildID, userID, machine.Name, spinResult))
+	member.AddResults(machine.Name, spinResult.SpinResult)
 
 	if spinResult.Payout > 0 {
 		if err := account.Deposit(spinResult.Payout); err != nil {
@@ -200,7 +408,7 @@ func playSlots(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	// Create the embed
 	embed := &discordgo.MessageEmbed{
-		Title:       "🎰 Slot Machine 🎰",
+		Title:       "🎰 " + machine.Name + " 🎰",
 		Description: p.Sprintf("<@%s> bet **%d** coins", userID, spinResult.Bet),
 		Color:       embedColor,
 		Fields: []*discordgo.MessageEmbedField{
@@ -214,6 +422,9 @@ func playSlots(s *discordgo.Session, i *discordgo.InteractionCreate) {
 				Inline: false,
 			},
 		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: p.Sprintf("Seed: %d", spinResult.Seed),
+		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
@@ -226,8 +437,12 @@ func showStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	p := message.NewPrinter(language.AmericanEnglish)
 
 	memberID := i.Member.User.ID
+	machineName := ""
 	options := i.ApplicationCommandData().Options[0].Options
 	for _, option := range options {
+		if option.Name == "machine" {
+			machineName = strings.TrimSpace(option.StringValue())
+		}
 		if option.Name == "user" {
 			var err error
 			member, err := guild.GetMemberByUser(s, i.GuildID, option.UserValue(s))
@@ -252,12 +467,33 @@ func showStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	slog.Debug("`/slots stats` command",
 		slog.String("guildID", guildID),
 		slog.String("memberID", memberID),
+		slog.String("machine", machineName),
 	)
 
-	member := GetMember(guildID, memberID)
+	var member *Member
+	title := "Slot Machine Stats"
+	if machineName != "" {
+		machine := GetConfig(guildID).GetMachine(machineName)
+		if machine == nil {
+			resp := disgomsg.NewResponse(
+				disgomsg.WithContent(fmt.Sprintf("The slot machine `%s` was not found. Use `/slots machines` to see the slot machines on this server.", machineName)),
+			)
+			if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+				slog.Error("error sending response",
+					slog.String("guildID", guildID),
+					slog.Any("error", err),
+				)
+			}
+			return
+		}
+		member = GetMachineMember(guildID, memberID, machine.Name)
+		title = machine.Name + " Stats"
+	} else {
+		member = GetMember(guildID, memberID)
+	}
 
 	embed := &discordgo.MessageEmbed{
-		Title:       "Slot Machine Stats",
+		Title:       title,
 		Description: p.Sprintf("Here are the stats for <@%s>:", memberID),
 		Color:       0x5865F2, // Blue color
 		Fields: []*discordgo.MessageEmbedField{
@@ -329,22 +565,44 @@ func payTable(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	p := message.NewPrinter(language.AmericanEnglish)
 
 	guildID := i.GuildID
-	payTable := GetPayoutTable()
+	machineName := ""
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		if option.Name == "machine" {
+			machineName = strings.TrimSpace(option.StringValue())
+		}
+	}
 
 	slog.Debug("`/slots paytable` command",
 		slog.String("guildID", guildID),
+		slog.String("machine", machineName),
 	)
 
+	config := GetConfig(guildID)
+	machine := config.GetMachine(machineName)
+	if machine == nil {
+		resp := disgomsg.NewResponse(
+			disgomsg.WithContent(fmt.Sprintf("The slot machine `%s` was not found. Use `/slots machines` to see the slot machines on this server.", machineName)),
+		)
+		if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+			slog.Error("error sending response",
+				slog.String("guildID", guildID),
+				slog.Any("error", err),
+			)
+		}
+		return
+	}
+	payTable := GetPayoutTable(machine.PayoutTable)
+
 	embeds := make([]*discordgo.MessageEmbed, 0, 1)
 	if payTable != nil {
 		embed := &discordgo.MessageEmbed{
-			Title:       "Slot Machine Pay Table",
+			Title:       machine.Name + " Pay Table",
 			Description: "Here are the possible winning combinations and their payouts.",
 			Color:       0x00ff00, // Green color
 			Fields:      make([]*discordgo.MessageEmbedField, 0, len(payTable)),
 		}
 
-		sm := GetSlotMachine()
+		sm := GetSlotMachine(config, machine)
 		twoConsecutiveTroops := false
 		for _, payout := range payTable {
 			payoutStr := strconv.FormatFloat(payout.Payout, 'f', -1, 64)
@@ -383,6 +641,53 @@ func payTable(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 }
 
+// listMachines handles the `/slots machines` command.
+func listMachines(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	guildID := i.GuildID
+
+	slog.Debug("`/slots machines` command",
+		slog.String("guildID", guildID),
+	)
+
+	config := GetConfig(guildID)
+	embed := &discordgo.MessageEmbed{
+		Title:       "Slot Machines",
+		Description: "Pick a slot machine with the `machine` option of `/slots play`.",
+		Color:       0x5865F2, // Blue color
+		Fields:      make([]*discordgo.MessageEmbedField, 0, len(config.Machines)),
+	}
+	for _, machine := range config.Machines {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   machine.Name,
+			Value:  formatMachine(config, machine),
+			Inline: false,
+		})
+	}
+
+	resp := disgomsg.NewResponse(disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed}))
+	if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+		slog.Error("error sending response",
+			slog.String("guildID", guildID),
+			slog.String("memberID", i.Member.User.ID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// formatMachine returns the bets allowed on the machine and its target return to player.
+func formatMachine(config *Config, machine *Machine) string {
+	p := message.NewPrinter(language.AmericanEnglish)
+	bets := machine.allowedBets(config.BetAmounts)
+	if len(bets) == 0 {
+		return "No bets are allowed"
+	}
+	content := "Bets: " + formatBetAmounts(bets)
+	if machine.TargetRTP > 0 {
+		content += p.Sprintf("\nTarget return to player: %.1f%%", machine.TargetRTP)
+	}
+	return content
+}
+
 // getPayoutDisplayMessage returns a user-friendly message for the payout symbols.
 func getPayoutDisplayMessage(spin []string, symbolTable SymbolTable) string {
 	if len(spin) == 1 {
@@ -429,3 +734,284 @@ func getPayoutDisplayMessage(spin []string, symbolTable SymbolTable) string {
 	}
 	return display
 }
+
+// slotsAdmin handles the `/slots-admin` commands.
+func slotsAdmin(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if discord.IsShuttingDown(s, i) {
+		return
+	}
+
+	if !guild.IsAdmin(s, i.GuildID, i.Member.User.ID) {
+		disgomsg.NewResponse(disgomsg.WithContent("You do not have permission to use this command.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	switch options[0].Name {
+	case "config":
+		slotsConfig(s, i)
+	case "machine":
+		slotsMachine(s, i)
+	default:
+		slog.Error("unknown command", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("command", options[0].Name))
+		disgomsg.NewResponse(disgomsg.WithContent("Command is unknown")).SendEphemeral(s, i.Interaction)
+	}
+}
+
+// slotsConfig handles the `/slots-admin config` commands.
+func slotsConfig(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "info":
+		configInfo(s, i)
+	case "cooldown":
+		configCooldown(s, i)
+	case "bets":
+		configBets(s, i)
+	case "symbols":
+		configSymbols(s, i)
+	case "payout":
+		configPayout(s, i)
+	default:
+		slog.Error("unknown command", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("command", options[0].Name))
+		disgomsg.NewResponse(disgomsg.WithContent("Command is unknown")).SendEphemeral(s, i.Interaction)
+	}
+}
+
+// configCooldown sets the time a member must wait between spins.
+func configCooldown(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	seconds := i.ApplicationCommandData().Options[0].Options[0].Options[0].IntValue()
+	config.Cooldown = time.Duration(seconds) * time.Second
+	writeConfig(config)
+	slog.Info("slots cooldown updated", slog.String("guildID", i.GuildID), slog.Duration("cooldown", config.Cooldown))
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	disgomsg.NewResponse(disgomsg.WithContent(p.Sprintf("Cooldown set to %d seconds", seconds))).Send(s, i.Interaction)
+}
+
+// configBets sets the bet amounts allowed on the slot machines. The amounts are rejected if a
+// machine would be left without a bet it allows.
+func configBets(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	amounts := i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()
+	betAmounts, err := parseBetAmounts(amounts)
+	if err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("The bet amounts must be a comma-separated list of whole numbers greater than zero.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	config := GetConfig(i.GuildID)
+	for _, machine := range config.Machines {
+		if len(machine.allowedBets(betAmounts)) == 0 {
+			p := message.NewPrinter(language.AmericanEnglish)
+			content := p.Sprintf("None of the bet amounts are between %d and %d, so the %s slot machine couldn't be played.", machine.MinBet, machine.MaxBet, machine.Name)
+			disgomsg.NewResponse(disgomsg.WithContent(content)).SendEphemeral(s, i.Interaction)
+			return
+		}
+	}
+	config.BetAmounts = betAmounts
+	writeConfig(config)
+	slog.Info("slots bet amounts updated", slog.String("guildID", i.GuildID), slog.Any("betAmounts", betAmounts))
+
+	disgomsg.NewResponse(disgomsg.WithContent("Bet amounts set to "+formatBetAmounts(betAmounts))).Send(s, i.Interaction)
+}
+
+// configSymbols sets the theme used for the symbols on the slot machine.
+func configSymbols(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	theme := filepath.Base(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	if GetSymbolTable(theme) == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("The symbol theme `%s` was not found.", theme))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	config := GetConfig(i.GuildID)
+	config.SymbolTheme = theme
+	writeConfig(config)
+	slog.Info("slots symbol theme updated", slog.String("guildID", i.GuildID), slog.String("theme", theme))
+
+	disgomsg.NewResponse(disgomsg.WithContent("Symbol theme set to "+theme)).Send(s, i.Interaction)
+}
+
+// configPayout sets the payout table used by the default slot machine. Other machines have their
+// payout table set when they are added.
+func configPayout(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	name := filepath.Base(strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue()))
+	if GetPayoutTable(name) == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("The payout table `%s` was not found.", name))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	config := GetConfig(i.GuildID)
+	machine := config.GetMachine(defaultMachineName)
+	if machine == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("There is no %s slot machine. Use `/slots-admin machine add` to change the payout table of another machine.", defaultMachineName))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	config.PayoutTable = name
+	machine.PayoutTable = name
+	writeConfig(config)
+	slog.Info("slots payout table updated", slog.String("guildID", i.GuildID), slog.String("machine", machine.Name), slog.String("payoutTable", name))
+
+	disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("Payout table for the %s slot machine set to %s", machine.Name, name))).Send(s, i.Interaction)
+}
+
+// configInfo returns the slots configuration for the server.
+func configInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	config := GetConfig(i.GuildID)
+	p := message.NewPrinter(language.AmericanEnglish)
+
+	embed := &discordgo.MessageEmbed{
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "cooldown",
+				Value:  p.Sprintf("%.f", config.Cooldown.Seconds()),
+				Inline: true,
+			},
+			{
+				Name:   "bets",
+				Value:  formatBetAmounts(config.BetAmounts),
+				Inline: true,
+			},
+			{
+				Name:   "symbols",
+				Value:  config.SymbolTheme,
+				Inline: true,
+			},
+			{
+				Name:   "payout table",
+				Value:  config.PayoutTable,
+				Inline: true,
+			},
+			{
+				Name:   "machines",
+				Value:  formatMachineNames(config.Machines),
+				Inline: false,
+			},
+		},
+	}
+
+	resp := disgomsg.NewResponse(
+		disgomsg.WithContent("Slots Configuration"),
+		disgomsg.WithEmbeds([]*discordgo.MessageEmbed{embed}),
+	)
+	if err := resp.SendEphemeral(s, i.Interaction); err != nil {
+		slog.Error("error sending response",
+			slog.String("guildID", i.GuildID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// formatMachineNames returns the names of the machines as a comma-separated list.
+func formatMachineNames(machines []*Machine) string {
+	names := make([]string, 0, len(machines))
+	for _, machine := range machines {
+		names = append(names, machine.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// slotsMachine handles the `/slots-admin machine` commands.
+func slotsMachine(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options[0].Options
+	switch options[0].Name {
+	case "add":
+		addMachine(s, i)
+	case "remove":
+		removeMachine(s, i)
+	default:
+		slog.Error("unknown command", slog.String("guildID", i.GuildID), slog.String("memberID", i.Member.User.ID), slog.String("command", options[0].Name))
+		disgomsg.NewResponse(disgomsg.WithContent("Command is unknown")).SendEphemeral(s, i.Interaction)
+	}
+}
+
+// addMachine adds a slot machine to the server, or replaces the one with the same name. A machine
+// that allows none of the server's bet amounts is rejected. The return to player is calculated
+// for each allowed bet and reported, along with a warning if it is too far from the target.
+func addMachine(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	machine := &Machine{}
+	for _, option := range i.ApplicationCommandData().Options[0].Options[0].Options {
+		switch option.Name {
+		case "name":
+			machine.Name = strings.TrimSpace(option.StringValue())
+		case "lookup":
+			machine.LookupTable = filepath.Base(strings.TrimSpace(option.StringValue()))
+		case "payout":
+			machine.PayoutTable = filepath.Base(strings.TrimSpace(option.StringValue()))
+		case "min-bet":
+			machine.MinBet = int(option.IntValue())
+		case "max-bet":
+			machine.MaxBet = int(option.IntValue())
+		case "rtp":
+			machine.TargetRTP = option.FloatValue()
+		}
+	}
+
+	if machine.Name == "" {
+		disgomsg.NewResponse(disgomsg.WithContent("The slot machine must have a name.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if machine.MaxBet < machine.MinBet {
+		disgomsg.NewResponse(disgomsg.WithContent("The minimum bet must be no larger than the maximum bet.")).SendEphemeral(s, i.Interaction)
+		return
+	}
+	lookupTable := GetLookupTable(machine.LookupTable)
+	if lookupTable == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("The lookup table `%s` was not found.", machine.LookupTable))).SendEphemeral(s, i.Interaction)
+		return
+	}
+	payoutTable := GetPayoutTable(machine.PayoutTable)
+	if payoutTable == nil {
+		disgomsg.NewResponse(disgomsg.WithContent(fmt.Sprintf("The payout table `%s` was not found.", machine.PayoutTable))).SendEphemeral(s, i.Interaction)
+		return
+	}
+
+	config := GetConfig(i.GuildID)
+	bets := machine.allowedBets(config.BetAmounts)
+	if len(bets) == 0 {
+		p := message.NewPrinter(language.AmericanEnglish)
+		content := p.Sprintf("None of the bet amounts (%s) are between %d and %d, so the slot machine couldn't be played.", formatBetAmounts(config.BetAmounts), machine.MinBet, machine.MaxBet)
+		disgomsg.NewResponse(disgomsg.WithContent(content)).SendEphemeral(s, i.Interaction)
+		return
+	}
+	if err := config.AddMachine(machine); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to add the slot machine: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+	writeConfig(config)
+
+	low, high := calculateRTPRange(lookupTable, payoutTable, bets)
+	slog.Info("slot machine added",
+		slog.String("guildID", i.GuildID),
+		slog.String("machine", machine.String()),
+		slog.Float64("minRTP", low),
+		slog.Float64("maxRTP", high),
+	)
+
+	p := message.NewPrinter(language.AmericanEnglish)
+	content := p.Sprintf("Slot machine %s added, with bets of %s", machine.Name, formatBetAmounts(bets))
+	if low == high {
+		content += p.Sprintf(" and a return to player of %.2f%%", low)
+	} else {
+		content += p.Sprintf(" and a return to player of %.2f%% to %.2f%%, depending on the bet", low, high)
+	}
+	if machine.TargetRTP > 0 && (math.Abs(low-machine.TargetRTP) > rtpTolerance || math.Abs(high-machine.TargetRTP) > rtpTolerance) {
+		content += p.Sprintf(". This is more than %.f%% from the target of %.2f%%, so the lookup or payout table may need to be adjusted.", rtpTolerance, machine.TargetRTP)
+	}
+	disgomsg.NewResponse(disgomsg.WithContent(content)).Send(s, i.Interaction)
+}
+
+// removeMachine removes a slot machine from the server. The statistics for the machine are kept.
+func removeMachine(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	name := strings.TrimSpace(i.ApplicationCommandData().Options[0].Options[0].Options[0].StringValue())
+
+	config := GetConfig(i.GuildID)
+	if err := config.RemoveMachine(name); err != nil {
+		disgomsg.NewResponse(disgomsg.WithContent("Unable to remove the slot machine: "+err.Error())).SendEphemeral(s, i.Interaction)
+		return
+	}
+	writeConfig(config)
+	slog.Info("slot machine removed", slog.String("guildID", i.GuildID), slog.String("machine", name))
+
+	disgomsg.NewResponse(disgomsg.WithContent("Slot machine "+name+" removed")).Send(s, i.Interaction)
+}
@@ -2,40 +2,136 @@ package slots
 
 import (
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rbrabson/goblin/discord"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 const (
 	ConfigFileName = "config"
 )
 
-// Config represents the configuration for the slots game.
+var (
+	defaultBetAmounts = []int{100, 300, 500}
+)
+
+// Config represents the configuration for the slots game on a guild.
 type Config struct {
-	Cooldown time.Duration `json:"cooldown"`
+	ID          bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID     string        `json:"guild_id" bson:"guild_id"`
+	Cooldown    time.Duration `json:"cooldown" bson:"cooldown"`
+	BetAmounts  []int         `json:"bet_amounts" bson:"bet_amounts"`
+	SymbolTheme string        `json:"symbol_theme" bson:"symbol_theme"`
+	PayoutTable string        `json:"payout_table" bson:"payout_table"`
+	Machines    []*Machine    `json:"machines" bson:"machines"`
 }
 
-// GetConfig retrieves the configuration for the slots game.
-func GetConfig() *Config {
-	return newConfig()
+// String returns a string representation of the Config.
+func (c *Config) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("Config{")
+	sb.WriteString("GuildID: " + c.GuildID)
+	fmt.Fprintf(&sb, ", Cooldown: %v", c.Cooldown)
+	fmt.Fprintf(&sb, ", BetAmounts: %v", c.BetAmounts)
+	sb.WriteString(", SymbolTheme: " + c.SymbolTheme)
+	sb.WriteString(", PayoutTable: " + c.PayoutTable)
+	fmt.Fprintf(&sb, ", Machines: %v", c.Machines)
+	sb.WriteString("}")
+
+	return sb.String()
 }
 
-// newConfig creates a new Config instance by reading from the configuration file.
-func newConfig() *Config {
-	return readConfigFromFile()
+// GetConfig retrieves the configuration for the slots game on the guild. If the configuration
+// does not exist, then a new one is created.
+func GetConfig(guildID string) *Config {
+	config := readConfig(guildID)
+	if config == nil {
+		config = readConfigFromFile(guildID)
+	}
+	if len(config.Machines) == 0 {
+		config.Machines = []*Machine{newDefaultMachine(config)}
+		writeConfig(config)
+		slog.Debug("add default slot machine", slog.String("guildID", guildID), slog.String("machine", config.Machines[0].Name))
+	}
+	return config
 }
 
-// readConfigFromFile reads the configuration from a JSON file and returns a Config instance.
-func readConfigFromFile() *Config {
+// readConfigFromFile creates a new configuration for the guild using the defaults in the
+// configuration file, and writes it to the database. If the file can't be read, then the
+// built-in defaults are used.
+func readConfigFromFile(guildID string) *Config {
 	configFileName := filepath.Join(discord.ConfigDir, "slots", "config", ConfigFileName+".json")
-	bytes, _ := os.ReadFile(configFileName)
-
-	var config Config
-	_ = json.Unmarshal(bytes, &config)
+	config := &Config{}
+	bytes, err := os.ReadFile(configFileName)
+	if err == nil {
+		err = json.Unmarshal(bytes, config)
+	}
+	if err != nil {
+		slog.Error("failed to read slots config",
+			slog.String("guildID", guildID),
+			slog.String("file", configFileName),
+			slog.Any("error", err),
+		)
+		config = &Config{}
+	}
 	config.Cooldown *= time.Second
+	config.GuildID = guildID
+	if len(config.BetAmounts) == 0 {
+		config.BetAmounts = slices.Clone(defaultBetAmounts)
+	}
+	if config.SymbolTheme == "" {
+		config.SymbolTheme = slotsTheme
+	}
+	if config.PayoutTable == "" {
+		config.PayoutTable = PayoutFileName
+	}
+
+	writeConfig(config)
+	slog.Debug("create new slots config", slog.String("guildID", guildID))
+
+	return config
+}
+
+// IsValidBet returns whether the bet is one of the bet amounts allowed on the guild.
+func (c *Config) IsValidBet(bet int) bool {
+	return slices.Contains(c.BetAmounts, bet)
+}
+
+// formatBetAmounts returns the allowed bet amounts as a comma-separated list.
+func formatBetAmounts(betAmounts []int) string {
+	amounts := make([]string, 0, len(betAmounts))
+	for _, amount := range betAmounts {
+		amounts = append(amounts, strconv.Itoa(amount))
+	}
+	return strings.Join(amounts, ", ")
+}
 
-	return &config
+// parseBetAmounts parses a comma-separated list of bet amounts. The amounts are returned
+// sorted from smallest to largest with any duplicates removed.
+func parseBetAmounts(value string) ([]int, error) {
+	betAmounts := make([]int, 0)
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		amount, err := strconv.Atoi(field)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("invalid bet amount %q", field)
+		}
+		betAmounts = append(betAmounts, amount)
+	}
+	if len(betAmounts) == 0 {
+		return nil, fmt.Errorf("no bet amounts were given")
+	}
+	slices.Sort(betAmounts)
+	return slices.Compact(betAmounts), nil
 }
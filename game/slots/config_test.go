@@ -0,0 +1,56 @@
+package slots
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseBetAmounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []int
+		wantErr bool
+	}{
+		{name: "single", value: "100", want: []int{100}},
+		{name: "sorted", value: "500, 100,300", want: []int{100, 300, 500}},
+		{name: "duplicates", value: "100,100,200", want: []int{100, 200}},
+		{name: "empty fields", value: "100,,200,", want: []int{100, 200}},
+		{name: "empty", value: "", wantErr: true},
+		{name: "zero", value: "0,100", wantErr: true},
+		{name: "negative", value: "-100", wantErr: true},
+		{name: "not a number", value: "100,abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBetAmounts(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseBetAmounts(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseBetAmounts(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigIsValidBet(t *testing.T) {
+	config := &Config{BetAmounts: []int{100, 300, 500}}
+
+	if !config.IsValidBet(300) {
+		t.Error("Config.IsValidBet(300) = false, want true")
+	}
+
+	if config.IsValidBet(200) {
+		t.Error("Config.IsValidBet(200) = true, want false")
+	}
+}
+
+func TestFormatBetAmounts(t *testing.T) {
+	got := formatBetAmounts([]int{100, 300, 500})
+	if got != "100, 300, 500" {
+		t.Errorf("formatBetAmounts() = %q, want %q", got, "100, 300, 500")
+	}
+}
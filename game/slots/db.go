@@ -8,9 +8,45 @@ import (
 )
 
 const (
-	MemberCollection = "slots_members"
+	ConfigCollection        = "slots_configs"
+	MemberCollection        = "slots_members"
+	MachineMemberCollection = "slots_machine_members"
+	SpinCollection          = "slots_spins"
 )
 
+// readConfig loads the slots configuration from the database. If it does not exist, then
+// a `nil` value is returned.
+func readConfig(guildID string) *Config {
+	var config Config
+	filter := bson.M{"guild_id": guildID}
+	err := db.FindOne(ConfigCollection, filter, &config)
+	if err != nil {
+		slog.Debug("slots configuration not found in the database",
+			slog.String("guildID", guildID),
+			slog.Any("error", err),
+		)
+		return nil
+	}
+
+	return &config
+}
+
+// writeConfig creates or updates the slots configuration in the database.
+func writeConfig(config *Config) {
+	var filter bson.M
+	if config.ID != bson.NilObjectID {
+		filter = bson.M{"_id": config.ID}
+	} else {
+		filter = bson.M{"guild_id": config.GuildID}
+	}
+	if err := db.UpdateOrInsert(ConfigCollection, filter, config); err != nil {
+		slog.Error("error writing slots configuration to the database",
+			slog.String("guildID", config.GuildID),
+			slog.Any("error", err),
+		)
+	}
+}
+
 // readMember loads the slots member from the database. If it does not exist, then
 // a `nil` value is returned.
 func readMember(guildID string, memberID string) *Member {
@@ -29,18 +65,60 @@ func readMember(guildID string, memberID string) *Member {
 	return &member
 }
 
-// Write creates or updates the slots member in the database
+// readMachineMember loads the slots member's statistics for a machine from the database. If they do
+// not exist, then a `nil` value is returned.
+func readMachineMember(guildID string, memberID string, machine string) *Member {
+	var member Member
+	filter := bson.M{"guild_id": guildID, "member_id": memberID, "machine": machine}
+	err := db.FindOne(MachineMemberCollection, filter, &member)
+	if err != nil {
+		slog.Debug("slots machine member not found in the database",
+			slog.String("guildID", guildID),
+			slog.String("memberID", memberID),
+			slog.String("machine", machine),
+			slog.Any("error", err),
+		)
+		return nil
+	}
+
+	return &member
+}
+
+// Write creates or updates the slots member in the database. Statistics for a single machine are
+// kept in their own collection, so they aren't counted in the overall statistics.
 func writeMember(member *Member) {
+	collection := MemberCollection
+	if member.Machine != "" {
+		collection = MachineMemberCollection
+	}
+
 	var filter bson.M
-	if member.ID != bson.NilObjectID {
+	switch {
+	case member.ID != bson.NilObjectID:
 		filter = bson.M{"_id": member.ID}
-	} else {
+	case member.Machine != "":
+		filter = bson.M{"guild_id": member.GuildID, "member_id": member.MemberID, "machine": member.Machine}
+	default:
 		filter = bson.M{"guild_id": member.GuildID, "member_id": member.MemberID}
 	}
-	if err := db.UpdateOrInsert(MemberCollection, filter, member); err != nil {
+	if err := db.UpdateOrInsert(collection, filter, member); err != nil {
 		slog.Error("error writing slots member to the database",
 			slog.String("guildID", member.GuildID),
 			slog.String("memberID", member.MemberID),
+			slog.String("machine", member.Machine),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// writeSpinRecord stores the outcome of a spin in the database.
+func writeSpinRecord(record *SpinRecord) {
+	filter := bson.M{"_id": record.ID}
+	if err := db.UpdateOrInsert(SpinCollection, filter, record); err != nil {
+		slog.Error("error writing slots spin to the database",
+			slog.String("guildID", record.GuildID),
+			slog.String("memberID", record.MemberID),
+			slog.Int64("seed", record.Seed),
 			slog.Any("error", err),
 		)
 	}
@@ -64,7 +142,56 @@ type PayoutAverages struct {
 // GetPayoutAverages uses an aggregation pipeline to calculate comprehensive payout statistics
 // across all slots members in a guild
 func GetPayoutAverages(guildID string) (*PayoutAverages, error) {
-	pipeline := mongo.Pipeline{
+	pipeline := payoutAveragesPipeline(guildID, nil)
+
+	docs, err := db.Aggregate(MemberCollection, pipeline)
+	if err != nil {
+		slog.Error("failed to get payout averages",
+			slog.String("guildID", guildID),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		slog.Debug("no slots data found for guild",
+			slog.String("guildID", guildID),
+		)
+		// Return zero values if no data found
+		return &PayoutAverages{}, nil
+	}
+
+	return newPayoutAverages(docs[0]), nil
+}
+
+// GetMachinePayoutAverages calculates the same payout statistics as GetPayoutAverages, broken down
+// by the slot machine that was played. The statistics are returned keyed by the name of the machine.
+func GetMachinePayoutAverages(guildID string) (map[string]*PayoutAverages, error) {
+	pipeline := payoutAveragesPipeline(guildID, "$machine")
+
+	docs, err := db.Aggregate(MachineMemberCollection, pipeline)
+	if err != nil {
+		slog.Error("failed to get machine payout averages",
+			slog.String("guildID", guildID),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+
+	averages := make(map[string]*PayoutAverages, len(docs))
+	for _, doc := range docs {
+		machine, _ := doc["_id"].(string)
+		averages[machine] = newPayoutAverages(doc)
+	}
+
+	return averages, nil
+}
+
+// payoutAveragesPipeline returns the aggregation pipeline that calculates the payout statistics
+// for the slots members in a guild, grouping the members by the given expression. A `nil` group
+// combines all members into a single result.
+func payoutAveragesPipeline(guildID string, groupBy any) mongo.Pipeline {
+	return mongo.Pipeline{
 		// Stage 1: Match documents for the specific guild
 		bson.D{
 			{Key: "$match", Value: bson.D{
@@ -127,10 +254,10 @@ func GetPayoutAverages(guildID string) (*PayoutAverages, error) {
 				}},
 			}},
 		},
-		// Stage 3: Group all documents and calculate averages
+		// Stage 3: Group the documents and calculate averages
 		bson.D{
 			{Key: "$group", Value: bson.D{
-				{Key: "_id", Value: nil},
+				{Key: "_id", Value: groupBy},
 				{Key: "average_total_wins", Value: bson.D{
 					{Key: "$avg", Value: "$total_wins"},
 				}},
@@ -167,26 +294,10 @@ func GetPayoutAverages(guildID string) (*PayoutAverages, error) {
 			}},
 		},
 	}
+}
 
-	docs, err := db.Aggregate(MemberCollection, pipeline)
-	if err != nil {
-		slog.Error("failed to get payout averages",
-			slog.String("guildID", guildID),
-			slog.Any("error", err),
-		)
-		return nil, err
-	}
-
-	if len(docs) == 0 {
-		slog.Debug("no slots data found for guild",
-			slog.String("guildID", guildID),
-		)
-		// Return zero values if no data found
-		return &PayoutAverages{}, nil
-	}
-
-	result := docs[0]
-
+// newPayoutAverages extracts the payout statistics from the result of the aggregation pipeline.
+func newPayoutAverages(result bson.M) *PayoutAverages {
 	// Extract values with proper type handling and defaults
 	averages := &PayoutAverages{
 		AverageTotalWins:        getFloatFromResult(result, "average_total_wins"),
@@ -201,9 +312,11 @@ func GetPayoutAverages(guildID string) (*PayoutAverages, error) {
 		AverageMaxWinningStreak: getFloatFromResult(result, "average_max_winning_streak"),
 		AverageMaxLosingStreak:  getFloatFromResult(result, "average_max_losing_streak"),
 	}
-	averages.AverageReturns = float64(averages.TotalWon) / float64(averages.TotalBet) * 100.0
+	if averages.TotalBet > 0 {
+		averages.AverageReturns = float64(averages.TotalWon) / float64(averages.TotalBet) * 100.0
+	}
 
-	return averages, nil
+	return averages
 }
 
 // Helper function to safely extract float64 values from aggregation results
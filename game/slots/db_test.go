@@ -120,3 +120,36 @@ func TestHelperFunctions(t *testing.T) {
 		t.Errorf("getInt64FromResult(nonexistent) = %v, want 0", intResult)
 	}
 }
+
+func TestNewPayoutAverages(t *testing.T) {
+	result := map[string]interface{}{
+		"average_total_wins": float64(4.5),
+		"total_wins":         int64(9),
+		"total_losses":       int32(11),
+		"total_bet":          int64(2000),
+		"total_won":          int64(1900),
+	}
+
+	averages := newPayoutAverages(result)
+	if averages.AverageTotalWins != 4.5 {
+		t.Errorf("newPayoutAverages().AverageTotalWins = %v, want 4.5", averages.AverageTotalWins)
+	}
+
+	if averages.TotalWins != 9 {
+		t.Errorf("newPayoutAverages().TotalWins = %v, want 9", averages.TotalWins)
+	}
+
+	if averages.TotalLosses != 11 {
+		t.Errorf("newPayoutAverages().TotalLosses = %v, want 11", averages.TotalLosses)
+	}
+
+	if averages.AverageReturns != 95.0 {
+		t.Errorf("newPayoutAverages().AverageReturns = %v, want 95.0", averages.AverageReturns)
+	}
+
+	// Without any bets, the returns should be zero rather than NaN
+	averages = newPayoutAverages(map[string]interface{}{})
+	if averages.AverageReturns != 0 {
+		t.Errorf("newPayoutAverages().AverageReturns = %v, want 0", averages.AverageReturns)
+	}
+}
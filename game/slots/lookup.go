@@ -14,24 +14,25 @@ const (
 	LookupFileName = "lookup"
 )
 
-// GetLookupTable retrieves the lookup table for the specified guild.
-func GetLookupTable() rslots.LookupTable {
-	lookupTable := newLookupTable()
+// GetLookupTable retrieves the lookup table with the given name. If the lookup table can't be
+// read, then a `nil` value is returned.
+func GetLookupTable(name string) rslots.LookupTable {
+	lookupTable := newLookupTable(name)
 	return lookupTable
 }
 
-// newLookupTable creates a new lookup table for the specified guild by reading from a configuration file.
-func newLookupTable() rslots.LookupTable {
-	lookupTable := readLookupTableFromFile()
+// newLookupTable creates a new lookup table by reading it from a configuration file.
+func newLookupTable(name string) rslots.LookupTable {
+	lookupTable := readLookupTableFromFile(name)
 	return lookupTable
 }
 
 // readLookupTableFromFile reads the lookup table from a JSON configuration file.
-// The file is expected to be located at DISCORD_CONFIG_DIR/slots/lookuptable/lookup.json
+// The file is expected to be located at DISCORD_CONFIG_DIR/slots/lookuptable/<name>.json
 // and contain an array of reels, where each reel is an object with a "Slots" field
 // that is an array of slot symbols.
-func readLookupTableFromFile() rslots.LookupTable {
-	configFileName := filepath.Join(discord.ConfigDir, "slots", "lookuptable", LookupFileName+".json")
+func readLookupTableFromFile(name string) rslots.LookupTable {
+	configFileName := filepath.Join(discord.ConfigDir, "slots", "lookuptable", name+".json")
 	bytes, err := os.ReadFile(configFileName)
 	if err != nil {
 		return nil
@@ -43,7 +44,7 @@ func readLookupTableFromFile() rslots.LookupTable {
 		return nil
 	}
 
-	slog.Debug("create new lookup table")
+	slog.Debug("create new lookup table", slog.String("name", name))
 
 	return lookupTable
 }
@@ -0,0 +1,167 @@
+package slots
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rslots "github.com/rbrabson/slots"
+)
+
+const (
+	defaultMachineName = "Classic"
+	maxMachines        = 25
+	rtpTolerance       = 1.0
+)
+
+// Machine is a slot machine defined by the admins of a guild. Each machine has its own lookup
+// and payout tables, which determine how volatile it is, and its own range of bets.
+type Machine struct {
+	Name        string  `json:"name" bson:"name"`
+	LookupTable string  `json:"lookup_table" bson:"lookup_table"`
+	PayoutTable string  `json:"payout_table" bson:"payout_table"`
+	MinBet      int     `json:"min_bet" bson:"min_bet"`
+	MaxBet      int     `json:"max_bet" bson:"max_bet"`
+	TargetRTP   float64 `json:"target_rtp" bson:"target_rtp"`
+}
+
+// String returns a string representation of the Machine.
+func (m *Machine) String() string {
+	sb := strings.Builder{}
+	sb.WriteString("Machine{")
+	sb.WriteString("Name: " + m.Name)
+	sb.WriteString(", LookupTable: " + m.LookupTable)
+	sb.WriteString(", PayoutTable: " + m.PayoutTable)
+	fmt.Fprintf(&sb, ", MinBet: %d", m.MinBet)
+	fmt.Fprintf(&sb, ", MaxBet: %d", m.MaxBet)
+	fmt.Fprintf(&sb, ", TargetRTP: %.2f", m.TargetRTP)
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// newDefaultMachine returns the machine used by a guild that hasn't defined any machines. It uses
+// the default lookup table, the payout table in the guild's configuration, and allows all the
+// guild's bet amounts.
+func newDefaultMachine(config *Config) *Machine {
+	payoutTable := config.PayoutTable
+	if payoutTable == "" {
+		payoutTable = PayoutFileName
+	}
+	machine := &Machine{
+		Name:        defaultMachineName,
+		LookupTable: LookupFileName,
+		PayoutTable: payoutTable,
+	}
+	if len(config.BetAmounts) > 0 {
+		machine.MinBet = slices.Min(config.BetAmounts)
+		machine.MaxBet = slices.Max(config.BetAmounts)
+	}
+
+	return machine
+}
+
+// IsInRange returns whether the bet is within the range of bets allowed on the machine.
+func (m *Machine) IsInRange(bet int) bool {
+	return bet >= m.MinBet && bet <= m.MaxBet
+}
+
+// allowedBets returns the bet amounts that may be placed on the machine.
+func (m *Machine) allowedBets(betAmounts []int) []int {
+	bets := make([]int, 0, len(betAmounts))
+	for _, bet := range betAmounts {
+		if m.IsInRange(bet) {
+			bets = append(bets, bet)
+		}
+	}
+	return bets
+}
+
+// GetMachine returns the machine with the given name, ignoring case. If no name is given, then
+// the first machine is returned. If the machine doesn't exist, then a `nil` value is returned.
+func (c *Config) GetMachine(name string) *Machine {
+	if name == "" {
+		if len(c.Machines) == 0 {
+			return nil
+		}
+		return c.Machines[0]
+	}
+	for _, machine := range c.Machines {
+		if strings.EqualFold(machine.Name, name) {
+			return machine
+		}
+	}
+	return nil
+}
+
+// AddMachine adds the machine to the guild, replacing any machine with the same name.
+func (c *Config) AddMachine(machine *Machine) error {
+	for idx, m := range c.Machines {
+		if strings.EqualFold(m.Name, machine.Name) {
+			c.Machines[idx] = machine
+			return nil
+		}
+	}
+	if len(c.Machines) >= maxMachines {
+		return fmt.Errorf("a server can't have more than %d slot machines", maxMachines)
+	}
+	c.Machines = append(c.Machines, machine)
+	return nil
+}
+
+// RemoveMachine removes the machine with the given name from the guild. The last machine can't
+// be removed, as members need a machine to play.
+func (c *Config) RemoveMachine(name string) error {
+	idx := slices.IndexFunc(c.Machines, func(m *Machine) bool {
+		return strings.EqualFold(m.Name, name)
+	})
+	if idx < 0 {
+		return fmt.Errorf("slot machine %q was not found", name)
+	}
+	if len(c.Machines) == 1 {
+		return fmt.Errorf("the last slot machine can't be removed")
+	}
+	c.Machines = slices.Delete(c.Machines, idx, idx+1)
+	return nil
+}
+
+// PayoutCalculator returns the amount paid for a bet on a payline, along with a description of the
+// win. It is implemented by the payout tables.
+type PayoutCalculator interface {
+	GetPayoutAmount(bet int, payline []string) (int, string)
+}
+
+// calculateRTP returns the percentage of the bet the machine pays back on average, found by
+// checking the payline for every combination of stops on the reels.
+func calculateRTP(lookupTable rslots.LookupTable, payoutTable PayoutCalculator, bet int) float64 {
+	if payoutTable == nil || bet <= 0 {
+		return 0
+	}
+
+	spins, payouts := 0, 0
+	for spin := range AllSpins(lookupTable, payoutTable, bet) {
+		payouts += spin.Payout
+		spins++
+	}
+	if spins == 0 {
+		return 0
+	}
+
+	return float64(payouts) / float64(spins*bet) * 100
+}
+
+// calculateRTPRange returns the lowest and highest return to player across the given bets. The
+// payout tables may round payouts or pay fixed amounts, so the return can differ for each bet.
+func calculateRTPRange(lookupTable rslots.LookupTable, payoutTable PayoutCalculator, bets []int) (float64, float64) {
+	if len(bets) == 0 {
+		return 0, 0
+	}
+	low := calculateRTP(lookupTable, payoutTable, bets[0])
+	high := low
+	for _, bet := range bets[1:] {
+		rtp := calculateRTP(lookupTable, payoutTable, bet)
+		low = min(low, rtp)
+		high = max(high, rtp)
+	}
+	return low, high
+}
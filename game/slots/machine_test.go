@@ -0,0 +1,190 @@
+package slots
+
+import (
+	"slices"
+	"testing"
+
+	rslots "github.com/rbrabson/slots"
+)
+
+func TestNewDefaultMachine(t *testing.T) {
+	config := &Config{
+		BetAmounts:  []int{300, 100, 500},
+		PayoutTable: "payout",
+	}
+
+	machine := newDefaultMachine(config)
+	if machine.Name != defaultMachineName {
+		t.Errorf("newDefaultMachine().Name = %q, want %q", machine.Name, defaultMachineName)
+	}
+	if machine.LookupTable != LookupFileName {
+		t.Errorf("newDefaultMachine().LookupTable = %q, want %q", machine.LookupTable, LookupFileName)
+	}
+	if machine.PayoutTable != "payout" {
+		t.Errorf("newDefaultMachine().PayoutTable = %q, want %q", machine.PayoutTable, "payout")
+	}
+	if machine.MinBet != 100 || machine.MaxBet != 500 {
+		t.Errorf("newDefaultMachine() bets = %d to %d, want 100 to 500", machine.MinBet, machine.MaxBet)
+	}
+}
+
+func TestMachineAllowedBets(t *testing.T) {
+	machine := &Machine{Name: "Penny Slots", MinBet: 10, MaxBet: 100}
+
+	got := machine.allowedBets([]int{10, 50, 100, 300, 500})
+	want := []int{10, 50, 100}
+	if !slices.Equal(got, want) {
+		t.Errorf("Machine.allowedBets() = %v, want %v", got, want)
+	}
+
+	if machine.IsInRange(300) {
+		t.Error("Machine.IsInRange(300) = true, want false")
+	}
+}
+
+func TestConfigGetMachine(t *testing.T) {
+	config := &Config{
+		Machines: []*Machine{
+			{Name: "Penny Slots"},
+			{Name: "High Roller"},
+		},
+	}
+
+	if machine := config.GetMachine(""); machine == nil || machine.Name != "Penny Slots" {
+		t.Errorf("Config.GetMachine(\"\") = %v, want Penny Slots", machine)
+	}
+
+	if machine := config.GetMachine("high roller"); machine == nil || machine.Name != "High Roller" {
+		t.Errorf("Config.GetMachine(\"high roller\") = %v, want High Roller", machine)
+	}
+
+	if machine := config.GetMachine("Jackpot"); machine != nil {
+		t.Errorf("Config.GetMachine(\"Jackpot\") = %v, want nil", machine)
+	}
+}
+
+func TestConfigAddMachine(t *testing.T) {
+	config := &Config{
+		Machines: []*Machine{
+			{Name: "Penny Slots", MaxBet: 100},
+		},
+	}
+
+	if err := config.AddMachine(&Machine{Name: "High Roller", MaxBet: 5000}); err != nil {
+		t.Errorf("Config.AddMachine() error = %v, want nil", err)
+	}
+	if len(config.Machines) != 2 {
+		t.Errorf("len(Config.Machines) = %d, want 2", len(config.Machines))
+	}
+
+	if err := config.AddMachine(&Machine{Name: "penny slots", MaxBet: 50}); err != nil {
+		t.Errorf("Config.AddMachine() error = %v, want nil", err)
+	}
+	if len(config.Machines) != 2 {
+		t.Errorf("len(Config.Machines) = %d, want 2", len(config.Machines))
+	}
+	if config.Machines[0].MaxBet != 50 {
+		t.Errorf("Config.Machines[0].MaxBet = %d, want 50", config.Machines[0].MaxBet)
+	}
+
+	config.Machines = make([]*Machine, 0, maxMachines)
+	for range maxMachines {
+		config.Machines = append(config.Machines, &Machine{})
+	}
+	if err := config.AddMachine(&Machine{Name: "One Too Many"}); err == nil {
+		t.Error("Config.AddMachine() error = nil, want an error when there are too many machines")
+	}
+}
+
+func TestConfigRemoveMachine(t *testing.T) {
+	config := &Config{
+		Machines: []*Machine{
+			{Name: "Penny Slots"},
+			{Name: "High Roller"},
+		},
+	}
+
+	if err := config.RemoveMachine("Jackpot"); err == nil {
+		t.Error("Config.RemoveMachine(\"Jackpot\") error = nil, want an error")
+	}
+
+	if err := config.RemoveMachine("penny slots"); err != nil {
+		t.Errorf("Config.RemoveMachine(\"penny slots\") error = %v, want nil", err)
+	}
+	if len(config.Machines) != 1 || config.Machines[0].Name != "High Roller" {
+		t.Errorf("Config.Machines = %v, want only High Roller", config.Machines)
+	}
+
+	if err := config.RemoveMachine("High Roller"); err == nil {
+		t.Error("Config.RemoveMachine() error = nil, want an error when removing the last machine")
+	}
+}
+
+func TestCalculateRTPWithoutTables(t *testing.T) {
+	if rtp := calculateRTP(nil, nil, 100); rtp != 0 {
+		t.Errorf("calculateRTP(nil) = %v, want 0", rtp)
+	}
+
+	lookupTable := rslots.LookupTable{{"blank"}, {}, {"blank"}}
+	if rtp := calculateRTP(lookupTable, nil, 100); rtp != 0 {
+		t.Errorf("calculateRTP(empty reel) = %v, want 0", rtp)
+	}
+
+	lookupTable = rslots.LookupTable{{"blank"}, {"blank"}, {"blank"}}
+	if rtp := calculateRTP(lookupTable, nil, 0); rtp != 0 {
+		t.Errorf("calculateRTP(bet 0) = %v, want 0", rtp)
+	}
+}
+
+// payoutFunc is a payout table used to test the return to player.
+type payoutFunc func(bet int, payline []string) (int, string)
+
+func (f payoutFunc) GetPayoutAmount(bet int, payline []string) (int, string) {
+	return f(bet, payline)
+}
+
+func TestCalculateRTP(t *testing.T) {
+	// Three cherries pay 10 times the bet, and two cherries on the first two reels pay twice the bet.
+	payoutTable := payoutFunc(func(bet int, payline []string) (int, string) {
+		switch {
+		case payline[0] == "cherry" && payline[1] == "cherry" && payline[2] == "cherry":
+			return bet * 10, "three cherries"
+		case payline[0] == "cherry" && payline[1] == "cherry":
+			return bet * 2, "two cherries"
+		default:
+			return 0, ""
+		}
+	})
+
+	// One of the 8 paylines has three cherries and one has two, so 12 bets are paid for every 8 placed.
+	lookupTable := rslots.LookupTable{{"cherry", "bar"}, {"cherry", "bar"}, {"cherry", "bar"}}
+	if rtp := calculateRTP(lookupTable, payoutTable, 100); rtp != 150 {
+		t.Errorf("calculateRTP() = %v, want 150", rtp)
+	}
+
+	// With two blanks added to the first reel, there are 16 paylines, but the same 12 bets are paid.
+	lookupTable = rslots.LookupTable{{"cherry", "bar", "blank", "blank"}, {"cherry", "bar"}, {"cherry", "bar"}}
+	if rtp := calculateRTP(lookupTable, payoutTable, 100); rtp != 75 {
+		t.Errorf("calculateRTP(longer reel) = %v, want 75", rtp)
+	}
+}
+
+func TestCalculateRTPRange(t *testing.T) {
+	// Three cherries pay a fixed 500, so the return falls as the bet grows.
+	payoutTable := payoutFunc(func(bet int, payline []string) (int, string) {
+		if payline[0] == "cherry" && payline[1] == "cherry" && payline[2] == "cherry" {
+			return 500, "three cherries"
+		}
+		return 0, ""
+	})
+	lookupTable := rslots.LookupTable{{"cherry", "bar"}, {"cherry", "bar"}, {"cherry", "bar"}}
+
+	low, high := calculateRTPRange(lookupTable, payoutTable, []int{100, 250, 50})
+	if low != 25 || high != 125 {
+		t.Errorf("calculateRTPRange() = %v, %v, want 25, 125", low, high)
+	}
+
+	if low, high := calculateRTPRange(lookupTable, payoutTable, nil); low != 0 || high != 0 {
+		t.Errorf("calculateRTPRange(no bets) = %v, %v, want 0, 0", low, high)
+	}
+}
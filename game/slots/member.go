@@ -8,11 +8,13 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// Member represents a member's statistics for the slots game.
+// Member represents a member's statistics for the slots game. The statistics across all machines
+// have no machine name, while those for a single machine include the name of the machine.
 type Member struct {
 	ID                  bson.ObjectID `json:"id" bson:"_id,omitempty"`
 	GuildID             string        `json:"guild_id" bson:"guild_id"`
 	MemberID            string        `json:"member_id" bson:"member_id"`
+	Machine             string        `json:"machine,omitempty" bson:"machine,omitempty"`
 	CurrentWinStreak    int           `json:"current_win_streak" bson:"current_win_streak"`
 	LongestWinStreak    int           `json:"longest_win_streak" bson:"longest_win_streak"`
 	CurrentLosingStreak int           `json:"current_losing_streak" bson:"current_losing_streak"`
@@ -35,6 +37,16 @@ func GetMember(guildID, userID string) *Member {
 	return member
 }
 
+// GetMachineMember retrieves the member statistics on a single machine for a specific guild and user.
+// If the member has not played the machine, a new member is created and returned.
+func GetMachineMember(guildID, userID, machine string) *Member {
+	member := readMachineMember(guildID, userID, machine)
+	if member == nil {
+		member = newMachineMember(guildID, userID, machine)
+	}
+	return member
+}
+
 // newMember creates a new Member instance with default values and writes it to the database.
 func newMember(guildID, userID string) *Member {
 	member := &Member{
@@ -46,6 +58,18 @@ func newMember(guildID, userID string) *Member {
 	return member
 }
 
+// newMachineMember creates a new Member instance for the machine with default values and writes it to the database.
+func newMachineMember(guildID, userID, machine string) *Member {
+	member := &Member{
+		GuildID:  guildID,
+		MemberID: userID,
+		Machine:  machine,
+	}
+	writeMember(member)
+
+	return member
+}
+
 // IsInCooldown checks if the member is in cooldown. If not, it updates the LastPlayed time and returns false.
 // If the member is in cooldown, it returns true.
 func (m *Member) IsInCooldown(config *Config) bool {
@@ -66,8 +90,22 @@ func (m *Member) GetCooldownRemaining(config *Config) time.Duration {
 	return remaining
 }
 
-// AddResults updates the member's statistics based on the results of a spin.
-func (m *Member) AddResults(spinResult *rslots.SpinResult) {
+// AddResults updates the member's statistics, both overall and for the machine that was played, based
+// on the results of a spin.
+func (m *Member) AddResults(machine string, spinResult *rslots.SpinResult) {
+	m.addSpin(spinResult)
+	writeMember(m)
+
+	machineMember := GetMachineMember(m.GuildID, m.MemberID, machine)
+	machineMember.addSpin(spinResult)
+	writeMember(machineMember)
+
+	memberIDs := []string{m.MemberID}
+	stats.UpdateGameStats(m.GuildID, "slots", memberIDs)
+}
+
+// addSpin adds the results of a spin to the member's statistics.
+func (m *Member) addSpin(spinResult *rslots.SpinResult) {
 	m.TotalBet += spinResult.Bet
 	if spinResult.Payout > 0 {
 		m.TotalWinnings += spinResult.Payout
@@ -82,9 +120,4 @@ func (m *Member) AddResults(spinResult *rslots.SpinResult) {
 		m.LongestLosingStreak = max(m.LongestLosingStreak, m.CurrentLosingStreak)
 		m.CurrentWinStreak = 0
 	}
-
-	writeMember(m)
-
-	memberIDs := []string{m.MemberID}
-	stats.UpdateGameStats(m.GuildID, "slots", memberIDs)
 }
@@ -15,9 +15,10 @@ const (
 	PayoutFileName = "payout"
 )
 
-// GetPayoutTable retrieves the payout table for a specific guild.
-func GetPayoutTable() rslots.PayoutTable {
-	pt := newPayoutTable()
+// GetPayoutTable retrieves the payout table with the given name. If the payout table can't be
+// read, then a `nil` value is returned.
+func GetPayoutTable(name string) rslots.PayoutTable {
+	pt := newPayoutTable(name)
 	slices.SortFunc(pt, func(a, b rslots.PayoutAmount) int {
 		if a.Bet != b.Bet {
 			return a.Bet - b.Bet
@@ -35,18 +36,18 @@ func GetPayoutTable() rslots.PayoutTable {
 	return pt
 }
 
-// newPayoutTable creates a new payout table for a specific guild by reading from a file.
-func newPayoutTable() rslots.PayoutTable {
-	payoutTable := readPayoutTableFromFile()
+// newPayoutTable creates a new payout table by reading it from a file.
+func newPayoutTable(name string) rslots.PayoutTable {
+	payoutTable := readPayoutTableFromFile(name)
 	return payoutTable
 }
 
-// readPayoutTableFromFile reads the payout table from a JSON file.
-func readPayoutTableFromFile() rslots.PayoutTable {
-	configFileName := filepath.Join(discord.ConfigDir, "slots", "payout", PayoutFileName+".json")
+// readPayoutTableFromFile reads the payout table with the given name from a JSON file.
+func readPayoutTableFromFile(name string) rslots.PayoutTable {
+	configFileName := filepath.Join(discord.ConfigDir, "slots", "payout", name+".json")
 	bytes, err := os.ReadFile(configFileName)
 	if err != nil {
-		slog.Error("failed to read default payout table",
+		slog.Error("failed to read payout table",
 			slog.String("file", configFileName),
 			slog.Any("error", err),
 		)
@@ -64,7 +65,7 @@ func readPayoutTableFromFile() rslots.PayoutTable {
 		return nil
 	}
 
-	slog.Debug("create new payout table")
+	slog.Debug("create new payout table", slog.String("name", name))
 
 	return payouts
 }
@@ -1,34 +1,155 @@
 package slots
 
 import (
+	"iter"
+	"math/rand/v2"
+	"time"
+
 	rslots "github.com/rbrabson/slots"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 // SlotMachine represents a slot machine with a lookup table, payout table, and symbol table.
 type SlotMachine struct {
-	slotMachine rslots.SlotMachine
+	lookupTable rslots.LookupTable
+	payoutTable rslots.PayoutTable
+	machine     *Machine
 	symbols     SymbolTable
 }
 
-// GetSlotMachine returns a new instance of the SlotMachine.
-func GetSlotMachine() *SlotMachine {
-	return newSlotMachine()
+// SpinResult is the result of a spin, along with the seed used to pick where each reel stopped.
+type SpinResult struct {
+	*rslots.SpinResult
+	Seed int64
+}
+
+// SpinRecord is the saved outcome of a spin. The seed used to pick where each reel stopped is saved
+// with it, so the spin may be reproduced if the result is disputed.
+type SpinRecord struct {
+	ID       bson.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	GuildID  string        `json:"guild_id" bson:"guild_id"`
+	MemberID string        `json:"member_id" bson:"member_id"`
+	Machine  string        `json:"machine" bson:"machine"`
+	Seed     int64         `json:"seed" bson:"seed"`
+	Bet      int           `json:"bet" bson:"bet"`
+	Payline  []string      `json:"payline" bson:"payline"`
+	Payout   int           `json:"payout" bson:"payout"`
+	Time     time.Time     `json:"time" bson:"time"`
+}
+
+// GetSlotMachine returns a new instance of the SlotMachine using the lookup and payout tables of the
+// machine and the symbol theme in the guild's configuration.
+func GetSlotMachine(config *Config, machine *Machine) *SlotMachine {
+	return newSlotMachine(config, machine)
 }
 
 // newSlotMachine creates a new instance of the SlotMachine with an initialized lookup table, payout table, and symbol table.
-func newSlotMachine() *SlotMachine {
+func newSlotMachine(config *Config, machine *Machine) *SlotMachine {
 	slotMachine := &SlotMachine{
-		slotMachine: *rslots.NewSlotMachine(
-			rslots.WithLookupTable(GetLookupTable()),
-			rslots.WithPayoutTable(GetPayoutTable()),
-		),
-		symbols: GetSymbolTable(),
+		lookupTable: GetLookupTable(machine.LookupTable),
+		payoutTable: GetPayoutTable(machine.PayoutTable),
+		machine:     machine,
+		symbols:     GetSymbolTable(config.SymbolTheme),
 	}
 
 	return slotMachine
 }
 
-// Spin spins the slot machine with the given bet and returns the result.
-func (sm *SlotMachine) Spin(bet int) *rslots.SpinResult {
-	return sm.slotMachine.Spin(bet)
+// Spin spins the slot machine with the given bet and returns the result. A new seed is used for
+// each spin.
+func (sm *SlotMachine) Spin(bet int) *SpinResult {
+	return sm.spin(bet, rand.Int64())
+}
+
+// spin spins the slot machine with the given bet, picking where each reel stops from a generator
+// created from the seed. Spinning again with the same seed produces the same result.
+func (sm *SlotMachine) spin(bet int, seed int64) *SpinResult {
+	rng := newSpinRand(seed)
+	stops := make([]int, len(sm.lookupTable))
+	for idx, reel := range sm.lookupTable {
+		if len(reel) > 0 {
+			stops[idx] = rng.IntN(len(reel))
+		}
+	}
+	return &SpinResult{SpinResult: SpinAt(sm.lookupTable, sm.payoutTable, bet, stops), Seed: seed}
+}
+
+// SpinAt returns the result of a spin in which each reel stopped at the given position. Every spin
+// of a machine, and every outcome checked when calculating its return to player, goes through this
+// function, so they pay the same. Empty reels are skipped.
+func SpinAt(lookupTable rslots.LookupTable, payoutTable PayoutCalculator, bet int, stops []int) *rslots.SpinResult {
+	result := &rslots.SpinResult{
+		Bet:        bet,
+		TopLine:    make([]string, 0, len(lookupTable)),
+		Payline:    make([]string, 0, len(lookupTable)),
+		BottomLine: make([]string, 0, len(lookupTable)),
+	}
+	for idx, reel := range lookupTable {
+		if len(reel) == 0 {
+			continue
+		}
+		stop := stops[idx]
+		result.TopLine = append(result.TopLine, reel[(stop+len(reel)-1)%len(reel)])
+		result.Payline = append(result.Payline, reel[stop])
+		result.BottomLine = append(result.BottomLine, reel[(stop+1)%len(reel)])
+	}
+	result.Payout, result.Message = payoutTable.GetPayoutAmount(bet, result.Payline)
+
+	return result
+}
+
+// AllSpins returns every possible spin of the reels with the given bet, one for each combination of
+// stops. If a reel is empty, there are no spins.
+func AllSpins(lookupTable rslots.LookupTable, payoutTable PayoutCalculator, bet int) iter.Seq[*rslots.SpinResult] {
+	return func(yield func(*rslots.SpinResult) bool) {
+		if len(lookupTable) == 0 {
+			return
+		}
+		for _, reel := range lookupTable {
+			if len(reel) == 0 {
+				return
+			}
+		}
+
+		stops := make([]int, len(lookupTable))
+		for {
+			if !yield(SpinAt(lookupTable, payoutTable, bet, stops)) {
+				return
+			}
+
+			// Advance the stops on the reels like an odometer, stopping once every combination is checked
+			reel := len(stops) - 1
+			for ; reel >= 0; reel-- {
+				stops[reel]++
+				if stops[reel] < len(lookupTable[reel]) {
+					break
+				}
+				stops[reel] = 0
+			}
+			if reel < 0 {
+				return
+			}
+		}
+	}
+}
+
+// newSpinRecord returns the record of a spin by the member on the machine.
+func newSpinRecord(guildID string, memberID string, machine string, spinResult *SpinResult) *SpinRecord {
+	return &SpinRecord{
+		ID:       bson.NewObjectID(),
+		GuildID:  guildID,
+		MemberID: memberID,
+		Machine:  machine,
+		Seed:     spinResult.Seed,
+		Bet:      spinResult.Bet,
+		Payline:  spinResult.Payline,
+		Payout:   spinResult.Payout,
+		Time:     time.Now(),
+	}
+}
+
+// newSpinRand returns a random number generator for a spin. All the stops on the reels are drawn
+// from it, so the spin may be reproduced from the seed.
+func newSpinRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
 }
@@ -0,0 +1,67 @@
+package slots
+
+import (
+	"slices"
+	"testing"
+
+	rslots "github.com/rbrabson/slots"
+)
+
+func TestSpinWithSeed(t *testing.T) {
+	sm := &SlotMachine{
+		lookupTable: rslots.LookupTable{
+			{"cherry", "bar", "blank", "red 7"},
+			{"bar", "cherry", "red 7"},
+			{"blank", "red 7", "bar", "cherry", "bar"},
+		},
+	}
+
+	first := sm.spin(100, 42)
+	second := sm.spin(100, 42)
+	if first.Seed != 42 {
+		t.Errorf("spin().Seed = %d, want 42", first.Seed)
+	}
+	if !slices.Equal(first.Payline, second.Payline) || !slices.Equal(first.TopLine, second.TopLine) || !slices.Equal(first.BottomLine, second.BottomLine) {
+		t.Errorf("spin() with the same seed = %v and %v, want the same result", first.Payline, second.Payline)
+	}
+	if len(first.Payline) != len(sm.lookupTable) {
+		t.Fatalf("len(spin().Payline) = %d, want %d", len(first.Payline), len(sm.lookupTable))
+	}
+
+	// The lines above and below the payline are the symbols next to it on each reel
+	for idx, reel := range sm.lookupTable {
+		adjacent := false
+		for stop := range reel {
+			if reel[stop] == first.Payline[idx] && reel[(stop+len(reel)-1)%len(reel)] == first.TopLine[idx] && reel[(stop+1)%len(reel)] == first.BottomLine[idx] {
+				adjacent = true
+			}
+		}
+		if !adjacent {
+			t.Errorf("reel %d = %s | %s | %s, want adjacent symbols", idx, first.TopLine[idx], first.Payline[idx], first.BottomLine[idx])
+		}
+	}
+}
+
+func TestAllSpins(t *testing.T) {
+	lookupTable := rslots.LookupTable{{"cherry", "bar"}, {"cherry", "bar", "blank"}, {"cherry"}}
+	payoutTable := payoutFunc(func(bet int, payline []string) (int, string) {
+		if slices.Equal(payline, []string{"cherry", "cherry", "cherry"}) {
+			return bet * 10, "three cherries"
+		}
+		return 0, ""
+	})
+
+	spins, wins := 0, 0
+	for spin := range AllSpins(lookupTable, payoutTable, 5) {
+		spins++
+		if spin.Payout > 0 {
+			wins++
+			if spin.Payout != 50 || spin.Message != "three cherries" {
+				t.Errorf("AllSpins() paid %d (%q), want 50 for three cherries", spin.Payout, spin.Message)
+			}
+		}
+	}
+	if spins != 6 || wins != 1 {
+		t.Errorf("AllSpins() = %d spins with %d wins, want 6 spins with 1 win", spins, wins)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/rbrabson/goblin/discord"
 )
 
 var (
-	symbolTable SymbolTable
+	symbolTables     = make(map[string]SymbolTable)
+	symbolTablesLock = sync.Mutex{}
 )
 
 // Symbol represents a slot symbol with a name and an emoji.
@@ -55,23 +57,31 @@ func (st SymbolTable) String() string {
 	return sb.String()
 }
 
-// GetSymbolTable retrieves the symbol table for a specific guild.
-func GetSymbolTable() SymbolTable {
-	if symbolTable == nil {
-		symbolTable = newSymbolTable()
+// GetSymbolTable retrieves the symbol table for the theme. Symbol tables are read once and cached.
+// If the symbol table can't be read, then a `nil` value is returned.
+func GetSymbolTable(theme string) SymbolTable {
+	symbolTablesLock.Lock()
+	defer symbolTablesLock.Unlock()
+	if symbolTable, ok := symbolTables[theme]; ok {
+		return symbolTable
+	}
+
+	symbolTable := newSymbolTable(theme)
+	if symbolTable != nil {
+		symbolTables[theme] = symbolTable
 	}
 	return symbolTable
 }
 
-// GetSymbolNames returns a slice of symbol names in the symbol table.
-func newSymbolTable() SymbolTable {
-	symbols := readSymbolTableFromFile()
+// newSymbolTable creates a new symbol table for the theme.
+func newSymbolTable(theme string) SymbolTable {
+	symbols := readSymbolTableFromFile(theme)
 	return symbols
 }
 
-// readSymbolTableFromFile reads the symbol table from a JSON file.
-func readSymbolTableFromFile() SymbolTable {
-	configFileName := filepath.Join(discord.ConfigDir, "slots", "symbols", slotsTheme+".json")
+// readSymbolTableFromFile reads the symbol table for the theme from a JSON file.
+func readSymbolTableFromFile(theme string) SymbolTable {
+	configFileName := filepath.Join(discord.ConfigDir, "slots", "symbols", theme+".json")
 	bytes, err := os.ReadFile(configFileName)
 	if err != nil {
 		slog.Error("failed to read symbols file",
@@ -96,7 +106,7 @@ func readSymbolTableFromFile() SymbolTable {
 
 	}
 
-	slog.Debug("loaded symbols")
+	slog.Debug("loaded symbols", slog.String("theme", theme))
 
 	return symbolTable
 }
@@ -0,0 +1,36 @@
+package form
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// TextInputValue returns the value of the text input with the given custom ID in a submitted modal.
+func TextInputValue(components []discordgo.MessageComponent, customID string) string {
+	for _, component := range components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, c := range row.Components {
+			if input, ok := c.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
+
+// ParseAmount parses an amount entered by a member, such as "1,000". The unit is used in the error
+// returned when the value isn't a whole number, such as "credits" or "chips".
+func ParseAmount(value string, unit string) (int, error) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	amount, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number of %s", value, unit)
+	}
+	return amount, nil
+}
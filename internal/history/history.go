@@ -0,0 +1,104 @@
+package history
+
+import (
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rbrabson/goblin/internal/unicode"
+)
+
+const (
+	defaultCount   = 5
+	maxCount       = 10
+	maxFieldLength = 1024
+	maxFields      = 25
+	maxEmbedLength = 6000
+)
+
+var minCount = 1.0
+
+// CountOption returns the optional "count" option for a history command. The noun is what is being
+// counted, such as "races" or "rounds".
+func CountOption(noun string) *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionInteger,
+		Name:        "count",
+		Description: "The number of " + noun + " to show.",
+		Required:    false,
+		MinValue:    &minCount,
+		MaxValue:    maxCount,
+	}
+}
+
+// Count returns the number of entries to show in the history, as given by the "count" option.
+func Count(options []*discordgo.ApplicationCommandInteractionDataOption) int {
+	for _, option := range options {
+		if option.Name == "count" {
+			return min(max(int(option.IntValue()), 1), maxCount)
+		}
+	}
+	return defaultCount
+}
+
+// Field returns an embed field for an entry in the history, truncating the value to the length
+// allowed by Discord.
+func Field(name string, value string) *discordgo.MessageEmbedField {
+	return &discordgo.MessageEmbedField{
+		Name:   name,
+		Value:  unicode.Truncate(value, maxFieldLength),
+		Inline: false,
+	}
+}
+
+// Send sends the history to the member who requested it. The game is used when logging a failure,
+// such as "race" or "blackjack".
+func Send(s *discordgo.Session, i *discordgo.InteractionCreate, game string, embed *discordgo.MessageEmbed) {
+	fit(embed)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		slog.Error("failed to send the history", slog.String("game", game), slog.String("guildID", i.GuildID), slog.Any("error", err))
+	}
+}
+
+// fit removes entries from the end of the history until the embed is within the number of fields and
+// the total length allowed by Discord, which rejects the whole message otherwise. The footer notes how
+// many entries were left out.
+func fit(embed *discordgo.MessageEmbed) {
+	footer := ""
+	if embed.Footer != nil {
+		footer = embed.Footer.Text
+	}
+	omitted := 0
+	for len(embed.Fields) > 0 && (len(embed.Fields) > maxFields || embedLength(embed) > maxEmbedLength) {
+		embed.Fields = embed.Fields[:len(embed.Fields)-1]
+		omitted++
+		note := "1 more entry was left out to fit in the message"
+		if omitted > 1 {
+			note = fmt.Sprintf("%d more entries were left out to fit in the message", omitted)
+		}
+		if footer != "" {
+			note = footer + "\n" + note
+		}
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: note}
+	}
+}
+
+// embedLength returns the number of characters in the embed that count towards Discord's limit.
+func embedLength(embed *discordgo.MessageEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return length
+}
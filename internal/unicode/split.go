@@ -11,3 +11,9 @@ func SplitString(s string, n int) (start, end string) {
 	}
 	return s, ""
 }
+
+// Truncate shortens a string to at most `n` Unicode characters
+func Truncate(s string, n int) string {
+	start, _ := SplitString(s, n)
+	return start
+}